@@ -0,0 +1,378 @@
+// Command bpsgen generates the operations bindings and the typed data model
+// accessors from the API description in api/bps-api.json.
+//
+// It is run through go generate from pkg/operations:
+//
+//	go generate ./pkg/operations
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"bps-client-go/pkg/models"
+)
+
+// Spec is the API description document
+type Spec struct {
+	Operations []OpsType      `json:"operations"`
+	Schema     *models.Schema `json:"schema"`
+}
+
+// OpsType is a group of operations bound to one Go type
+type OpsType struct {
+	Type     string   `json:"type"`
+	Receiver string   `json:"receiver"`
+	Methods  []Method `json:"methods"`
+}
+
+// Method is a single API operation
+type Method struct {
+	Name string `json:"name"`
+	// Kind is one of post, get, patch, export or import
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+	// File names the parameter holding the local file of export and
+	// import operations
+	File   string  `json:"file,omitempty"`
+	Params []Param `json:"params"`
+}
+
+// Param is an operation parameter
+type Param struct {
+	Name     string `json:"name"`
+	JSON     string `json:"json,omitempty"`
+	Type     string `json:"type"`
+	Optional bool   `json:"optional,omitempty"`
+	// In is body (default), path for parameters substituted into the
+	// path, or arg for parameters only passed to the client call
+	In string `json:"in,omitempty"`
+}
+
+func (p Param) key() string {
+	if p.JSON != "" {
+		return p.JSON
+	}
+	return p.Name
+}
+
+func (p Param) goType() string {
+	if p.Optional {
+		return "*" + p.Type
+	}
+	return p.Type
+}
+
+func (p Param) inBody() bool {
+	return p.In == "" || p.In == "body"
+}
+
+const header = "// Code generated by bpsgen from api/bps-api.json. DO NOT EDIT.\n\n"
+
+func main() {
+	specPath := flag.String("spec", "api/bps-api.json", "API description document")
+	opsOut := flag.String("ops", "", "output file for the operations bindings")
+	modelsOut := flag.String("models", "", "output file for the data model accessors")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spec := &Spec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		log.Fatalf("parse %s: %v", *specPath, err)
+	}
+
+	if *opsOut != "" {
+		if err := write(*opsOut, genOperations(spec)); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *modelsOut != "" && spec.Schema != nil {
+		if err := write(*modelsOut, genModels(spec.Schema)); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func write(path, src string) error {
+	out, err := format.Source([]byte(src))
+	if err != nil {
+		return fmt.Errorf("format %s: %w\n%s", path, err, src)
+	}
+	return os.WriteFile(path, out, 0o644)
+}
+
+func genOperations(spec *Spec) string {
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteString("package operations\n")
+	for _, t := range spec.Operations {
+		fmt.Fprintf(&b, "\ntype %s struct {\n\tClient ClientWrapper\n}\n", t.Type)
+		for _, m := range t.Methods {
+			genMethod(&b, t, m)
+		}
+	}
+	return b.String()
+}
+
+func genMethod(b *strings.Builder, t OpsType, m Method) {
+	var args []string
+	for _, p := range m.Params {
+		args = append(args, p.Name+" "+p.goType())
+	}
+	result := "(interface{}, error)"
+	if m.Kind == "export" {
+		result = "error"
+	}
+	verb := map[string]string{"get": "GET", "patch": "PATCH"}[m.Kind]
+	if verb == "" {
+		verb = "POST"
+	}
+	fmt.Fprintf(b, "\n// %s calls %s %s.\n", m.Name, verb, m.Path)
+	fmt.Fprintf(b, "func (%s *%s) %s(%s) %s {\n", t.Receiver, t.Type, m.Name, strings.Join(args, ", "), result)
+
+	path := pathExpr(m)
+	if m.Kind == "get" {
+		fmt.Fprintf(b, "\treturn %s.Client.Get(%s, nil, nil)\n}\n", t.Receiver, path)
+		return
+	}
+
+	b.WriteString("\tparams := map[string]interface{}{")
+	var optional []Param
+	first := true
+	for _, p := range m.Params {
+		if !p.inBody() {
+			continue
+		}
+		if p.Optional {
+			optional = append(optional, p)
+			continue
+		}
+		if first {
+			b.WriteString("\n")
+			first = false
+		}
+		fmt.Fprintf(b, "\t\t%q: %s,\n", p.key(), p.Name)
+	}
+	b.WriteString("\t}\n")
+	for _, p := range optional {
+		fmt.Fprintf(b, "\tif %s != nil {\n\t\tparams[%q] = *%s\n\t}\n", p.Name, p.key(), p.Name)
+	}
+
+	c := t.Receiver + ".Client"
+	switch m.Kind {
+	case "patch":
+		fmt.Fprintf(b, "\terr := %s.Patch(%s, params)\n\treturn nil, err\n", c, path)
+	case "export":
+		fmt.Fprintf(b, "\treturn %s.Export(%s, %s, params)\n", c, path, m.File)
+	case "import":
+		fmt.Fprintf(b, "\treturn %s.Import(%s, %s, params)\n", c, path, m.File)
+	default:
+		fmt.Fprintf(b, "\treturn %s.Post(%s, params)\n", c, path)
+	}
+	b.WriteString("}\n")
+}
+
+// pathExpr turns "/testmodel/component/{componentID}" into a Go string
+// expression concatenating the path parameters.
+func pathExpr(m Method) string {
+	var parts []string
+	rest := m.Path
+	for {
+		i := strings.Index(rest, "{")
+		if i < 0 {
+			break
+		}
+		j := strings.Index(rest[i:], "}")
+		if j < 0 {
+			break
+		}
+		if i > 0 {
+			parts = append(parts, fmt.Sprintf("%q", rest[:i]))
+		}
+		parts = append(parts, rest[i+1:i+j])
+		rest = rest[i+j+1:]
+	}
+	if rest != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", rest))
+	}
+	return strings.Join(parts, "+")
+}
+
+// proxyMethods are the methods of DataModelProxy that generated field
+// accessors must not shadow.
+var proxyMethods = map[string]bool{
+	"Cache": true, "Cached": true, "CachedGet": true, "Decode": true,
+	"Delete": true, "Err": true, "Fields": true, "Get": true,
+	"GetField": true, "GetItem": true, "Invalidate": true, "IsList": true,
+	"IsObject": true, "Item": true, "Node": true, "Path": true,
+	"Prefetch": true, "Put": true, "Schema": true, "Select": true,
+	"Set": true, "SetAll": true, "SetCache": true, "SetSchema": true,
+	"String": true, "URL": true, "Validate": true, "Value": true,
+	"Values": true,
+}
+
+type modelGen struct {
+	b       strings.Builder
+	done    map[string]bool
+	structs strings.Builder
+}
+
+func genModels(schema *models.Schema) string {
+	g := &modelGen{done: make(map[string]bool)}
+	g.b.WriteString(header)
+	g.b.WriteString("package models\n")
+
+	roots := make([]string, 0, len(schema.Roots))
+	for name := range schema.Roots {
+		roots = append(roots, name)
+	}
+	sort.Strings(roots)
+	for _, name := range roots {
+		node := schema.Roots[name]
+		goName := node.GoName
+		if goName == "" {
+			goName = exported(name)
+		}
+		node.GoName = goName
+		fmt.Fprintf(&g.b, "\n// New%sNode returns a typed proxy for /%s\n", goName, name)
+		fmt.Fprintf(&g.b, "func New%sNode(wrapper BPSWrapper) *%sNode {\n\treturn &%sNode{NewDataModelProxy(wrapper, %q, \"\")}\n}\n", goName, goName, goName, name)
+		g.object(node, "/"+name)
+	}
+	g.b.WriteString(g.structs.String())
+	return g.b.String()
+}
+
+// object emits the typed proxy and value struct of an object node.
+func (g *modelGen) object(node *models.SchemaNode, path string) {
+	if g.done[node.GoName] {
+		return
+	}
+	g.done[node.GoName] = true
+
+	fmt.Fprintf(&g.b, "\n// %sNode is a typed proxy for %s\ntype %sNode struct {\n\t*DataModelProxy\n}\n", node.GoName, path, node.GoName)
+	fmt.Fprintf(&g.b, "\n// Value reads the node\nfunc (n *%sNode) Value() (*%s, error) {\n\tdepth := FullDepth\n\tv := &%s{}\n\tif err := n.Decode(&depth, v); err != nil {\n\t\treturn nil, err\n\t}\n\treturn v, nil\n}\n",
+		node.GoName, valueType(node), valueType(node))
+
+	var nested []func()
+	for _, field := range node.FieldNames() {
+		child := node.Fields[field]
+		childPath := path + "/" + field
+		method := exported(field)
+		if proxyMethods[method] {
+			method += "Field"
+		}
+		switch {
+		case child.Type == models.NodeObject:
+			g.name(child, node.GoName+exported(field))
+			fmt.Fprintf(&g.b, "\n// %s returns the %s field\nfunc (n *%sNode) %s() *%sNode {\n\treturn &%sNode{n.GetField(%q)}\n}\n",
+				method, field, node.GoName, method, child.GoName, child.GoName, field)
+			nested = append(nested, func() { g.object(child, childPath) })
+		case child.Type == models.NodeList && child.Items != nil && child.Items.Type == models.NodeObject:
+			g.name(child.Items, node.GoName+exported(field))
+			fmt.Fprintf(&g.b, "\n// %s returns the %s list\nfunc (n *%sNode) %s() *%sItems {\n\treturn &%sItems{n.GetField(%q)}\n}\n",
+				method, field, node.GoName, method, child.Items.GoName, child.Items.GoName, field)
+			nested = append(nested, func() { g.list(child.Items, childPath) })
+		default:
+			fmt.Fprintf(&g.b, "\n// %s returns the %s field\nfunc (n *%sNode) %s() *DataModelProxy {\n\treturn n.GetField(%q)\n}\n",
+				method, field, node.GoName, method, field)
+		}
+	}
+	if node.GoType == "" {
+		g.valueStruct(node)
+	}
+	for _, fn := range nested {
+		fn()
+	}
+}
+
+func (g *modelGen) list(item *models.SchemaNode, path string) {
+	fmt.Fprintf(&g.b, "\n// %sItems is a typed proxy for %s\ntype %sItems struct {\n\t*DataModelProxy\n}\n", item.GoName, path, item.GoName)
+	fmt.Fprintf(&g.b, "\n// Item returns the list element with the given index or id\nfunc (l *%sItems) Item(index interface{}) *%sNode {\n\treturn &%sNode{l.GetItem(index)}\n}\n",
+		item.GoName, item.GoName, item.GoName)
+	fmt.Fprintf(&g.b, "\n// Values reads every element of the list\nfunc (l *%sItems) Values() ([]%s, error) {\n\tdepth := FullDepth\n\tvar v []%s\n\tif err := l.Decode(&depth, &v); err != nil {\n\t\treturn nil, err\n\t}\n\treturn v, nil\n}\n",
+		item.GoName, valueType(item), valueType(item))
+	g.object(item, path+"/{item}")
+}
+
+func (g *modelGen) name(node *models.SchemaNode, fallback string) {
+	if node.GoName == "" {
+		node.GoName = fallback
+	}
+}
+
+func (g *modelGen) valueStruct(node *models.SchemaNode) {
+	fmt.Fprintf(&g.structs, "\n// %s is the value of a %sNode\ntype %s struct {\n", node.GoName, node.GoName, node.GoName)
+	for _, field := range node.FieldNames() {
+		fmt.Fprintf(&g.structs, "\t%s %s `json:\"%s,omitempty\"`\n", exported(field), fieldType(node.Fields[field]), field)
+	}
+	g.structs.WriteString("}\n")
+}
+
+func valueType(node *models.SchemaNode) string {
+	if node.GoType != "" {
+		return node.GoType
+	}
+	return node.GoName
+}
+
+func fieldType(node *models.SchemaNode) string {
+	switch node.Type {
+	case models.NodeAny:
+		if node.GoType != "" {
+			return node.GoType
+		}
+	case models.NodeString:
+		return "string"
+	case models.NodeInteger:
+		return "int"
+	case models.NodeNumber:
+		return "float64"
+	case models.NodeBoolean:
+		return "bool"
+	case models.NodeObject:
+		if node.GoName != "" {
+			return valueType(node)
+		}
+		return "map[string]interface{}"
+	case models.NodeList:
+		if node.Items == nil {
+			return "[]interface{}"
+		}
+		return "[]" + fieldType(node.Items)
+	}
+	return "interface{}"
+}
+
+// exported turns a data model field name into an exported Go identifier.
+func exported(name string) string {
+	switch strings.ToLower(name) {
+	case "id":
+		return "ID"
+	case "url":
+		return "URL"
+	}
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' || r == ' ' {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
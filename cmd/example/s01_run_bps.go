@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"bps-client-go/pkg/client"
+	"bps-client-go/pkg/operations"
+	"bps-client-go/pkg/pcap"
 )
 
 var (
@@ -23,6 +25,7 @@ var (
 	portList          = []int{0, 1, 4, 5}
 	modelComponentAct []string
 	globalRunID       int
+	captureDir        string
 )
 
 func loginToBps() *client.BPS {
@@ -47,7 +50,7 @@ func setActiveComponents(bps *client.BPS) error {
 	for _, name := range modelComponentAct {
 		activeWanted[strings.TrimSpace(name)] = true
 	}
-	changed := false
+	edit := bps.TestModel.Edit(modelName)
 	for _, comp := range comps {
 		c, ok := comp.(map[string]interface{})
 		if !ok {
@@ -66,17 +69,13 @@ func setActiveComponents(bps *client.BPS) error {
 				stateStr = "active"
 			}
 			fmt.Printf("Component '%s' state changed to %s\n", label, stateStr)
-			if _, err := bps.TestModel.SetComponentActive(idStr, wantActive); err != nil {
-				return fmt.Errorf("failed to update component %s: %w", label, err)
-			}
-			changed = true
+			edit.SetActive(idStr, wantActive)
 		}
 	}
-	if changed {
+	if edit.Len() > 0 {
 		fmt.Println("Saving component changes...")
-		_, err := bps.TestModel.Save(modelName, true)
-		if err != nil {
-			return fmt.Errorf("failed to save components: %w", err)
+		if err := edit.Commit(); err != nil {
+			return fmt.Errorf("failed to update components: %w", err)
 		}
 	}
 	return nil
@@ -139,6 +138,44 @@ func unreservePorts(bps *client.BPS) {
 	}
 }
 
+// armCapture enables capture on the reserved ports when CAPTURE_DIR is
+// set, so a failed run leaves its captures behind
+func armCapture(bps *client.BPS) *operations.CaptureSession {
+	if captureDir == "" {
+		return nil
+	}
+	session := &operations.CaptureSession{Topology: bps.TopologyOps, Dir: captureDir}
+	for _, port := range portList {
+		session.Ports = append(session.Ports, operations.CapturePort{Slot: slotNumber, Port: port})
+	}
+	fmt.Println("Arming capture on reserved ports")
+	if err := session.Arm(); err != nil {
+		log.Printf("Failed to arm capture: %v", err)
+	}
+	return session
+}
+
+func exportCapture(session *operations.CaptureSession) {
+	if session == nil || globalRunID == 0 {
+		return
+	}
+	files, err := session.Export(globalRunID)
+	if err != nil {
+		log.Printf("Failed to export capture: %v", err)
+	}
+	for _, f := range files {
+		fmt.Printf("Capture written to %s\n", f)
+		summary, err := pcap.Summarize(f, pcap.Origin{})
+		if err != nil {
+			log.Printf("Failed to summarize capture: %v", err)
+			continue
+		}
+		for _, finding := range summary.Findings(pcap.DefaultThresholds) {
+			fmt.Println(finding)
+		}
+	}
+}
+
 func runTestAndPoll(bps *client.BPS, modelName string) error {
 	result, err := bps.TestModel.Run(modelName, 2, false)
 	if err != nil {
@@ -229,6 +266,7 @@ func main() {
 	bpsSystem = strings.TrimSpace(os.Getenv("BPS_SYSTEM"))
 	bpsUser = strings.TrimSpace(os.Getenv("BPS_USER"))
 	bpsPass = strings.TrimSpace(os.Getenv("BPS_PASS"))
+	captureDir = strings.TrimSpace(os.Getenv("CAPTURE_DIR"))
 	compActEnv := strings.TrimSpace(os.Getenv("COMPONENT_ACT"))
 	if compActEnv != "" {
 		modelComponentAct = strings.Split(compActEnv, ",")
@@ -256,6 +294,7 @@ func main() {
 
 	searchAndLoadNetworkConfig(bps)
 	reservePorts(bps)
+	capture := armCapture(bps)
 
 	errChan := make(chan error, 1)
 	go func() {
@@ -274,13 +313,17 @@ func main() {
 				fmt.Println("Test was canceled successfully.")
 			}
 		}
+		capture.Close()
 		unreservePorts(bps)
 
 	case err := <-errChan:
 		if err != nil {
+			exportCapture(capture)
+			capture.Close()
 			log.Fatalf("Test run failed: %v", err)
 		}
 		fmt.Println("Test completed normally.")
+		capture.Close()
 		unreservePorts(bps)
 	}
 
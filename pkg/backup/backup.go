@@ -0,0 +1,183 @@
+// Package backup exports the user configuration stored on a BPS chassis into
+// a single archive and restores it, object by object, on the same or
+// another chassis.
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"bps-client-go/pkg/models"
+	"bps-client-go/pkg/operations"
+)
+
+const (
+	manifestFile  = "manifest.json"
+	searchLimit   = 10000
+	snapshotDepth = 10
+)
+
+// Kind identifies the type of a configuration object.
+type Kind string
+
+const (
+	KindTestModel      Kind = "testmodel"
+	KindNetwork        Kind = "network"
+	KindAppProfile     Kind = "appProfile"
+	KindSuperflow      Kind = "superflow"
+	KindStrikeList     Kind = "strikeList"
+	KindEvasionProfile Kind = "evasionProfile"
+	KindLoadProfile    Kind = "loadProfile"
+)
+
+// AllKinds lists every kind in the order objects are restored, so that
+// objects are imported before the ones referencing them.
+var AllKinds = []Kind{
+	KindSuperflow,
+	KindAppProfile,
+	KindStrikeList,
+	KindEvasionProfile,
+	KindLoadProfile,
+	KindNetwork,
+	KindTestModel,
+}
+
+// Entry is one object in a backup archive.
+type Entry struct {
+	Name     string `json:"name"`
+	Kind     Kind   `json:"type"`
+	Revision int    `json:"revision"`
+	File     string `json:"file"`
+	Format   string `json:"format"`
+	Checksum string `json:"checksum"`
+}
+
+// Manifest describes the content of a backup archive.
+type Manifest struct {
+	Created time.Time `json:"created"`
+	Host    string    `json:"host,omitempty"`
+	Entries []Entry   `json:"entries"`
+	Errors  []string  `json:"errors,omitempty"`
+}
+
+// Manager backs up and restores chassis configuration.
+type Manager struct {
+	Client operations.ClientWrapper
+	Host   string
+	// Schema tells the read-only fields stripped from JSON snapshots
+	// before they are written back. Without it only the name and the
+	// bookkeeping fields are stripped.
+	Schema *models.Schema
+}
+
+// Backup exports every object of the given kinds (all kinds when none are
+// given) into the gzip compressed tar archive at path. Objects that fail to
+// export are recorded in Manifest.Errors and do not abort the backup.
+// Kinds stored as snapshots are exported by loading each object, after
+// which their working copy is put back the way it was.
+func (m *Manager) Backup(path string, kinds ...Kind) (*Manifest, error) {
+	if len(kinds) == 0 {
+		kinds = AllKinds
+	}
+	dir, err := os.MkdirTemp("", "bps-backup-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := &Manifest{Created: time.Now().UTC(), Host: m.Host}
+	for _, kind := range kinds {
+		h, err := m.handler(kind)
+		if err != nil {
+			return nil, err
+		}
+		items, err := h.list()
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", kind, err)
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+		if err := os.MkdirAll(filepath.Join(dir, string(kind)), 0o755); err != nil {
+			return nil, err
+		}
+		if err := m.exportKind(dir, kind, h, items, manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), data, 0o644); err != nil {
+		return nil, err
+	}
+	if err := writeArchive(path, dir); err != nil {
+		return nil, fmt.Errorf("write archive %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// exportKind exports items of kind into dir and adds them to manifest.
+func (m *Manager) exportKind(dir string, kind Kind, h *handler, items []operations.SearchItem, manifest *Manifest) (err error) {
+	restore, err := m.keepWorking(h)
+	if err != nil {
+		return fmt.Errorf("%s: %w", kind, err)
+	}
+	defer func() {
+		if restoreErr := restore(); restoreErr != nil && err == nil {
+			err = fmt.Errorf("%s: %w", kind, restoreErr)
+		}
+	}()
+	for _, item := range items {
+		entry := Entry{
+			Name:     item.Name,
+			Kind:     kind,
+			Revision: item.Revision,
+			File:     filepath.ToSlash(filepath.Join(string(kind), models.FileName(item.Name)+h.ext)),
+			Format:   h.format,
+		}
+		target := filepath.Join(dir, filepath.FromSlash(entry.File))
+		if err := h.export(item.Name, target); err != nil {
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("%s %s: %v", kind, item.Name, err))
+			continue
+		}
+		if entry.Checksum, err = checksum(target); err != nil {
+			return err
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+	return nil
+}
+
+// ReadManifest returns the manifest of the archive at path.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := readArchiveFile(path, manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func checksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
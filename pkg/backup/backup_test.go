@@ -0,0 +1,369 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"bps-client-go/pkg/models"
+	"bps-client-go/pkg/operations"
+)
+
+// fakeChassis keeps saved networks as export files and saved load profiles
+// as JSON objects, with a working load profile they are loaded into
+type fakeChassis struct {
+	networks map[string]string
+	profiles map[string]map[string]interface{}
+	working  map[string]interface{}
+	imported []string
+	puts     []map[string]interface{}
+}
+
+func (c *fakeChassis) Get(path string, responseDepth *int, params map[string]string) (interface{}, error) {
+	if path == "/loadProfile" {
+		return c.working, nil
+	}
+	return nil, fmt.Errorf("GET %s: not found", path)
+}
+
+func (c *fakeChassis) Post(path string, data interface{}) (interface{}, error) {
+	params, _ := data.(map[string]interface{})
+	switch path {
+	case "/network/operations/search":
+		return searchResult(c.networks), nil
+	case "/loadprofile/operations/search":
+		return searchResult(c.profiles), nil
+	case "/loadprofile/operations/load":
+		name := params["template"].(string)
+		p, ok := c.profiles[name]
+		if !ok {
+			return nil, fmt.Errorf("no load profile %s", name)
+		}
+		c.working = copyObject(p)
+		return nil, nil
+	case "/loadprofile/operations/createNew":
+		c.working = map[string]interface{}{"name": params["loadProfile"]}
+		return nil, nil
+	case "/loadprofile/operations/save", "/loadprofile/operations/saveAs":
+		name, _ := params["name"].(string)
+		if name == "" {
+			name = c.working["name"].(string)
+		}
+		c.imported = append(c.imported, "loadProfile/"+name)
+		return nil, nil
+	}
+	return nil, fmt.Errorf("POST %s: not found", path)
+}
+
+func (c *fakeChassis) Put(path string, value interface{}) error {
+	v := value.(map[string]interface{})
+	c.puts = append(c.puts, v)
+	name := c.working["name"]
+	c.working = copyObject(v)
+	c.working["name"] = name
+	return nil
+}
+
+func (c *fakeChassis) Patch(path string, value interface{}) error { return nil }
+
+func (c *fakeChassis) Delete(path string) (interface{}, error) { return nil, nil }
+
+func (c *fakeChassis) Export(path, file string, params map[string]interface{}) error {
+	return os.WriteFile(file, []byte(c.networks[params["name"].(string)]), 0o644)
+}
+
+func (c *fakeChassis) Import(path, filename string, params map[string]interface{}) (interface{}, error) {
+	c.imported = append(c.imported, "network/"+params["name"].(string))
+	return nil, nil
+}
+
+func (c *fakeChassis) EnableProfiling(enabled bool) {}
+func (c *fakeChassis) PrintVersions()               {}
+func (c *fakeChassis) PrintProfilingData()          {}
+
+func searchResult[T any](saved map[string]T) map[string]interface{} {
+	var items []interface{}
+	for name := range saved {
+		items = append(items, map[string]interface{}{"name": name, "revision": 3})
+	}
+	return map[string]interface{}{"result": items}
+}
+
+func copyObject(v map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(v)
+	var out map[string]interface{}
+	json.Unmarshal(data, &out)
+	return out
+}
+
+func newChassis() *fakeChassis {
+	return &fakeChassis{
+		networks: map[string]string{"lab": "network lab", "a/b": "network a/b"},
+		profiles: map[string]map[string]interface{}{
+			"ramp": {"name": "ramp", "revision": 2, "phase": []interface{}{map[string]interface{}{"duration": 10}}},
+		},
+		working: map[string]interface{}{"name": "mine", "revision": 1, "description": "unsaved edit"},
+	}
+}
+
+func TestBackupManifest(t *testing.T) {
+	c := newChassis()
+	m := &Manager{Client: c, Host: "chassis"}
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	manifest, err := m.Backup(archive, KindNetwork, KindLoadProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Errors) > 0 {
+		t.Fatalf("manifest errors: %v", manifest.Errors)
+	}
+	var files []string
+	for _, e := range manifest.Entries {
+		files = append(files, e.File)
+	}
+	want := []string{"network/" + models.FileName("a/b") + ".export", "network/lab.export", "loadProfile/ramp.json"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+	read, err := ReadManifest(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Entries, manifest.Entries) || read.Host != "chassis" {
+		t.Errorf("ReadManifest() = %+v, want %+v", read, manifest)
+	}
+	sum := sha256.Sum256([]byte("network lab"))
+	if got := manifest.Entries[1].Checksum; got != hex.EncodeToString(sum[:]) {
+		t.Errorf("checksum of lab = %s", got)
+	}
+	if c.working["name"] != "mine" || c.working["description"] != "unsaved edit" {
+		t.Errorf("working load profile after backup = %v", c.working)
+	}
+	if _, ok := c.puts[len(c.puts)-1]["revision"]; ok {
+		t.Errorf("read-only revision written back: %v", c.puts[len(c.puts)-1])
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  []string
+		opts      RestoreOptions
+		tamper    string
+		restored  []string
+		planned   []string
+		conflicts []string
+		failed    []string
+		imported  []string
+	}{
+		{
+			name:     "empty chassis",
+			restored: []string{"ramp", "a/b", "lab"},
+			imported: []string{"loadProfile/ramp", "network/a/b", "network/lab"},
+		},
+		{
+			name:      "conflicts",
+			existing:  []string{"lab"},
+			restored:  []string{"ramp", "a/b"},
+			conflicts: []string{"lab"},
+			imported:  []string{"loadProfile/ramp", "network/a/b"},
+		},
+		{
+			name:     "force",
+			existing: []string{"lab"},
+			opts:     RestoreOptions{Force: true},
+			restored: []string{"ramp", "a/b", "lab"},
+			imported: []string{"loadProfile/ramp", "network/a/b", "network/lab"},
+		},
+		{
+			name:      "dry run",
+			existing:  []string{"lab"},
+			opts:      RestoreOptions{DryRun: true},
+			planned:   []string{"ramp", "a/b"},
+			conflicts: []string{"lab"},
+		},
+		{
+			name:     "selected names",
+			opts:     RestoreOptions{Kinds: []Kind{KindNetwork}, Names: []string{"lab"}},
+			restored: []string{"lab"},
+			imported: []string{"network/lab"},
+		},
+		{
+			name:     "checksum mismatch",
+			tamper:   "network/lab.export",
+			restored: []string{"ramp", "a/b"},
+			failed:   []string{"network/lab"},
+			imported: []string{"loadProfile/ramp", "network/a/b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+			if _, err := (&Manager{Client: newChassis()}).Backup(archive, KindNetwork, KindLoadProfile); err != nil {
+				t.Fatal(err)
+			}
+			if tt.tamper != "" {
+				tamper(t, archive, tt.tamper)
+			}
+			target := &fakeChassis{
+				networks: map[string]string{},
+				profiles: map[string]map[string]interface{}{},
+				working:  map[string]interface{}{"name": "mine"},
+			}
+			for _, name := range tt.existing {
+				target.networks[name] = "old"
+			}
+			report, err := (&Manager{Client: target}).Restore(archive, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			check := func(what string, got []Entry, want []string) {
+				var names []string
+				for _, e := range got {
+					names = append(names, e.Name)
+				}
+				if !reflect.DeepEqual(names, want) {
+					t.Errorf("%s = %v, want %v", what, names, want)
+				}
+			}
+			check("restored", report.Restored, tt.restored)
+			check("planned", report.Planned, tt.planned)
+			check("conflicts", report.Conflicts, tt.conflicts)
+			var failed []string
+			for key := range report.Failed {
+				failed = append(failed, key)
+			}
+			sort.Strings(failed)
+			if !reflect.DeepEqual(failed, tt.failed) {
+				t.Errorf("failed = %v, want %v", report.Failed, tt.failed)
+			}
+			if !reflect.DeepEqual(target.imported, tt.imported) {
+				t.Errorf("imported = %v, want %v", target.imported, tt.imported)
+			}
+		})
+	}
+}
+
+// tamper changes the content of file inside archive
+func tamper(t *testing.T, archive, file string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := extractArchive(archive, dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(file)), []byte("tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeArchive(archive, dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWritable(t *testing.T) {
+	schema := &models.Schema{Roots: map[string]*models.SchemaNode{
+		"testmodel": {Type: models.NodeObject, Fields: map[string]*models.SchemaNode{
+			"name":     {Type: models.NodeString},
+			"revision": {Type: models.NodeInteger, ReadOnly: true},
+			"component": {Type: models.NodeList, Items: &models.SchemaNode{Type: models.NodeObject, Fields: map[string]*models.SchemaNode{
+				"id":    {Type: models.NodeString, ReadOnly: true},
+				"label": {Type: models.NodeString},
+			}}},
+		}},
+	}}
+	snapshot := map[string]interface{}{
+		"name":      "m",
+		"revision":  4,
+		"createdBy": "admin",
+		"component": []interface{}{map[string]interface{}{"id": "appsim_1", "label": "web"}},
+	}
+	tests := []struct {
+		name   string
+		schema *models.Schema
+		path   string
+		want   string
+	}{
+		{"schema", schema, "/testmodel", `{"component":[{"label":"web"}],"createdBy":"admin"}`},
+		{"no schema", nil, "/testmodel", `{"component":[{"id":"appsim_1","label":"web"}]}`},
+		{"path the schema lacks", schema, "/superflow", `{"component":[{"id":"appsim_1","label":"web"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := json.Marshal((&Manager{Schema: tt.schema}).writable(tt.path, snapshot))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("writable() = %s, want %s", out, tt.want)
+			}
+			if snapshot["revision"] != 4 {
+				t.Errorf("writable() changed the snapshot")
+			}
+		})
+	}
+}
+
+func TestSearchPaging(t *testing.T) {
+	page := func(from, n int) interface{} {
+		items := make([]interface{}, n)
+		for i := range items {
+			items[i] = map[string]interface{}{"name": fmt.Sprintf("o%05d", from+i)}
+		}
+		return map[string]interface{}{"result": items}
+	}
+	tests := []struct {
+		name    string
+		search  func(limit, offset int) (interface{}, error)
+		want    int
+		wantErr string
+	}{
+		{
+			name:   "short first page",
+			search: func(limit, offset int) (interface{}, error) { return page(offset, 3), nil },
+			want:   3,
+		},
+		{
+			name: "two pages",
+			search: func(limit, offset int) (interface{}, error) {
+				if offset == 0 {
+					return page(0, limit), nil
+				}
+				return page(offset, 5), nil
+			},
+			want: searchLimit + 5,
+		},
+		{
+			name:    "offset ignored",
+			search:  func(limit, offset int) (interface{}, error) { return page(0, limit), nil },
+			wantErr: "ignores the offset",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := pagedItems(tt.search)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("pagedItems() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != tt.want {
+				t.Errorf("pagedItems() = %d items, want %d", len(items), tt.want)
+			}
+		})
+	}
+	if _, err := searchItems(page(0, searchLimit), nil); err == nil {
+		t.Errorf("searchItems() accepted a full list")
+	}
+}
+
+var _ operations.ClientWrapper = (*fakeChassis)(nil)
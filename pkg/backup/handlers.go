@@ -0,0 +1,370 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"bps-client-go/pkg/models"
+	"bps-client-go/pkg/operations"
+)
+
+const (
+	formatExport   = "export"
+	formatSnapshot = "json"
+)
+
+// bookkeepingFields are the fields the chassis maintains on every saved
+// object; they are stripped from snapshots when there is no schema to
+// tell the read-only fields.
+var bookkeepingFields = []string{"revision", "createdBy", "createdOn"}
+
+// handler binds a kind to the operations used to list, export and import
+// its objects. Kinds without export/import operations on the chassis are
+// stored as JSON snapshots of their working copy.
+type handler struct {
+	ext     string
+	format  string
+	list    func() ([]operations.SearchItem, error)
+	export  func(name, path string) error
+	restore func(name, path string, force bool) error
+	// working is the model path of the working copy snapshot kinds load
+	// objects into; reopen makes the saved object name the working copy
+	// again, or a new one when name is empty or was never saved.
+	working string
+	reopen  func(name string) error
+}
+
+func (m *Manager) handler(kind Kind) (*handler, error) {
+	c := m.Client
+	switch kind {
+	case KindTestModel:
+		ops := &operations.TestModelOps{Client: c}
+		return &handler{
+			ext:    ".export",
+			format: formatExport,
+			list: func() ([]operations.SearchItem, error) {
+				return searchItems(ops.Search("", searchLimit, "name", "ascending"))
+			},
+			export: func(name, path string) error { return ops.ExportModel(name, true, path) },
+			restore: func(name, path string, force bool) error {
+				_, err := ops.ImportModel(name, path, force)
+				return err
+			},
+		}, nil
+	case KindNetwork:
+		ops := &operations.NetworkOps{Client: c}
+		return &handler{
+			ext:    ".export",
+			format: formatExport,
+			list: func() ([]operations.SearchItem, error) {
+				return pagedItems(func(limit, offset int) (interface{}, error) {
+					return ops.Search("", "", "", "ascending", "name", limit, offset)
+				})
+			},
+			export: func(name, path string) error { return ops.ExportNetwork(name, true, path) },
+			restore: func(name, path string, force bool) error {
+				_, err := ops.ImportNetwork(name, path, force)
+				return err
+			},
+		}, nil
+	case KindAppProfile:
+		ops := &operations.AppProfileOps{Client: c}
+		return &handler{
+			ext:    ".export",
+			format: formatExport,
+			list: func() ([]operations.SearchItem, error) {
+				return searchItems(ops.Search("", searchLimit, "name", "ascending"))
+			},
+			export: func(name, path string) error { return ops.ExportAppProfile(name, true, path) },
+			restore: func(name, path string, force bool) error {
+				_, err := ops.ImportAppProfile(name, path, force)
+				return err
+			},
+		}, nil
+	case KindStrikeList:
+		ops := &operations.StrikeListOps{Client: c}
+		return &handler{
+			ext:    ".export",
+			format: formatExport,
+			list: func() ([]operations.SearchItem, error) {
+				return searchItems(ops.Search("", searchLimit, "name", "ascending"))
+			},
+			export: func(name, path string) error { return ops.ExportStrikeList(name, path) },
+			restore: func(name, path string, force bool) error {
+				_, err := ops.ImportStrikeList(name, path, force)
+				return err
+			},
+		}, nil
+	case KindSuperflow:
+		ops := &operations.SuperflowOps{Client: c}
+		return &handler{
+			ext:    ".json",
+			format: formatSnapshot,
+			list: func() ([]operations.SearchItem, error) {
+				return searchItems(ops.Search("", searchLimit, "name", "ascending"))
+			},
+			export: func(name, path string) error {
+				if _, err := ops.Load(name); err != nil {
+					return err
+				}
+				return m.snapshot("/superflow", path)
+			},
+			restore: func(name, path string, force bool) error {
+				if _, err := ops.New(nil); err != nil {
+					return err
+				}
+				if err := m.apply("/superflow", path); err != nil {
+					return err
+				}
+				_, err := ops.SaveAs(name, force)
+				return err
+			},
+			working: "/superflow",
+			reopen: func(name string) error {
+				if name != "" {
+					if _, err := ops.Load(name); err == nil {
+						return nil
+					}
+				}
+				_, err := ops.New(nil)
+				return err
+			},
+		}, nil
+	case KindEvasionProfile:
+		ops := &operations.EvasionProfileOps{Client: c}
+		return &handler{
+			ext:    ".json",
+			format: formatSnapshot,
+			list: func() ([]operations.SearchItem, error) {
+				return searchItems(ops.Search("", searchLimit, "name", "ascending"))
+			},
+			export: func(name, path string) error {
+				if _, err := ops.Load(name); err != nil {
+					return err
+				}
+				return m.snapshot("/evasionProfile", path)
+			},
+			restore: func(name, path string, force bool) error {
+				if _, err := ops.New(nil); err != nil {
+					return err
+				}
+				if err := m.apply("/evasionProfile", path); err != nil {
+					return err
+				}
+				_, err := ops.SaveAs(name, force)
+				return err
+			},
+			working: "/evasionProfile",
+			reopen: func(name string) error {
+				if name != "" {
+					if _, err := ops.Load(name); err == nil {
+						return nil
+					}
+				}
+				_, err := ops.New(nil)
+				return err
+			},
+		}, nil
+	case KindLoadProfile:
+		ops := &operations.LoadProfileOps{Client: c}
+		return &handler{
+			ext:    ".json",
+			format: formatSnapshot,
+			list: func() ([]operations.SearchItem, error) {
+				return searchItems(ops.Search("", searchLimit, "name", "ascending"))
+			},
+			export: func(name, path string) error {
+				if _, err := ops.Load(name); err != nil {
+					return err
+				}
+				return m.snapshot("/loadProfile", path)
+			},
+			restore: func(name, path string, force bool) error {
+				// LoadProfileOps.SaveAs cannot overwrite, so a forced
+				// restore loads the existing profile and saves in place.
+				if force {
+					if _, err := ops.Load(name); err == nil {
+						if err := m.apply("/loadProfile", path); err != nil {
+							return err
+						}
+						_, err = ops.Save()
+						return err
+					}
+				}
+				if _, err := ops.CreateNew(name); err != nil {
+					return err
+				}
+				if err := m.apply("/loadProfile", path); err != nil {
+					return err
+				}
+				_, err := ops.SaveAs(name)
+				return err
+			},
+			// A load profile cannot be started without a name, so an
+			// unnamed working copy is put back over the last one loaded.
+			working: "/loadProfile",
+			reopen: func(name string) error {
+				if name == "" {
+					return nil
+				}
+				if _, err := ops.Load(name); err == nil {
+					return nil
+				}
+				_, err := ops.CreateNew(name)
+				return err
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown backup kind %q", kind)
+}
+
+// snapshot writes the working copy at modelPath to path as JSON.
+func (m *Manager) snapshot(modelPath, path string) error {
+	depth := snapshotDepth
+	data, err := m.Client.Get(modelPath, &depth, nil)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o644)
+}
+
+// apply replaces the working copy at modelPath with the snapshot at path.
+func (m *Manager) apply(modelPath, path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("parse snapshot: %w", err)
+	}
+	return m.Client.Put(modelPath, m.writable(modelPath, data))
+}
+
+// keepWorking reads the working copy of a snapshot kind and returns a
+// function putting it back, unsaved edits included, once the backup has
+// loaded other objects over it.
+func (m *Manager) keepWorking(h *handler) (func() error, error) {
+	if h.working == "" {
+		return func() error { return nil }, nil
+	}
+	depth := snapshotDepth
+	data, err := m.Client.Get(h.working, &depth, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read working copy %s: %w", h.working, err)
+	}
+	saved, _ := data.(map[string]interface{})
+	return func() error {
+		name, _ := saved["name"].(string)
+		if err := h.reopen(name); err != nil {
+			return fmt.Errorf("reopen working copy %s: %w", name, err)
+		}
+		if saved == nil {
+			return nil
+		}
+		if err := m.Client.Put(h.working, m.writable(h.working, saved)); err != nil {
+			return fmt.Errorf("restore working copy %s: %w", h.working, err)
+		}
+		return nil
+	}, nil
+}
+
+// writable returns a copy of a snapshot of the working copy at modelPath
+// without the fields the chassis sets itself: the name, which SaveAs
+// assigns, and the fields m.Schema marks read only, or the bookkeeping
+// fields when the schema does not describe modelPath.
+func (m *Manager) writable(modelPath string, data map[string]interface{}) map[string]interface{} {
+	var node *models.SchemaNode
+	if m.Schema != nil {
+		node, _ = m.Schema.Lookup(modelPath)
+	}
+	out := stripReadOnly(node, data).(map[string]interface{})
+	delete(out, "name")
+	if node == nil {
+		for _, k := range bookkeepingFields {
+			delete(out, k)
+		}
+	}
+	return out
+}
+
+// stripReadOnly copies v without the fields node marks read only.
+func stripReadOnly(node *models.SchemaNode, v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			var child *models.SchemaNode
+			if node != nil && node.Type == models.NodeObject {
+				child = node.Fields[k]
+			}
+			if child != nil && child.ReadOnly {
+				continue
+			}
+			out[k] = stripReadOnly(child, item)
+		}
+		return out
+	case []interface{}:
+		var items *models.SchemaNode
+		if node != nil && node.Type == models.NodeList {
+			items = node.Items
+		}
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			out[i] = stripReadOnly(items, item)
+		}
+		return out
+	}
+	return v
+}
+
+// searchItems decodes the result of a search that cannot page. It asks
+// for searchLimit items, so a full list means objects were left out.
+func searchItems(resp interface{}, err error) ([]operations.SearchItem, error) {
+	if err != nil {
+		return nil, err
+	}
+	items, err := operations.SearchItems(resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) >= searchLimit {
+		return nil, fmt.Errorf("more than %d objects, which the search cannot page through", searchLimit-1)
+	}
+	return items, nil
+}
+
+// pagedItems pages through a search taking an offset until a short page.
+func pagedItems(search func(limit, offset int) (interface{}, error)) ([]operations.SearchItem, error) {
+	var out []operations.SearchItem
+	seen := make(map[string]bool)
+	for offset := 0; ; offset += searchLimit {
+		resp, err := search(searchLimit, offset)
+		if err != nil {
+			return nil, err
+		}
+		items, err := operations.SearchItems(resp)
+		if err != nil {
+			return nil, err
+		}
+		fresh := 0
+		for _, item := range items {
+			if !seen[item.Name] {
+				seen[item.Name] = true
+				out = append(out, item)
+				fresh++
+			}
+		}
+		if len(items) < searchLimit {
+			return out, nil
+		}
+		if fresh == 0 {
+			return nil, fmt.Errorf("search ignores the offset and repeats the first %d objects", searchLimit)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RestoreOptions selects what a restore imports and how it treats objects
+// already present on the chassis.
+type RestoreOptions struct {
+	// Kinds limits the restore to the given kinds. All kinds when empty.
+	Kinds []Kind
+	// Names limits the restore to objects with the given names.
+	Names []string
+	// Force overwrites existing objects instead of reporting a conflict.
+	Force bool
+	// DryRun reports what would be restored without importing anything.
+	DryRun bool
+}
+
+func (o RestoreOptions) selects(e Entry) bool {
+	if len(o.Kinds) > 0 && !containsKind(o.Kinds, e.Kind) {
+		return false
+	}
+	if len(o.Names) > 0 && !containsString(o.Names, e.Name) {
+		return false
+	}
+	return true
+}
+
+// RestoreReport is the outcome of a restore. A dry run lists the objects
+// it would have imported in Planned and leaves Restored empty.
+type RestoreReport struct {
+	Restored  []Entry
+	Planned   []Entry
+	Conflicts []Entry
+	Failed    map[string]error
+}
+
+func (r *RestoreReport) String() string {
+	if len(r.Planned) > 0 {
+		return fmt.Sprintf("%d to restore, %d conflicts, %d failed", len(r.Planned), len(r.Conflicts), len(r.Failed))
+	}
+	return fmt.Sprintf("%d restored, %d conflicts, %d failed", len(r.Restored), len(r.Conflicts), len(r.Failed))
+}
+
+// Restore imports the objects of the archive at path selected by opts.
+// Objects whose name already exists on the chassis are reported as
+// conflicts unless opts.Force is set. Checksums are verified before import.
+// The working copy of the kinds stored as snapshots is put back once their
+// objects are restored.
+func (m *Manager) Restore(path string, opts RestoreOptions) (*RestoreReport, error) {
+	manifest, err := ReadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "bps-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := extractArchive(path, dir); err != nil {
+		return nil, fmt.Errorf("extract %s: %w", path, err)
+	}
+
+	byKind := make(map[Kind][]Entry)
+	for _, e := range manifest.Entries {
+		if opts.selects(e) {
+			byKind[e.Kind] = append(byKind[e.Kind], e)
+		}
+	}
+
+	report := &RestoreReport{Failed: make(map[string]error)}
+	for _, kind := range AllKinds {
+		entries := byKind[kind]
+		if len(entries) == 0 {
+			continue
+		}
+		h, err := m.handler(kind)
+		if err != nil {
+			return report, err
+		}
+		existing, err := h.list()
+		if err != nil {
+			return report, fmt.Errorf("list %s: %w", kind, err)
+		}
+		present := make(map[string]bool, len(existing))
+		for _, item := range existing {
+			present[item.Name] = true
+		}
+		keep := func() error { return nil }
+		if !opts.DryRun {
+			if keep, err = m.keepWorking(h); err != nil {
+				return report, fmt.Errorf("%s: %w", kind, err)
+			}
+		}
+
+		for _, e := range entries {
+			key := string(e.Kind) + "/" + e.Name
+			if present[e.Name] && !opts.Force {
+				report.Conflicts = append(report.Conflicts, e)
+				continue
+			}
+			file := filepath.Join(dir, filepath.FromSlash(e.File))
+			sum, err := checksum(file)
+			if err != nil {
+				report.Failed[key] = err
+				continue
+			}
+			if sum != e.Checksum {
+				report.Failed[key] = fmt.Errorf("checksum mismatch")
+				continue
+			}
+			if opts.DryRun {
+				report.Planned = append(report.Planned, e)
+				continue
+			}
+			if err := h.restore(e.Name, file, opts.Force); err != nil {
+				report.Failed[key] = err
+				continue
+			}
+			report.Restored = append(report.Restored, e)
+		}
+		if err := keep(); err != nil {
+			return report, fmt.Errorf("%s: %w", kind, err)
+		}
+	}
+	return report, nil
+}
+
+func containsKind(kinds []Kind, k Kind) bool {
+	for _, kind := range kinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
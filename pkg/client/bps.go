@@ -20,6 +20,9 @@ import (
 const (
 	ClientVersion = "11.0"
 	APIVersion    = "v2"
+
+	CacheEntries = 4096
+	CacheTTL     = 30 * time.Second
 )
 
 var (
@@ -42,6 +45,11 @@ type BPS struct {
 	ProfilingData    map[string]map[string][]float64
 	profilingMutex   sync.RWMutex
 
+	// Cache is shared by the data model proxies and invalidated by every
+	// write going through the client.
+	Cache  *models.Cache
+	schema *models.Schema
+
 	Results        *models.DataModelProxy
 	Capture        *models.DataModelProxy
 	Administration *models.DataModelProxy
@@ -81,6 +89,7 @@ func NewBPS(host, user, password string, checkVersion bool) *BPS {
 		PrintRequests:    false,
 		ProfilingEnabled: false,
 		ProfilingData:    make(map[string]map[string][]float64),
+		Cache:            models.NewCache(CacheEntries, CacheTTL),
 	}
 
 	bps.Results = models.NewDataModelProxy(bps, "results", "")
@@ -91,6 +100,9 @@ func NewBPS(host, user, password string, checkVersion bool) *BPS {
 	bps.Network = models.NewDataModelProxy(bps, "network", "")
 	bps.EvasionProfile = models.NewDataModelProxy(bps, "evasionProfile", "")
 	bps.Remote = models.NewDataModelProxy(bps, "remote", "")
+	for _, p := range bps.proxies() {
+		p.SetCache(bps.Cache)
+	}
 
 	bps.StrikeList = &operations.StrikeListOps{Client: bps}
 	bps.Strikes = &operations.StrikesOps{Client: bps}
@@ -205,6 +217,94 @@ func (b *BPS) disconnect() {
 	}
 }
 
+// BaseURL returns the root URL of the v2 core API.
+func (b *BPS) BaseURL() string {
+	return fmt.Sprintf("https://%s/bps/api/v2/core", b.Host)
+}
+
+// LoadSchema reads the data model from the chassis and attaches the
+// inferred schema to the data model proxies, so that unknown fields and
+// invalid values are rejected before any request is sent.
+func (b *BPS) LoadSchema(depth int) error {
+	schema, err := models.FetchSchema(b, depth)
+	if err != nil {
+		return err
+	}
+	b.SetSchema(schema)
+	return nil
+}
+
+// SetSchema attaches schema to the data model proxies.
+func (b *BPS) SetSchema(schema *models.Schema) {
+	b.schema = schema
+	for _, p := range b.proxies() {
+		p.SetSchema(schema)
+	}
+}
+
+// Path returns the proxy addressed by a path expression starting at a data
+// model root, such as "network/networkModel/ip_router[0]/default_gateway"
+// or the JSON pointer "/network/networkModel/ip_router/0/default_gateway".
+func (b *BPS) Path(expr string) *models.DataModelProxy {
+	root, rest := b.splitRoot(expr)
+	return root.Path(rest)
+}
+
+// Select returns every node matched by a path expression starting at a
+// data model root, such as "testmodel/component[*]/active".
+func (b *BPS) Select(expr string) ([]models.Match, error) {
+	root, rest := b.splitRoot(expr)
+	return root.Select(rest)
+}
+
+// SetAll writes value to every node matched by a path expression starting
+// at a data model root and returns the number of nodes written.
+func (b *BPS) SetAll(expr string, value interface{}) (int, error) {
+	root, rest := b.splitRoot(expr)
+	return root.SetAll(rest, value)
+}
+
+func (b *BPS) splitRoot(expr string) (*models.DataModelProxy, string) {
+	pointer := strings.HasPrefix(expr, "/")
+	parts := strings.SplitN(strings.TrimPrefix(expr, "/"), "/", 2)
+	name, rest := parts[0], ""
+	if len(parts) == 2 {
+		rest = parts[1]
+	}
+	if pointer && rest != "" {
+		rest = "/" + rest
+	}
+	if i := strings.Index(name, "["); i >= 0 {
+		name, rest = name[:i], name[i:]+"/"+rest
+	}
+	root := models.NewDataModelProxy(b, name, "")
+	root.SetCache(b.Cache)
+	root.SetSchema(b.schema)
+	return root, rest
+}
+
+func (b *BPS) proxies() []*models.DataModelProxy {
+	return []*models.DataModelProxy{
+		b.Results, b.Capture, b.Administration, b.Topology,
+		b.LoadProfile, b.Network, b.EvasionProfile, b.Remote,
+	}
+}
+
+// invalidate drops cached values affected by a write to path. Operations
+// such as /testmodel/operations/load replace the whole working object, so
+// the root of their path is dropped. Writes call it once the request has
+// completed, failed ones included since they may have partly applied, so
+// that a read racing the write cannot cache the old value again.
+func (b *BPS) invalidate(path string) {
+	if b.Cache == nil {
+		return
+	}
+	if i := strings.Index(path, "/operations/"); i >= 0 {
+		path = strings.SplitN(strings.TrimPrefix(path[:i], "/"), "/", 2)[0]
+	}
+	b.Cache.Invalidate(path)
+}
+
 func (b *BPS) Get(path string, depth *int, params map[string]string) (interface{}, error) {
 	if b.ProfilingEnabled {
 		start := time.Now()
@@ -233,6 +333,7 @@ func (b *BPS) Post(path string, data interface{}) (interface{}, error) {
 		start := time.Now()
 		defer b.recordTiming("Post", path, time.Since(start))
 	}
+	defer b.invalidate(path)
 	url := fmt.Sprintf("https://%s/bps/api/v2/core/%s", b.Host, path)
 	resp, err := b.Client.R().
 		SetHeader("Content-Type", "application/json").
@@ -251,6 +352,7 @@ func (b *BPS) Post(path string, data interface{}) (interface{}, error) {
 }
 
 func (b *BPS) Put(path string, value interface{}) error {
+	defer b.invalidate(path)
 	url := fmt.Sprintf("https://%s/bps/api/v2/core/%s", b.Host, path)
 	resp, err := b.Client.R().
 		SetHeader("Content-Type", "application/json").
@@ -266,6 +368,7 @@ func (b *BPS) Put(path string, value interface{}) error {
 }
 
 func (b *BPS) Patch(path string, value interface{}) error {
+	defer b.invalidate(path)
 	url := fmt.Sprintf("https://%s/bps/api/v2/core/%s", b.Host, path)
 	resp, err := b.Client.R().
 		SetHeader("Content-Type", "application/json").
@@ -281,6 +384,7 @@ func (b *BPS) Patch(path string, value interface{}) error {
 }
 
 func (b *BPS) Delete(path string) (interface{}, error) {
+	defer b.invalidate(path)
 	url := fmt.Sprintf("https://%s/bps/api/v2/core/%s", b.Host, path)
 	resp, err := b.Client.R().
 		SetHeader("Content-Type", "application/json").
@@ -311,6 +415,7 @@ func (b *BPS) Export(path, file string, params map[string]interface{}) error {
 }
 
 func (b *BPS) Import(path, filename string, params map[string]interface{}) (interface{}, error) {
+	defer b.invalidate(path)
 	url := fmt.Sprintf("https://%s/bps/api/v2/core/%s", b.Host, path)
 	file, err := os.Open(filename)
 	if err != nil {
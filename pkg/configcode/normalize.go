@@ -0,0 +1,142 @@
+package configcode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"bps-client-go/pkg/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the file format objects are written in.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// volatileFields change on every save and are left out of exported files.
+var volatileFields = map[string]bool{
+	"revision":     true,
+	"createdOn":    true,
+	"createdBy":    true,
+	"lastModified": true,
+	"lockedBy":     true,
+}
+
+// unorderedLists names the lists whose order carries no meaning: the
+// element lists of a network model and the components of a test model.
+// Everything else, such as phases, actions and timelines, keeps its order.
+var unorderedLists = map[string]bool{
+	"component":                   true,
+	models.ElementInterface:       true,
+	models.ElementVLAN:            true,
+	models.ElementIPStaticHosts:   true,
+	models.ElementIPv6StaticHosts: true,
+	models.ElementIPDHCPHosts:     true,
+	models.ElementIPDHCPServer:    true,
+	models.ElementIPRouter:        true,
+	models.ElementIPv6Router:      true,
+	models.ElementIPsecRouter:     true,
+	models.ElementGGSN:            true,
+	models.ElementSGSN:            true,
+}
+
+// Normalize strips volatile fields from v and orders the unordered lists
+// of objects by their id so that repeated exports of the same object are
+// identical.
+func Normalize(v interface{}) interface{} {
+	return normalize("", v)
+}
+
+func normalize(key string, v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			if volatileFields[k] {
+				continue
+			}
+			out[k] = normalize(k, item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			out[i] = normalize("", item)
+		}
+		if unorderedLists[key] && allHaveIDs(out) {
+			sort.SliceStable(out, func(i, j int) bool { return idLess(out[i], out[j]) })
+		}
+		return out
+	}
+	return v
+}
+
+// idLess orders objects by id, numerically when both ids are numbers.
+func idLess(a, b interface{}) bool {
+	x, y := objectID(a), objectID(b)
+	fx, errX := strconv.ParseFloat(x, 64)
+	fy, errY := strconv.ParseFloat(y, 64)
+	if errX == nil && errY == nil {
+		return fx < fy
+	}
+	return x < y
+}
+
+// allHaveIDs reports whether every element is an object carrying an id.
+func allHaveIDs(list []interface{}) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m["id"]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func objectID(v interface{}) string {
+	if m, ok := v.(map[string]interface{}); ok {
+		return fmt.Sprintf("%v", m["id"])
+	}
+	return ""
+}
+
+func (f Format) ext() string {
+	if f == FormatYAML {
+		return ".yaml"
+	}
+	return ".json"
+}
+
+func (f Format) marshal(v interface{}) ([]byte, error) {
+	if f == FormatYAML {
+		return yaml.Marshal(v)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func (f Format) unmarshal(data []byte) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	var err error
+	if f == FormatYAML {
+		err = yaml.Unmarshal(data, &out)
+	} else {
+		err = json.Unmarshal(data, &out)
+	}
+	return out, err
+}
@@ -0,0 +1,227 @@
+// Package configcode keeps test models and network configs as normalized
+// JSON or YAML files that can be reviewed in version control, and applies
+// edited files back to the chassis.
+//
+// A tree has the following layout:
+//
+//	testmodels/<name>/model.yaml
+//	testmodels/<name>/components/<id>.yaml
+//	networks/<name>.yaml
+package configcode
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"bps-client-go/pkg/models"
+	"bps-client-go/pkg/operations"
+)
+
+// fullDepth is deep enough to return every node of a test model or network.
+const fullDepth = 100
+
+// Tree is a directory of configuration files mirroring chassis objects.
+type Tree struct {
+	Client operations.ClientWrapper
+	Dir    string
+	Format Format
+}
+
+// ApplyReport lists what an apply changed and what it could not reconcile.
+type ApplyReport struct {
+	Updated []string
+	// Missing are components present in the files but not in the model.
+	Missing []string
+	// Extra are components present in the model but not in the files.
+	Extra []string
+}
+
+func (t *Tree) format() Format {
+	if t.Format == "" {
+		return FormatYAML
+	}
+	return t.Format
+}
+
+func (t *Tree) modelDir(name string) string {
+	return filepath.Join(t.Dir, "testmodels", models.FileName(name))
+}
+
+func (t *Tree) networkFile(name string) string {
+	return filepath.Join(t.Dir, "networks", models.FileName(name)+t.format().ext())
+}
+
+// ExportModel loads the test model name and writes it to the tree, one
+// file for the model and one per component.
+func (t *Tree) ExportModel(name string) error {
+	ops := &operations.TestModelOps{Client: t.Client}
+	if _, err := ops.Load(name, false); err != nil {
+		return fmt.Errorf("load test model %s: %w", name, err)
+	}
+	model, err := t.get(models.NewDataModelProxy(t.Client, "testmodel", ""))
+	if err != nil {
+		return fmt.Errorf("read test model %s: %w", name, err)
+	}
+	components, _ := model["component"].([]interface{})
+	delete(model, "component")
+
+	dir := t.modelDir(name)
+	compDir := filepath.Join(dir, "components")
+	if err := os.RemoveAll(compDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(compDir, 0o755); err != nil {
+		return err
+	}
+	if err := t.write(filepath.Join(dir, "model"+t.format().ext()), model); err != nil {
+		return err
+	}
+	for _, c := range components {
+		comp, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id := objectID(comp)
+		if err := t.write(filepath.Join(compDir, models.FileName(id)+t.format().ext()), comp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportNetwork loads the network config name and writes it to the tree.
+func (t *Tree) ExportNetwork(name string) error {
+	ops := &operations.NetworkOps{Client: t.Client}
+	if _, err := ops.Load(name); err != nil {
+		return fmt.Errorf("load network %s: %w", name, err)
+	}
+	network, err := t.get(models.NewDataModelProxy(t.Client, "network", ""))
+	if err != nil {
+		return fmt.Errorf("read network %s: %w", name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(t.networkFile(name)), 0o755); err != nil {
+		return err
+	}
+	return t.write(t.networkFile(name), network)
+}
+
+// ApplyModel loads the test model name, patches the model and its
+// components with the content of the tree and saves it.
+func (t *Tree) ApplyModel(name string) (*ApplyReport, error) {
+	dir := t.modelDir(name)
+	model, err := t.read(filepath.Join(dir, "model"+t.format().ext()))
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "components", "*"+t.format().ext()))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	ops := &operations.TestModelOps{Client: t.Client}
+	if _, err := ops.Load(name, false); err != nil {
+		return nil, fmt.Errorf("load test model %s: %w", name, err)
+	}
+	proxy := models.NewDataModelProxy(t.Client, "testmodel", "")
+	current, err := t.get(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("read test model %s: %w", name, err)
+	}
+	existing := make(map[string]bool)
+	if comps, ok := current["component"].([]interface{}); ok {
+		for _, c := range comps {
+			existing[objectID(c)] = true
+		}
+	}
+
+	report := &ApplyReport{}
+	delete(model, "name")
+	if err := proxy.Set(model); err != nil {
+		return report, fmt.Errorf("update test model %s: %w", name, err)
+	}
+	report.Updated = append(report.Updated, "model")
+
+	for _, file := range files {
+		comp, err := t.read(file)
+		if err != nil {
+			return report, err
+		}
+		id := objectID(comp)
+		if !existing[id] {
+			report.Missing = append(report.Missing, id)
+			continue
+		}
+		delete(existing, id)
+		delete(comp, "id")
+		if err := proxy.GetField("component").GetItem(id).Set(comp); err != nil {
+			return report, fmt.Errorf("update component %s: %w", id, err)
+		}
+		report.Updated = append(report.Updated, "component/"+id)
+	}
+	for id := range existing {
+		report.Extra = append(report.Extra, id)
+	}
+	sort.Strings(report.Extra)
+
+	if _, err := ops.Save(name, true); err != nil {
+		return report, fmt.Errorf("save test model %s: %w", name, err)
+	}
+	return report, nil
+}
+
+// ApplyNetwork loads the network config name, replaces its content with
+// the file in the tree and saves it.
+func (t *Tree) ApplyNetwork(name string) error {
+	network, err := t.read(t.networkFile(name))
+	if err != nil {
+		return err
+	}
+	ops := &operations.NetworkOps{Client: t.Client}
+	if _, err := ops.Load(name); err != nil {
+		return fmt.Errorf("load network %s: %w", name, err)
+	}
+	delete(network, "name")
+	if err := models.NewDataModelProxy(t.Client, "network", "").Put(network); err != nil {
+		return fmt.Errorf("update network %s: %w", name, err)
+	}
+	if _, err := ops.Save(&name, true); err != nil {
+		return fmt.Errorf("save network %s: %w", name, err)
+	}
+	return nil
+}
+
+func (t *Tree) get(proxy *models.DataModelProxy) (map[string]interface{}, error) {
+	depth := fullDepth
+	resp, err := proxy.Get(&depth, nil)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := Normalize(resp).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type: %#v", resp)
+	}
+	return obj, nil
+}
+
+func (t *Tree) write(path string, v interface{}) error {
+	data, err := t.format().marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (t *Tree) read(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := t.format().unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return obj, nil
+}
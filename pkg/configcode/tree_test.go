@@ -0,0 +1,256 @@
+package configcode
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"bps-client-go/pkg/models"
+)
+
+// fakeChassis keeps saved test models and networks and the working copy
+// each is loaded into
+type fakeChassis struct {
+	saved   map[string]map[string]map[string]interface{}
+	working map[string]map[string]interface{}
+}
+
+func newChassis() *fakeChassis {
+	return &fakeChassis{
+		saved: map[string]map[string]map[string]interface{}{
+			"testmodel": {"web/mail": {
+				"name": "web/mail", "description": "baseline", "revision": 7,
+				"component": []interface{}{
+					map[string]interface{}{"id": "appsim_2", "label": "mail", "active": false},
+					map[string]interface{}{"id": "appsim_1", "label": "web", "active": true,
+						"timeline": map[string]interface{}{"timesegment": []interface{}{
+							map[string]interface{}{"type": "RampUpSegment", "duration": 10},
+							map[string]interface{}{"type": "SteadySegment", "duration": 60},
+						}}},
+				},
+			}},
+			"network": {"lab": {
+				"name": "lab", "createdBy": "admin",
+				"interface": []interface{}{
+					map[string]interface{}{"id": "Interface 2", "number": 2},
+					map[string]interface{}{"id": "Interface 1", "number": 1},
+				},
+			}},
+		},
+		working: map[string]map[string]interface{}{},
+	}
+}
+
+func clone(v map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(v)
+	var out map[string]interface{}
+	json.Unmarshal(data, &out)
+	return out
+}
+
+func (c *fakeChassis) Get(path string, responseDepth *int, params map[string]string) (interface{}, error) {
+	obj, ok := c.working[strings.TrimPrefix(path, "/")]
+	if !ok {
+		return nil, fmt.Errorf("GET %s: not found", path)
+	}
+	return clone(obj), nil
+}
+
+func (c *fakeChassis) Post(path string, data interface{}) (interface{}, error) {
+	params := data.(map[string]interface{})
+	kind, op, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/operations/")
+	switch op {
+	case "load":
+		obj, ok := c.saved[kind][params["template"].(string)]
+		if !ok {
+			return nil, fmt.Errorf("no %s %v", kind, params["template"])
+		}
+		c.working[kind] = clone(obj)
+	case "save":
+		name := params["name"].(string)
+		obj := clone(c.working[kind])
+		obj["name"] = name
+		c.saved[kind][name] = obj
+	default:
+		return nil, fmt.Errorf("POST %s: not found", path)
+	}
+	return nil, nil
+}
+
+func (c *fakeChassis) Put(path string, value interface{}) error {
+	kind := strings.TrimPrefix(path, "/")
+	obj := clone(value.(map[string]interface{}))
+	obj["name"] = c.working[kind]["name"]
+	c.working[kind] = obj
+	return nil
+}
+
+func (c *fakeChassis) Patch(path string, value interface{}) error {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	target := c.working[parts[0]]
+	if len(parts) == 3 {
+		target = nil
+		for _, item := range c.working[parts[0]][parts[1]].([]interface{}) {
+			if m := item.(map[string]interface{}); m["id"] == parts[2] {
+				target = m
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("PATCH %s: not found", path)
+		}
+	}
+	for k, v := range value.(map[string]interface{}) {
+		target[k] = v
+	}
+	return nil
+}
+
+func (c *fakeChassis) Delete(path string) (interface{}, error) { return nil, nil }
+func (c *fakeChassis) Export(path, filepath string, params map[string]interface{}) error {
+	return nil
+}
+func (c *fakeChassis) Import(path, filename string, params map[string]interface{}) (interface{}, error) {
+	return nil, nil
+}
+func (c *fakeChassis) EnableProfiling(enabled bool) {}
+func (c *fakeChassis) PrintVersions()               {}
+func (c *fakeChassis) PrintProfilingData()          {}
+
+// readTree returns the content of every file below dir by slash path
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	out := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		rel, _ := filepath.Rel(dir, path)
+		out[filepath.ToSlash(rel)] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// edit rewrites file in the tree with change applied to its object
+func edit(t *testing.T, tree *Tree, file string, change func(obj map[string]interface{})) {
+	t.Helper()
+	obj, err := tree.read(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	change(obj)
+	if err := tree.write(file, obj); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestModelRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			c := newChassis()
+			tree := &Tree{Client: c, Dir: t.TempDir(), Format: format}
+			if err := tree.ExportModel("web/mail"); err != nil {
+				t.Fatal(err)
+			}
+			dir := "testmodels/" + models.FileName("web/mail") + "/"
+			ext := format.ext()
+			files := readTree(t, tree.Dir)
+			want := []string{dir + "components/appsim_1" + ext, dir + "components/appsim_2" + ext, dir + "model" + ext}
+			if names := sortedKeys(files); !reflect.DeepEqual(names, want) {
+				t.Fatalf("exported %v, want %v", names, want)
+			}
+			if strings.Contains(files[dir+"model"+ext], "revision") {
+				t.Errorf("volatile field exported:\n%s", files[dir+"model"+ext])
+			}
+
+			edit(t, tree, filepath.Join(tree.Dir, dir, "model"+ext), func(obj map[string]interface{}) {
+				obj["description"] = "edited"
+			})
+			edit(t, tree, filepath.Join(tree.Dir, dir, "components", "appsim_2"+ext), func(obj map[string]interface{}) {
+				obj["active"] = true
+			})
+			if err := tree.write(filepath.Join(tree.Dir, dir, "components", "appsim_9"+ext), map[string]interface{}{"id": "appsim_9"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Remove(filepath.Join(tree.Dir, dir, "components", "appsim_1"+ext)); err != nil {
+				t.Fatal(err)
+			}
+			edited := readTree(t, tree.Dir)
+
+			report, err := tree.ApplyModel("web/mail")
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantReport := &ApplyReport{
+				Updated: []string{"model", "component/appsim_2"},
+				Missing: []string{"appsim_9"},
+				Extra:   []string{"appsim_1"},
+			}
+			if !reflect.DeepEqual(report, wantReport) {
+				t.Errorf("ApplyModel() = %+v, want %+v", report, wantReport)
+			}
+			if got := c.saved["testmodel"]["web/mail"]["description"]; got != "edited" {
+				t.Errorf("saved description = %v", got)
+			}
+
+			// Exporting again reproduces the edited files, apart from the
+			// component the model lacks and the one the files dropped
+			if err := tree.ExportModel("web/mail"); err != nil {
+				t.Fatal(err)
+			}
+			again := readTree(t, tree.Dir)
+			delete(edited, dir+"components/appsim_9"+ext)
+			delete(again, dir+"components/appsim_1"+ext)
+			if !reflect.DeepEqual(again, edited) {
+				t.Errorf("re-export differs from the applied files:\n%v\n%v", again, edited)
+			}
+		})
+	}
+}
+
+func TestNetworkRoundTrip(t *testing.T) {
+	c := newChassis()
+	tree := &Tree{Client: c, Dir: t.TempDir()}
+	if err := tree.ExportNetwork("lab"); err != nil {
+		t.Fatal(err)
+	}
+	file := tree.networkFile("lab")
+	exported := readTree(t, tree.Dir)["networks/lab.yaml"]
+	if strings.Contains(exported, "createdBy") || strings.Index(exported, "Interface 1") > strings.Index(exported, "Interface 2") {
+		t.Errorf("network not normalized:\n%s", exported)
+	}
+	edit(t, tree, file, func(obj map[string]interface{}) {
+		obj["description"] = "edited"
+	})
+	edited := readTree(t, tree.Dir)
+	if err := tree.ApplyNetwork("lab"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.ExportNetwork("lab"); err != nil {
+		t.Fatal(err)
+	}
+	if again := readTree(t, tree.Dir); !reflect.DeepEqual(again, edited) {
+		t.Errorf("re-export differs from the applied file:\n%s\n%s", again["networks/lab.yaml"], edited["networks/lab.yaml"])
+	}
+	if got := c.saved["network"]["lab"]["name"]; got != "lab" {
+		t.Errorf("saved network name = %v", got)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// App profile weight types: weights share out either the bandwidth or the
+// number of flows
+const (
+	WeightBandwidth = "bandwidth"
+	WeightFlows     = "flows"
+)
+
+// NewAppProfile returns an empty app profile weighted by weightType
+func NewAppProfile(name, weightType string) *AppProfile {
+	return &AppProfile{Name: name, WeightType: weightType}
+}
+
+// Entry returns the entry of superflow, or nil
+func (p *AppProfile) Entry(superflow string) *AppProfileSuperflow {
+	for i := range p.Superflow {
+		if p.Superflow[i].Superflow == superflow {
+			return &p.Superflow[i]
+		}
+	}
+	return nil
+}
+
+// Set adds superflow with weight, or changes its weight if the profile
+// already has it
+func (p *AppProfile) Set(superflow string, weight int) *AppProfile {
+	if e := p.Entry(superflow); e != nil {
+		e.Weight = weight
+		return p
+	}
+	p.Superflow = append(p.Superflow, AppProfileSuperflow{Superflow: superflow, Weight: weight})
+	return p
+}
+
+// SetSeed fixes the random seed of superflow; zero lets the chassis pick
+func (p *AppProfile) SetSeed(superflow string, seed int) error {
+	e := p.Entry(superflow)
+	if e == nil {
+		return fmt.Errorf("app profile %s has no superflow %s", p.Name, superflow)
+	}
+	e.Seed = seed
+	return nil
+}
+
+// Remove drops superflow and reports whether the profile had it
+func (p *AppProfile) Remove(superflow string) bool {
+	for i := range p.Superflow {
+		if p.Superflow[i].Superflow == superflow {
+			p.Superflow = append(p.Superflow[:i], p.Superflow[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Names lists the superflows of the profile in order
+func (p *AppProfile) Names() []string {
+	names := make([]string, len(p.Superflow))
+	for i, e := range p.Superflow {
+		names[i] = e.Superflow
+	}
+	return names
+}
+
+// TotalWeight sums the weights of the profile
+func (p *AppProfile) TotalWeight() int {
+	total := 0
+	for _, e := range p.Superflow {
+		total += e.Weight
+	}
+	return total
+}
+
+// Share returns the fraction of the mix superflow gets
+func (p *AppProfile) Share(superflow string) float64 {
+	e := p.Entry(superflow)
+	total := p.TotalWeight()
+	if e == nil || total == 0 {
+		return 0
+	}
+	return float64(e.Weight) / float64(total)
+}
+
+// Normalize scales the weights so they add up to total while keeping
+// their proportions. Rounding leftovers go to the largest remainders and
+// no superflow drops to zero.
+func (p *AppProfile) Normalize(total int) error {
+	sum := p.TotalWeight()
+	if sum <= 0 {
+		return fmt.Errorf("app profile %s has no weight to normalize", p.Name)
+	}
+	if total < len(p.Superflow) {
+		return fmt.Errorf("cannot share %d among %d superflows", total, len(p.Superflow))
+	}
+	weights := make([]float64, len(p.Superflow))
+	for i, e := range p.Superflow {
+		weights[i] = float64(e.Weight)
+	}
+	for i, w := range Apportion(weights, total) {
+		p.Superflow[i].Weight = w
+	}
+	return nil
+}
+
+// Apportion shares total among weights by the largest remainder method,
+// giving every positive weight at least 1
+func Apportion(weights []float64, total int) []int {
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	out := make([]int, len(weights))
+	if sum <= 0 {
+		return out
+	}
+	type rest struct {
+		index int
+		frac  float64
+	}
+	var rests []rest
+	given := 0
+	for i, w := range weights {
+		exact := w / sum * float64(total)
+		out[i] = int(math.Floor(exact))
+		if w > 0 && out[i] == 0 {
+			out[i] = 1
+		}
+		given += out[i]
+		rests = append(rests, rest{i, exact - math.Floor(exact)})
+	}
+	sort.SliceStable(rests, func(a, b int) bool { return rests[a].frac > rests[b].frac })
+	for i := 0; given < total; i = (i + 1) % len(rests) {
+		out[rests[i].index]++
+		given++
+	}
+	// The minimum of 1 can overshoot; take it back from the largest weights.
+	for given > total {
+		largest := 0
+		for i := range out {
+			if out[i] > out[largest] {
+				largest = i
+			}
+		}
+		if out[largest] <= 1 {
+			break
+		}
+		out[largest]--
+		given--
+	}
+	return out
+}
+
+// Validate checks the profile for an unknown weight type, an empty mix,
+// unnamed or repeated superflows and weights that are not positive
+func (p *AppProfile) Validate() error {
+	var errs []error
+	if p.WeightType != WeightBandwidth && p.WeightType != WeightFlows {
+		errs = append(errs, fmt.Errorf("weight type %q is neither %s nor %s", p.WeightType, WeightBandwidth, WeightFlows))
+	}
+	if len(p.Superflow) == 0 {
+		errs = append(errs, fmt.Errorf("no superflows"))
+	}
+	seen := make(map[string]bool)
+	for i, e := range p.Superflow {
+		switch {
+		case e.Superflow == "":
+			errs = append(errs, fmt.Errorf("entry %d has no superflow", i+1))
+		case seen[e.Superflow]:
+			errs = append(errs, fmt.Errorf("superflow %s listed twice", e.Superflow))
+		}
+		seen[e.Superflow] = true
+		if e.Weight <= 0 {
+			errs = append(errs, fmt.Errorf("superflow %s has weight %d", e.Superflow, e.Weight))
+		}
+	}
+	return joinErrors(errs)
+}
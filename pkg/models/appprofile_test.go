@@ -0,0 +1,72 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApportion(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []float64
+		total   int
+		want    []int
+	}{
+		{"even", []float64{1, 1, 1, 1}, 100, []int{25, 25, 25, 25}},
+		{"largest remainder", []float64{2, 1}, 4, []int{3, 1}},
+		{"ties go first come", []float64{1, 1, 1}, 100, []int{34, 33, 33}},
+		{"tiny weight keeps 1", []float64{1000, 1}, 10, []int{9, 1}},
+		{"minimum taken from the largest", []float64{1, 1, 1, 1000}, 10, []int{1, 1, 1, 7}},
+		{"zero weight stays zero", []float64{0, 1}, 5, []int{0, 5}},
+		{"no weight", []float64{0, 0}, 10, []int{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Apportion(tt.weights, tt.total); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apportion(%v, %d) = %v, want %v", tt.weights, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppProfileNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []int
+		total   int
+		want    []int
+		wantErr string
+	}{
+		{"scale up", []int{1, 3}, 100, []int{25, 75}, ""},
+		{"scale down", []int{50, 30, 20}, 10, []int{5, 3, 2}, ""},
+		{"already normalized", []int{60, 40}, 100, []int{60, 40}, ""},
+		{"no weight", []int{0, 0}, 100, nil, "no weight"},
+		{"total too small", []int{1, 1, 1}, 2, nil, "cannot share 2 among 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewAppProfile("mix", WeightBandwidth)
+			for i, w := range tt.weights {
+				p.Set(string(rune('a'+i)), w)
+			}
+			err := p.Normalize(tt.total)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Normalize(%d) error = %v, want %q", tt.total, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, e := range p.Superflow {
+				got = append(got, e.Weight)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize(%d) weights = %v, want %v", tt.total, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"container/list"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheEntries is the size of the cache created for a proxy that
+// is not given one
+const DefaultCacheEntries = 1024
+
+// Cache is a size bounded, least recently used cache of data model values
+// keyed by API path. It is safe for concurrent use and is meant to be
+// shared by all proxies of a client.
+type Cache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	// lists holds the paths of filled lists, whose items are cached under
+	// both their index and their id
+	lists map[string]bool
+	now   func() time.Time
+}
+
+type cacheEntry struct {
+	path    string
+	value   interface{}
+	expires time.Time
+}
+
+// NewCache creates a cache holding at most maxEntries values for ttl each.
+// A zero ttl keeps values until they are evicted or invalidated.
+func NewCache(maxEntries int, ttl time.Duration) *Cache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	return &Cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		lists:      make(map[string]bool),
+		now:        time.Now,
+	}
+}
+
+// Get returns the value cached for path
+func (c *Cache) Get(path string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[cleanPath(path)]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && c.now().After(entry.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return entry.value, true
+}
+
+// Set stores value for path
+func (c *Cache) Set(path string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(cleanPath(path), value)
+}
+
+func (c *Cache) set(path string, value interface{}) {
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = c.now().Add(c.ttl)
+	}
+	if el, ok := c.entries[path]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.value = value
+		entry.expires = expires
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[path] = c.lru.PushFront(&cacheEntry{path: path, value: value, expires: expires})
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Invalidate drops path, every path below it and every path above it,
+// since the values cached for parents embed the changed subtree. A path
+// below a filled list drops the whole list, as its items are also cached
+// under another alias.
+func (c *Cache) Invalidate(path string) {
+	path = cleanPath(path)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for l := range c.lists {
+		if strings.HasPrefix(path, l+"/") && len(l) < len(path) {
+			path = l
+		}
+	}
+	for l := range c.lists {
+		if path == "/" || l == path || strings.HasPrefix(l, path+"/") {
+			delete(c.lists, l)
+		}
+	}
+	for key, el := range c.entries {
+		if path == "/" || key == path || strings.HasPrefix(key, path+"/") || strings.HasPrefix(path, key+"/") {
+			c.remove(el)
+		}
+	}
+}
+
+// Clear drops every cached value
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lists = make(map[string]bool)
+	c.lru.Init()
+}
+
+// Len returns the number of cached values
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// fill caches value at path and every node below it down to depth levels
+func (c *Cache) fill(path string, value interface{}, depth int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fillLocked(cleanPath(path), value, depth)
+}
+
+func (c *Cache) fillLocked(path string, value interface{}, depth int) {
+	c.set(path, value)
+	if depth <= 1 {
+		return
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			c.fillLocked(path+"/"+k, child, depth-1)
+		}
+	case []interface{}:
+		c.lists[path] = true
+		for i, child := range v {
+			c.fillLocked(path+"/"+strconv.Itoa(i), child, depth-1)
+			if m, ok := child.(map[string]interface{}); ok {
+				if id, ok := m["id"]; ok && id != nil {
+					c.fillLocked(path+"/"+fmt.Sprintf("%v", id), child, depth-1)
+				}
+			}
+		}
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	delete(c.entries, el.Value.(*cacheEntry).path)
+	c.lru.Remove(el)
+}
+
+func cleanPath(path string) string {
+	return "/" + strings.Join(splitPath(path), "/")
+}
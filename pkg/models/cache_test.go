@@ -0,0 +1,137 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func cachedPaths(c *Cache) []string {
+	var paths []string
+	for p := range c.entries {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	network := map[string]interface{}{
+		"name": "net",
+		"ip_router": []interface{}{
+			map[string]interface{}{"id": "r1", "ip_address": "10.0.0.1"},
+		},
+	}
+	tests := []struct {
+		name       string
+		invalidate string
+		want       []string
+	}{
+		{
+			name:       "index alias drops the id alias",
+			invalidate: "/network/ip_router/0/ip_address",
+			want:       []string{"/network/name"},
+		},
+		{
+			name:       "id alias drops the index alias",
+			invalidate: "/network/ip_router/r1",
+			want:       []string{"/network/name"},
+		},
+		{
+			name:       "sibling field is kept",
+			invalidate: "/network/name",
+			want: []string{
+				"/network/ip_router",
+				"/network/ip_router/0",
+				"/network/ip_router/0/id",
+				"/network/ip_router/0/ip_address",
+				"/network/ip_router/r1",
+				"/network/ip_router/r1/id",
+				"/network/ip_router/r1/ip_address",
+			},
+		},
+		{
+			name:       "root drops everything",
+			invalidate: "/",
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCache(0, 0)
+			c.fill("/network", network, FullDepth)
+			c.Invalidate(tt.invalidate)
+			if got := cachedPaths(c); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cached after Invalidate(%s) = %v, want %v", tt.invalidate, got, tt.want)
+			}
+		})
+	}
+}
+
+// racingWrapper caches a stale value while a write is in flight, as a
+// concurrent reader would
+type racingWrapper struct {
+	BPSWrapper
+	cache *Cache
+}
+
+func (w *racingWrapper) Patch(path string, value interface{}) error {
+	w.cache.Set(path, "stale")
+	return nil
+}
+
+func (w *racingWrapper) Put(path string, value interface{}) error {
+	w.cache.Set(path, "stale")
+	return nil
+}
+
+func (w *racingWrapper) Delete(path string) (interface{}, error) {
+	w.cache.Set(path, "stale")
+	return nil, nil
+}
+
+func TestProxyInvalidatesAfterWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(*DataModelProxy) error
+	}{
+		{"Set", func(p *DataModelProxy) error { return p.Set("x") }},
+		{"Put", func(p *DataModelProxy) error { return p.Put("x") }},
+		{"Delete", func(p *DataModelProxy) error { _, err := p.Delete(); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &racingWrapper{}
+			p := NewDataModelProxy(w, "name", "/network")
+			w.cache = p.cache
+			if err := tt.write(p); err != nil {
+				t.Fatal(err)
+			}
+			if v, ok := p.cache.Get("/network/name"); ok {
+				t.Errorf("cache holds %v after %s", v, tt.name)
+			}
+		})
+	}
+}
+
+func TestDerivedProxiesShareCache(t *testing.T) {
+	root := NewDataModelProxy(&racingWrapper{}, "network", "")
+	cache := NewCache(0, 0)
+	root.SetCache(cache)
+	tests := []struct {
+		name  string
+		proxy *DataModelProxy
+	}{
+		{"GetField", root.GetField("networkModel")},
+		{"GetItem", root.GetField("networkModel").GetField("ip_router").GetItem(0)},
+		{"Path", root.Path("networkModel/ip_router[0]/ip_address")},
+		{"failed Path", root.Path("networkModel/ip_router[")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.proxy.Cache() != cache {
+				t.Errorf("%s got a cache of its own", tt.proxy)
+			}
+		})
+	}
+}
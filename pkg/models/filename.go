@@ -0,0 +1,26 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// FileName maps an object name onto a portable file name. Names that need
+// characters replaced get a short hash of the original appended, so "a/b"
+// and "a_b" do not end up in the same file
+func FileName(name string) string {
+	out := []rune(name)
+	replaced := false
+	for i, r := range out {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			out[i] = '_'
+			replaced = true
+		}
+	}
+	if !replaced {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	return string(out) + "_" + hex.EncodeToString(sum[:4])
+}
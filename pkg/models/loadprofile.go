@@ -0,0 +1,311 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Load profile phase types
+const (
+	PhaseRampUp   = "up"
+	PhaseSteady   = "steady"
+	PhaseRampDown = "down"
+)
+
+// LoadPhase is one phase of a load profile. Settings without a field are
+// kept in Other so a profile survives a read-modify-write round trip
+// unchanged.
+type LoadPhase struct {
+	ID   int    `json:"phaseId,omitempty"`
+	Type string `json:"type,omitempty"`
+	// Duration is in seconds
+	Duration       int     `json:"duration"`
+	MaxSessions    int     `json:"sessions.max,omitempty"`
+	MaxSessionRate int     `json:"sessions.maxPerSecond,omitempty"`
+	Rate           float64 `json:"rateDist.min,omitempty"`
+	// RateUnit is the unit of Rate, such as "Mbps" or "fps"
+	RateUnit       string `json:"rateDist.unit,omitempty"`
+	UpBehavior     string `json:"rampDist.upBehavior,omitempty"`
+	SteadyBehavior string `json:"rampDist.steadyBehavior,omitempty"`
+	DownBehavior   string `json:"rampDist.downBehavior,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// loadPhaseFields aliases LoadPhase without its JSON methods
+type loadPhaseFields LoadPhase
+
+// UnmarshalJSON decodes the typed settings and keeps the others in Other
+func (p *LoadPhase) UnmarshalJSON(data []byte) error {
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, (*loadPhaseFields)(p)); err != nil {
+		return err
+	}
+	known := jsonKeys(reflect.TypeOf(loadPhaseFields{}))
+	p.Other = nil
+	for k, v := range all {
+		if !known[k] {
+			if p.Other == nil {
+				p.Other = make(map[string]interface{})
+			}
+			p.Other[k] = v
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the typed settings together with Other
+func (p LoadPhase) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(loadPhaseFields(p))
+	if err != nil || len(p.Other) == 0 {
+		return data, err
+	}
+	all, err := p.settings()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(all)
+}
+
+// settings returns every setting of the phase keyed by its JSON name
+func (p LoadPhase) settings() (map[string]interface{}, error) {
+	data, err := json.Marshal(loadPhaseFields(p))
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range p.Other {
+		if _, ok := all[k]; !ok {
+			all[k] = v
+		}
+	}
+	return all, nil
+}
+
+// Length returns the duration of the phase
+func (p LoadPhase) Length() time.Duration {
+	return time.Duration(p.Duration) * time.Second
+}
+
+// RateMbps converts Rate to megabits per second; ok is false for rates
+// that are not a bit rate, such as frames per second
+func (p LoadPhase) RateMbps() (float64, bool) {
+	switch strings.ToLower(p.RateUnit) {
+	case "bps":
+		return p.Rate / 1e6, true
+	case "kbps":
+		return p.Rate / 1e3, true
+	case "mbps", "":
+		return p.Rate, true
+	case "gbps":
+		return p.Rate * 1e3, true
+	}
+	return 0, false
+}
+
+// NewLoadProfile returns an empty load profile
+func NewLoadProfile(name string) *LoadProfile {
+	return &LoadProfile{Name: name}
+}
+
+// AddPhase appends phase, numbering it after the last phase
+func (l *LoadProfile) AddPhase(phase LoadPhase) *LoadProfile {
+	phase.ID = len(l.Phase) + 1
+	l.Phase = append(l.Phase, phase)
+	return l
+}
+
+// RampUp appends a phase ramping up to sessions concurrent sessions and
+// rate over d
+func (l *LoadProfile) RampUp(d time.Duration, sessions int, rate float64, unit string) *LoadProfile {
+	return l.AddPhase(LoadPhase{Type: PhaseRampUp, Duration: seconds(d), MaxSessions: sessions, Rate: rate, RateUnit: unit})
+}
+
+// Steady appends a phase holding sessions and rate for d
+func (l *LoadProfile) Steady(d time.Duration, sessions int, rate float64, unit string) *LoadProfile {
+	return l.AddPhase(LoadPhase{Type: PhaseSteady, Duration: seconds(d), MaxSessions: sessions, Rate: rate, RateUnit: unit})
+}
+
+// RampDown appends a phase closing every session over d
+func (l *LoadProfile) RampDown(d time.Duration) *LoadProfile {
+	return l.AddPhase(LoadPhase{Type: PhaseRampDown, Duration: seconds(d)})
+}
+
+func seconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// TotalDuration sums the durations of the phases
+func (l *LoadProfile) TotalDuration() time.Duration {
+	var total time.Duration
+	for _, p := range l.Phase {
+		total += p.Length()
+	}
+	return total
+}
+
+// PeakSessions returns the highest number of concurrent sessions of any
+// phase
+func (l *LoadProfile) PeakSessions() int {
+	peak := 0
+	for _, p := range l.Phase {
+		if p.MaxSessions > peak {
+			peak = p.MaxSessions
+		}
+	}
+	return peak
+}
+
+// PeakRate returns the highest rate of any phase in megabits per second
+func (l *LoadProfile) PeakRate() float64 {
+	peak := 0.0
+	for _, p := range l.Phase {
+		if r, ok := p.RateMbps(); ok && r > peak {
+			peak = r
+		}
+	}
+	return peak
+}
+
+// DataVolume estimates the bytes the profile sends. Steady phases run at
+// their rate; ramps are taken as linear from the rate of the previous
+// phase to their own, so a ramp down without a rate ends at zero. Ramp up
+// and steady phases limited by sessions alone have no volume to estimate
+// and are reported as an error.
+func (l *LoadProfile) DataVolume() (int64, error) {
+	total := 0.0
+	prev := 0.0
+	for i, p := range l.Phase {
+		rate, ok := p.RateMbps()
+		if !ok {
+			return 0, fmt.Errorf("phase %d: rate unit %s is not a bit rate", i+1, p.RateUnit)
+		}
+		if rate == 0 && p.Type != PhaseRampDown {
+			return 0, fmt.Errorf("phase %d: no rate, only sessions", i+1)
+		}
+		avg := rate
+		if p.Type == PhaseRampUp || p.Type == PhaseRampDown {
+			avg = (prev + rate) / 2
+		}
+		total += avg * 1e6 / 8 * float64(p.Duration)
+		prev = rate
+	}
+	return int64(total), nil
+}
+
+// Validate checks the phases for unknown types, non-positive durations,
+// negative loads and a ramp down that is not last
+func (l *LoadProfile) Validate() error {
+	var errs []error
+	if len(l.Phase) == 0 {
+		errs = append(errs, fmt.Errorf("no phases"))
+	}
+	for i, p := range l.Phase {
+		switch p.Type {
+		case PhaseRampUp, PhaseSteady:
+		case PhaseRampDown:
+			for _, later := range l.Phase[i+1:] {
+				if later.Type != PhaseRampDown {
+					errs = append(errs, fmt.Errorf("phase %d: ramp down followed by a %s phase", i+1, later.Type))
+					break
+				}
+			}
+		default:
+			errs = append(errs, fmt.Errorf("phase %d: unknown type %q", i+1, p.Type))
+		}
+		if p.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("phase %d: duration %d", i+1, p.Duration))
+		}
+		if p.MaxSessions < 0 || p.MaxSessionRate < 0 || p.Rate < 0 {
+			errs = append(errs, fmt.Errorf("phase %d: negative load", i+1))
+		}
+	}
+	return joinErrors(errs)
+}
+
+// ValidateSpeed checks that no phase asks for more than speed megabits
+// per second. Phases whose rate is not a bit rate are not checked.
+func (l *LoadProfile) ValidateSpeed(speed float64) error {
+	var errs []error
+	for i, p := range l.Phase {
+		if r, ok := p.RateMbps(); ok && r > speed {
+			errs = append(errs, fmt.Errorf("phase %d: rate %g Mbps exceeds port speed %g Mbps", i+1, r, speed))
+		}
+	}
+	return joinErrors(errs)
+}
+
+// LoadProfileChange is a setting that differs between two load profiles.
+// From is nil for an added phase or setting, To for a removed one.
+type LoadProfileChange struct {
+	Phase   int
+	Setting string
+	From    interface{}
+	To      interface{}
+}
+
+func (c LoadProfileChange) String() string {
+	if c.Setting == "" {
+		if c.From == nil {
+			return fmt.Sprintf("phase %d: added", c.Phase)
+		}
+		return fmt.Sprintf("phase %d: removed", c.Phase)
+	}
+	return fmt.Sprintf("phase %d %s: %v -> %v", c.Phase, c.Setting, c.From, c.To)
+}
+
+// Diff lists the differences from l to other phase by phase
+func (l *LoadProfile) Diff(other *LoadProfile) ([]LoadProfileChange, error) {
+	var changes []LoadProfileChange
+	n := len(l.Phase)
+	if len(other.Phase) > n {
+		n = len(other.Phase)
+	}
+	for i := 0; i < n; i++ {
+		switch {
+		case i >= len(l.Phase):
+			changes = append(changes, LoadProfileChange{Phase: i + 1, To: other.Phase[i]})
+			continue
+		case i >= len(other.Phase):
+			changes = append(changes, LoadProfileChange{Phase: i + 1, From: l.Phase[i]})
+			continue
+		}
+		from, err := l.Phase[i].settings()
+		if err != nil {
+			return nil, err
+		}
+		to, err := other.Phase[i].settings()
+		if err != nil {
+			return nil, err
+		}
+		keys := make(map[string]bool)
+		for k := range from {
+			keys[k] = true
+		}
+		for k := range to {
+			keys[k] = true
+		}
+		names := make([]string, 0, len(keys))
+		for k := range keys {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			if k == "phaseId" || reflect.DeepEqual(from[k], to[k]) {
+				continue
+			}
+			changes = append(changes, LoadProfileChange{Phase: i + 1, Setting: k, From: from[k], To: to[k]})
+		}
+	}
+	return changes, nil
+}
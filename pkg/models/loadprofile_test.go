@@ -0,0 +1,233 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// problems returns the problems of a ValidationError, nil for no error
+func problems(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	v, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("error %v is a %T, not a *ValidationError", err, err)
+	}
+	return v.Problems
+}
+
+func TestDataVolume(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *LoadProfile
+		want    int64
+		wantErr string
+	}{
+		{
+			name:    "steady",
+			profile: NewLoadProfile("p").Steady(10*time.Second, 0, 100, "Mbps"),
+			want:    125_000_000,
+		},
+		{
+			name: "ramps average with the previous phase",
+			profile: NewLoadProfile("p").
+				RampUp(10*time.Second, 0, 100, "Mbps").
+				Steady(10*time.Second, 0, 100, "Mbps").
+				RampDown(10 * time.Second),
+			want: 62_500_000 + 125_000_000 + 62_500_000,
+		},
+		{
+			name: "ramp between rates",
+			profile: NewLoadProfile("p").
+				Steady(time.Second, 0, 80, "Mbps").
+				RampUp(time.Second, 0, 160, "Mbps"),
+			want: 10_000_000 + 15_000_000,
+		},
+		{
+			name:    "gbps",
+			profile: NewLoadProfile("p").Steady(8*time.Second, 0, 1, "Gbps"),
+			want:    1_000_000_000,
+		},
+		{
+			name:    "kbps",
+			profile: NewLoadProfile("p").Steady(time.Second, 0, 8000, "kbps"),
+			want:    1_000_000,
+		},
+		{
+			name:    "bps",
+			profile: NewLoadProfile("p").Steady(time.Second, 0, 8e6, "bps"),
+			want:    1_000_000,
+		},
+		{
+			name:    "default unit",
+			profile: NewLoadProfile("p").Steady(time.Second, 0, 8, ""),
+			want:    1_000_000,
+		},
+		{
+			name:    "frame rate",
+			profile: NewLoadProfile("p").Steady(time.Second, 0, 1000, "fps"),
+			wantErr: "phase 1: rate unit fps is not a bit rate",
+		},
+		{
+			name:    "sessions only",
+			profile: NewLoadProfile("p").RampUp(time.Second, 100, 0, ""),
+			wantErr: "phase 1: no rate, only sessions",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.profile.DataVolume()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("DataVolume() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("DataVolume() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadProfileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *LoadProfile
+		want    []string
+	}{
+		{
+			name: "valid",
+			profile: NewLoadProfile("p").
+				RampUp(time.Second, 10, 0, "").
+				Steady(time.Second, 10, 0, "").
+				RampDown(time.Second).
+				RampDown(time.Second),
+		},
+		{
+			name:    "no phases",
+			profile: NewLoadProfile("p"),
+			want:    []string{"no phases"},
+		},
+		{
+			name: "ramp down not last",
+			profile: NewLoadProfile("p").
+				RampDown(time.Second).
+				Steady(time.Second, 10, 0, "").
+				Steady(time.Second, 10, 0, ""),
+			want: []string{"phase 1: ramp down followed by a steady phase"},
+		},
+		{
+			name: "bad phases",
+			profile: NewLoadProfile("p").
+				AddPhase(LoadPhase{Type: "spike", Duration: 1}).
+				AddPhase(LoadPhase{Type: PhaseSteady}).
+				AddPhase(LoadPhase{Type: PhaseSteady, Duration: 1, Rate: -1}),
+			want: []string{
+				"phase 1: unknown type \"spike\"",
+				"phase 2: duration 0",
+				"phase 3: negative load",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := problems(t, tt.profile.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSpeed(t *testing.T) {
+	profile := NewLoadProfile("p").
+		Steady(time.Second, 0, 1, "Gbps").
+		Steady(time.Second, 0, 1500, "Mbps").
+		Steady(time.Second, 0, 1e6, "fps")
+	want := []string{"phase 2: rate 1500 Mbps exceeds port speed 1000 Mbps"}
+	if got := problems(t, profile.ValidateSpeed(1000)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateSpeed(1000) = %q, want %q", got, want)
+	}
+	if err := profile.ValidateSpeed(10000); err != nil {
+		t.Errorf("ValidateSpeed(10000) = %v", err)
+	}
+}
+
+func TestLoadPhaseOtherRoundTrip(t *testing.T) {
+	in := `{"phaseId":1,"type":"up","duration":10,"sessions.max":100,"rampDist.synRetryMode":"obey_retry","tcp.window":{"size":4096}}`
+	var p LoadPhase
+	if err := json.Unmarshal([]byte(in), &p); err != nil {
+		t.Fatal(err)
+	}
+	wantOther := map[string]interface{}{
+		"rampDist.synRetryMode": "obey_retry",
+		"tcp.window":            map[string]interface{}{"size": 4096.0},
+	}
+	if !reflect.DeepEqual(p.Other, wantOther) || p.MaxSessions != 100 || p.Type != PhaseRampUp {
+		t.Fatalf("decoded %+v", p)
+	}
+	out, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want map[string]interface{}
+	json.Unmarshal(out, &got)
+	json.Unmarshal([]byte(in), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %s, want %s", out, in)
+	}
+
+	// A typed field wins over a stale copy of it in Other
+	p.MaxSessions = 200
+	p.Other["sessions.max"] = 100.0
+	out, _ = json.Marshal(p)
+	if !strings.Contains(string(out), `"sessions.max":200`) {
+		t.Errorf("Marshal() = %s, want sessions.max 200", out)
+	}
+}
+
+func TestLoadProfileDiff(t *testing.T) {
+	from := NewLoadProfile("a").
+		RampUp(10*time.Second, 100, 0, "").
+		Steady(time.Minute, 100, 0, "")
+	from.Phase[1].Other = map[string]interface{}{"rampDist.steadyBehavior": "cycle"}
+	to := NewLoadProfile("b").
+		RampUp(20*time.Second, 100, 0, "").
+		Steady(time.Minute, 100, 0, "").
+		RampDown(10 * time.Second)
+	to.Phase[1].Other = map[string]interface{}{"rampDist.steadyBehavior": "hold"}
+	to.Phase[0].ID = 7
+
+	got, err := from.Diff(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, c := range got {
+		lines = append(lines, c.String())
+	}
+	want := []string{
+		"phase 1 duration: 10 -> 20",
+		"phase 2 rampDist.steadyBehavior: cycle -> hold",
+		"phase 3: added",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("Diff() = %q, want %q", lines, want)
+	}
+
+	back, err := to.Diff(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := back[len(back)-1].String(); last != "phase 3: removed" {
+		t.Errorf("reverse Diff() ends with %q", last)
+	}
+}
@@ -0,0 +1,650 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Network element types, as named in the networkModel of a network
+// neighborhood
+const (
+	ElementInterface       = "interface"
+	ElementVLAN            = "vlan"
+	ElementIPStaticHosts   = "ip_static_hosts"
+	ElementIPv6StaticHosts = "ipv6_static_hosts"
+	ElementIPDHCPHosts     = "ip_dhcp_hosts"
+	ElementIPDHCPServer    = "ip_dhcp_server"
+	ElementIPRouter        = "ip_router"
+	ElementIPv6Router      = "ipv6_router"
+	ElementIPsecRouter     = "ipsec_router"
+	ElementGGSN            = "ggsn"
+	ElementSGSN            = "sgsn"
+)
+
+// NetInterface is a physical test interface
+type NetInterface struct {
+	ID                  string `json:"id"`
+	Number              int    `json:"number"`
+	MACAddress          string `json:"mac_address,omitempty"`
+	UseVnicMACAddress   bool   `json:"use_vnic_mac_address,omitempty"`
+	DuplicateMACAddress bool   `json:"duplicate_mac_address,omitempty"`
+	MTU                 int    `json:"mtu,omitempty"`
+	VLANKey             string `json:"vlan_key,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// VLAN is a tagged sub interface of an interface or another VLAN
+type VLAN struct {
+	ID                  string `json:"id"`
+	DefaultContainer    string `json:"default_container"`
+	InnerVLAN           int    `json:"inner_vlan"`
+	OuterVLAN           int    `json:"outer_vlan,omitempty"`
+	TPID                string `json:"tpid,omitempty"`
+	MACAddress          string `json:"mac_address,omitempty"`
+	DuplicateMACAddress bool   `json:"duplicate_mac_address,omitempty"`
+	MTU                 int    `json:"mtu,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// IPStaticHosts is a range of Count IPv4 hosts starting at IPAddress
+type IPStaticHosts struct {
+	ID               string   `json:"id"`
+	DefaultContainer string   `json:"default_container"`
+	Tags             []Tag    `json:"tags,omitempty"`
+	IPAddress        string   `json:"ip_address"`
+	Count            int      `json:"count"`
+	Netmask          int      `json:"netmask"`
+	GatewayIPAddress string   `json:"gateway_ip_address,omitempty"`
+	MaxMbpsPerHost   int      `json:"maxmbps_per_host,omitempty"`
+	IPSelectionType  string   `json:"ip_selection_type,omitempty"`
+	DNS              []string `json:"dns,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// IPv6StaticHosts is a range of Count IPv6 hosts starting at IPAddress
+type IPv6StaticHosts struct {
+	ID               string `json:"id"`
+	DefaultContainer string `json:"default_container"`
+	Tags             []Tag  `json:"tags,omitempty"`
+	IPAddress        string `json:"ip_address"`
+	Count            int    `json:"count"`
+	PrefixLength     int    `json:"prefix_length"`
+	GatewayIPAddress string `json:"gateway_ip_address,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// IPDHCPHosts is a set of hosts configured through DHCP
+type IPDHCPHosts struct {
+	ID                    string `json:"id"`
+	DefaultContainer      string `json:"default_container"`
+	Tags                  []Tag  `json:"tags,omitempty"`
+	Count                 int    `json:"count"`
+	AcceptLocalOffersOnly bool   `json:"accept_local_offers_only,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// IPDHCPServer leases Count addresses starting at LeaseAddress
+type IPDHCPServer struct {
+	ID                      string `json:"id"`
+	DefaultContainer        string `json:"default_container"`
+	IPAddress               string `json:"ip_address"`
+	Netmask                 int    `json:"netmask"`
+	GatewayIPAddress        string `json:"gateway_ip_address,omitempty"`
+	LeaseAddress            string `json:"lease_address"`
+	Count                   int    `json:"count"`
+	LeaseTime               int    `json:"lease_time,omitempty"`
+	AcceptLocalRequestsOnly bool   `json:"accept_local_requests_only,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// IPRouter is an IPv4 router hosts reach other subnets through
+type IPRouter struct {
+	ID               string `json:"id"`
+	DefaultContainer string `json:"default_container"`
+	IPAddress        string `json:"ip_address"`
+	Netmask          int    `json:"netmask"`
+	GatewayIPAddress string `json:"gateway_ip_address,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// IPv6Router is an IPv6 router
+type IPv6Router struct {
+	ID               string `json:"id"`
+	DefaultContainer string `json:"default_container"`
+	IPAddress        string `json:"ip_address"`
+	PrefixLength     int    `json:"prefix_length"`
+	GatewayIPAddress string `json:"gateway_ip_address,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// IPsecRouter terminates IPsec tunnels towards IKEPeerIPAddress
+type IPsecRouter struct {
+	ID               string `json:"id"`
+	DefaultContainer string `json:"default_container"`
+	IPAddress        string `json:"ip_address"`
+	Netmask          int    `json:"netmask"`
+	GatewayIPAddress string `json:"gateway_ip_address,omitempty"`
+	IKEPeerIPAddress string `json:"ike_peer_ip_address"`
+	IPsec            string `json:"ipsec,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// GGSN is a GTP gateway support node
+type GGSN struct {
+	ID               string `json:"id"`
+	DefaultContainer string `json:"default_container"`
+	GGSNIPAddress    string `json:"ggsn_ip_address"`
+	Netmask          int    `json:"netmask"`
+	GatewayIPAddress string `json:"gateway_ip_address,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// SGSN is a GTP serving support node tunnelling to a GGSN
+type SGSN struct {
+	ID               string `json:"id"`
+	DefaultContainer string `json:"default_container"`
+	SGSNIPAddress    string `json:"sgsn_ip_address"`
+	GGSNIPAddress    string `json:"ggsn_ip_address"`
+	Netmask          int    `json:"netmask"`
+	GatewayIPAddress string `json:"gateway_ip_address,omitempty"`
+
+	Other map[string]interface{} `json:"-"`
+}
+
+// NetworkModel is the typed networkModel of a network neighborhood.
+// Element types without a Go type are kept in Other, and the settings of
+// an element without a field in the Other of the element, so a model
+// survives a read-modify-write round trip unchanged.
+type NetworkModel struct {
+	Interfaces      []NetInterface    `json:"interface,omitempty"`
+	VLANs           []VLAN            `json:"vlan,omitempty"`
+	IPStaticHosts   []IPStaticHosts   `json:"ip_static_hosts,omitempty"`
+	IPv6StaticHosts []IPv6StaticHosts `json:"ipv6_static_hosts,omitempty"`
+	IPDHCPHosts     []IPDHCPHosts     `json:"ip_dhcp_hosts,omitempty"`
+	IPDHCPServers   []IPDHCPServer    `json:"ip_dhcp_server,omitempty"`
+	IPRouters       []IPRouter        `json:"ip_router,omitempty"`
+	IPv6Routers     []IPv6Router      `json:"ipv6_router,omitempty"`
+	IPsecRouters    []IPsecRouter     `json:"ipsec_router,omitempty"`
+	GGSNs           []GGSN            `json:"ggsn,omitempty"`
+	SGSNs           []SGSN            `json:"sgsn,omitempty"`
+
+	Other map[string]json.RawMessage `json:"-"`
+}
+
+// networkModelFields aliases NetworkModel without its JSON methods
+type networkModelFields NetworkModel
+
+// UnmarshalJSON decodes the typed elements and keeps the others in Other
+func (m *NetworkModel) UnmarshalJSON(data []byte) error {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, (*networkModelFields)(m)); err != nil {
+		return err
+	}
+	m.Other = nil
+	for k, v := range all {
+		if !isTypedElement(k) {
+			if m.Other == nil {
+				m.Other = make(map[string]json.RawMessage)
+			}
+			m.Other[k] = v
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the typed elements together with Other
+func (m NetworkModel) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(networkModelFields(m))
+	if err != nil || len(m.Other) == 0 {
+		return data, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range m.Other {
+		if _, ok := all[k]; !ok {
+			all[k] = v
+		}
+	}
+	return json.Marshal(all)
+}
+
+func isTypedElement(name string) bool {
+	switch name {
+	case ElementInterface, ElementVLAN, ElementIPStaticHosts, ElementIPv6StaticHosts,
+		ElementIPDHCPHosts, ElementIPDHCPServer, ElementIPRouter, ElementIPv6Router,
+		ElementIPsecRouter, ElementGGSN, ElementSGSN:
+		return true
+	}
+	return false
+}
+
+// Every element type keeps the settings it has no field for in its Other
+// map. The xxxFields types alias the elements without their JSON methods.
+type (
+	netInterfaceFields    NetInterface
+	vlanFields            VLAN
+	ipStaticHostsFields   IPStaticHosts
+	ipv6StaticHostsFields IPv6StaticHosts
+	ipDHCPHostsFields     IPDHCPHosts
+	ipDHCPServerFields    IPDHCPServer
+	ipRouterFields        IPRouter
+	ipv6RouterFields      IPv6Router
+	ipsecRouterFields     IPsecRouter
+	ggsnFields            GGSN
+	sgsnFields            SGSN
+)
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *NetInterface) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*netInterfaceFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e NetInterface) MarshalJSON() ([]byte, error) {
+	return encodeElement(netInterfaceFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *VLAN) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*vlanFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e VLAN) MarshalJSON() ([]byte, error) {
+	return encodeElement(vlanFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *IPStaticHosts) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*ipStaticHostsFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e IPStaticHosts) MarshalJSON() ([]byte, error) {
+	return encodeElement(ipStaticHostsFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *IPv6StaticHosts) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*ipv6StaticHostsFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e IPv6StaticHosts) MarshalJSON() ([]byte, error) {
+	return encodeElement(ipv6StaticHostsFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *IPDHCPHosts) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*ipDHCPHostsFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e IPDHCPHosts) MarshalJSON() ([]byte, error) {
+	return encodeElement(ipDHCPHostsFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *IPDHCPServer) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*ipDHCPServerFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e IPDHCPServer) MarshalJSON() ([]byte, error) {
+	return encodeElement(ipDHCPServerFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *IPRouter) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*ipRouterFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e IPRouter) MarshalJSON() ([]byte, error) {
+	return encodeElement(ipRouterFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *IPv6Router) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*ipv6RouterFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e IPv6Router) MarshalJSON() ([]byte, error) {
+	return encodeElement(ipv6RouterFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *IPsecRouter) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*ipsecRouterFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e IPsecRouter) MarshalJSON() ([]byte, error) {
+	return encodeElement(ipsecRouterFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *GGSN) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*ggsnFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e GGSN) MarshalJSON() ([]byte, error) {
+	return encodeElement(ggsnFields(e), e.Other)
+}
+
+// UnmarshalJSON decodes the fields and keeps the other settings in Other
+func (e *SGSN) UnmarshalJSON(data []byte) (err error) {
+	e.Other, err = decodeElement(data, (*sgsnFields)(e))
+	return err
+}
+
+// MarshalJSON encodes the fields together with Other
+func (e SGSN) MarshalJSON() ([]byte, error) {
+	return encodeElement(sgsnFields(e), e.Other)
+}
+
+// decodeElement decodes data into fields, a pointer to an element alias,
+// and returns the settings fields has no field for
+func decodeElement(data []byte, fields interface{}) (map[string]interface{}, error) {
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, fields); err != nil {
+		return nil, err
+	}
+	known := jsonKeys(reflect.TypeOf(fields).Elem())
+	var other map[string]interface{}
+	for k, v := range all {
+		if !known[k] {
+			if other == nil {
+				other = make(map[string]interface{})
+			}
+			other[k] = v
+		}
+	}
+	return other, nil
+}
+
+// encodeElement encodes fields, an element alias, together with other.
+// Fields win over other settings of the same name.
+func encodeElement(fields interface{}, other map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(fields)
+	if err != nil || len(other) == 0 {
+		return data, err
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range other {
+		if _, ok := all[k]; !ok {
+			all[k] = v
+		}
+	}
+	return json.Marshal(all)
+}
+
+// jsonKeys returns the JSON names of the fields of struct type t
+func jsonKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// Element is an element of a network model reduced to what the conflict
+// checks need
+type Element struct {
+	Type      string
+	ID        string
+	Container string
+	MAC       string
+	// Subnet is the network the element lives in, Range the addresses it
+	// occupies. Either may be nil.
+	Subnet *net.IPNet
+	First  net.IP
+	Count  int
+	Domain string
+}
+
+// Elements lists every typed element of the model
+func (m *NetworkModel) Elements() ([]Element, []error) {
+	var out []Element
+	var errs []error
+	addr := func(typ, id, container, ip string, prefix, count int, v6 bool, tags []Tag) {
+		e := Element{Type: typ, ID: id, Container: container, Count: count, Domain: domainOf(tags)}
+		if ip != "" {
+			subnet, first, err := parseSubnet(ip, prefix, v6)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", typ, id, err))
+			} else {
+				e.Subnet, e.First = subnet, first
+			}
+		}
+		out = append(out, e)
+	}
+	for _, e := range m.Interfaces {
+		if e.DuplicateMACAddress || e.UseVnicMACAddress {
+			out = append(out, Element{Type: ElementInterface, ID: e.ID})
+		} else {
+			out = append(out, Element{Type: ElementInterface, ID: e.ID, MAC: e.MACAddress})
+		}
+	}
+	for _, e := range m.VLANs {
+		mac := e.MACAddress
+		if e.DuplicateMACAddress {
+			mac = ""
+		}
+		out = append(out, Element{Type: ElementVLAN, ID: e.ID, Container: e.DefaultContainer, MAC: mac})
+	}
+	for _, e := range m.IPStaticHosts {
+		addr(ElementIPStaticHosts, e.ID, e.DefaultContainer, e.IPAddress, e.Netmask, e.Count, false, e.Tags)
+	}
+	for _, e := range m.IPv6StaticHosts {
+		addr(ElementIPv6StaticHosts, e.ID, e.DefaultContainer, e.IPAddress, e.PrefixLength, e.Count, true, e.Tags)
+	}
+	for _, e := range m.IPDHCPHosts {
+		out = append(out, Element{Type: ElementIPDHCPHosts, ID: e.ID, Container: e.DefaultContainer, Domain: domainOf(e.Tags)})
+	}
+	for _, e := range m.IPDHCPServers {
+		addr(ElementIPDHCPServer, e.ID, e.DefaultContainer, e.IPAddress, e.Netmask, 1, false, nil)
+		if e.LeaseAddress != "" {
+			addr(ElementIPDHCPServer, e.ID+" leases", e.DefaultContainer, e.LeaseAddress, e.Netmask, e.Count, false, nil)
+		}
+	}
+	for _, e := range m.IPRouters {
+		addr(ElementIPRouter, e.ID, e.DefaultContainer, e.IPAddress, e.Netmask, 1, false, nil)
+	}
+	for _, e := range m.IPv6Routers {
+		addr(ElementIPv6Router, e.ID, e.DefaultContainer, e.IPAddress, e.PrefixLength, 1, true, nil)
+	}
+	for _, e := range m.IPsecRouters {
+		addr(ElementIPsecRouter, e.ID, e.DefaultContainer, e.IPAddress, e.Netmask, 1, false, nil)
+	}
+	for _, e := range m.GGSNs {
+		addr(ElementGGSN, e.ID, e.DefaultContainer, e.GGSNIPAddress, e.Netmask, 1, false, nil)
+	}
+	for _, e := range m.SGSNs {
+		addr(ElementSGSN, e.ID, e.DefaultContainer, e.SGSNIPAddress, e.Netmask, 1, false, nil)
+	}
+	return out, errs
+}
+
+// Validate checks the model for the conflicts the chassis would reject:
+// duplicate element ids, references to missing containers, duplicate MAC
+// addresses, overlapping address ranges and subnets that overlap without
+// being the same network on the same container
+func (m *NetworkModel) Validate() error {
+	elements, errs := m.Elements()
+	ids := make(map[string]string)
+	for _, e := range elements {
+		if e.Type == ElementIPDHCPServer && strings.HasSuffix(e.ID, " leases") {
+			continue
+		}
+		if e.ID == "" {
+			errs = append(errs, fmt.Errorf("%s without id", e.Type))
+		} else if prev, ok := ids[e.ID]; ok {
+			errs = append(errs, fmt.Errorf("id %s is used by a %s and a %s", e.ID, prev, e.Type))
+		}
+		ids[e.ID] = e.Type
+	}
+	for _, e := range elements {
+		if e.Type != ElementInterface && e.Container != "" {
+			if _, ok := ids[e.Container]; !ok {
+				errs = append(errs, fmt.Errorf("%s %s: container %s does not exist", e.Type, e.ID, e.Container))
+			}
+		}
+	}
+	macs := make(map[string]string)
+	for _, e := range elements {
+		if e.MAC == "" {
+			continue
+		}
+		hw, err := net.ParseMAC(e.MAC)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", e.Type, e.ID, err))
+			continue
+		}
+		if prev, ok := macs[hw.String()]; ok {
+			errs = append(errs, fmt.Errorf("MAC address %s is used by %s and %s", hw, prev, e.ID))
+		}
+		macs[hw.String()] = e.ID
+	}
+	for i, a := range elements {
+		if a.Subnet == nil {
+			continue
+		}
+		for _, b := range elements[i+1:] {
+			if b.Subnet == nil || (a.Subnet.IP.To4() == nil) != (b.Subnet.IP.To4() == nil) {
+				continue
+			}
+			if rangesOverlap(a.First, a.Count, b.First, b.Count) {
+				errs = append(errs, fmt.Errorf("addresses of %s %s and %s %s overlap", a.Type, a.ID, b.Type, b.ID))
+				continue
+			}
+			same := a.Subnet.String() == b.Subnet.String()
+			if !same && (a.Subnet.Contains(b.Subnet.IP) || b.Subnet.Contains(a.Subnet.IP)) {
+				errs = append(errs, fmt.Errorf("subnet %s of %s %s overlaps subnet %s of %s %s", a.Subnet, a.Type, a.ID, b.Subnet, b.Type, b.ID))
+			} else if same && a.Container != b.Container && a.Domain == b.Domain {
+				errs = append(errs, fmt.Errorf("subnet %s is used on both %s and %s", a.Subnet, a.Container, b.Container))
+			}
+		}
+	}
+	return joinErrors(errs)
+}
+
+func parseSubnet(ip string, prefix int, v6 bool) (*net.IPNet, net.IP, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return nil, nil, fmt.Errorf("invalid IP address %q", ip)
+	}
+	bits := 32
+	if v6 {
+		bits = 128
+	} else if addr = addr.To4(); addr == nil {
+		return nil, nil, fmt.Errorf("%s is not an IPv4 address", ip)
+	}
+	if prefix < 0 || prefix > bits {
+		return nil, nil, fmt.Errorf("prefix length %d out of range", prefix)
+	}
+	mask := net.CIDRMask(prefix, bits)
+	return &net.IPNet{IP: addr.Mask(mask), Mask: mask}, addr, nil
+}
+
+// rangesOverlap compares the address ranges [a, a+n) and [b, b+m)
+func rangesOverlap(a net.IP, n int, b net.IP, m int) bool {
+	if n <= 0 || m <= 0 {
+		return false
+	}
+	return compareIP(a, addIP(b, m-1)) <= 0 && compareIP(b, addIP(a, n-1)) <= 0
+}
+
+// addIP returns ip + n
+func addIP(ip net.IP, n int) net.IP {
+	out := append(net.IP(nil), ip...)
+	carry := n
+	for i := len(out) - 1; i >= 0 && carry > 0; i-- {
+		sum := int(out[i]) + carry
+		out[i] = byte(sum)
+		carry = sum >> 8
+	}
+	return out
+}
+
+func compareIP(a, b net.IP) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func domainOf(tags []Tag) string {
+	for _, t := range tags {
+		if t.DomainID.Name != "" {
+			return t.DomainID.Name + "/" + t.DomainID.Iface
+		}
+	}
+	return ""
+}
+
+// ValidationError lists every problem found in a model
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	problems := make([]string, len(errs))
+	for i, err := range errs {
+		problems[i] = err.Error()
+	}
+	sort.Strings(problems)
+	return &ValidationError{Problems: problems}
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNetworkModelRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{
+			name: "typed fields only",
+			in:   `{"interface":[{"id":"Interface 1","number":1,"mac_address":"02:1a:c5:01:00:00"}]}`,
+		},
+		{
+			name: "unknown element type",
+			in:   `{"interface":[{"id":"Interface 1","number":1}],"ds_lite_b4":[{"id":"b4","aftr_address":"2001::1"}]}`,
+		},
+		{
+			name: "unknown settings of known elements",
+			in: `{
+				"interface":[{"id":"Interface 1","number":1,"packet_filter":{"vlan":null}}],
+				"vlan":[{"id":"v1","default_container":"Interface 1","inner_vlan":10,"inner_tpid":"0x8100"}],
+				"ip_static_hosts":[{"id":"h1","default_container":"v1","ip_address":"10.0.0.10","count":10,"netmask":24,
+					"tags":[{"id":"t1","type":"domain","domainId":{"name":"client","external":false,"iface":"default"}}],
+					"psn":"0x01","enable_stats":true}],
+				"ip_router":[{"id":"r1","default_container":"v1","ip_address":"10.0.0.1","netmask":24,"hosts_ip_alloc_container":"h1"}]
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &NetworkModel{}
+			if err := json.Unmarshal([]byte(tt.in), m); err != nil {
+				t.Fatal(err)
+			}
+			out, err := json.Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want, got interface{}
+			if err := json.Unmarshal([]byte(tt.in), &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip changed the model\n got %s\nwant %s", out, tt.in)
+			}
+		})
+	}
+}
+
+func TestNetworkModelEditKeepsOther(t *testing.T) {
+	in := `{"ip_router":[{"id":"r1","default_container":"v1","ip_address":"10.0.0.1","netmask":24,"hosts_ip_alloc_container":"h1"}]}`
+	m := &NetworkModel{}
+	if err := json.Unmarshal([]byte(in), m); err != nil {
+		t.Fatal(err)
+	}
+	m.IPRouters[0].IPAddress = "10.0.0.254"
+	out, err := json.Marshal(m.IPRouters[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["ip_address"] != "10.0.0.254" || got["hosts_ip_alloc_container"] != "h1" {
+		t.Errorf("edited router encodes as %s", out)
+	}
+}
+
+func TestNetworkModelValidate(t *testing.T) {
+	iface := NetInterface{ID: "Interface 1", Number: 1, MACAddress: "02:00:00:00:00:01"}
+	vlan := func(id string, tag int) VLAN {
+		return VLAN{ID: id, DefaultContainer: "Interface 1", InnerVLAN: tag}
+	}
+	hosts := func(id, container, ip string, count, mask int) IPStaticHosts {
+		return IPStaticHosts{ID: id, DefaultContainer: container, IPAddress: ip, Count: count, Netmask: mask}
+	}
+	tests := []struct {
+		name  string
+		model NetworkModel
+		want  []string
+	}{
+		{
+			name: "valid",
+			model: NetworkModel{
+				Interfaces:    []NetInterface{iface},
+				VLANs:         []VLAN{vlan("v1", 10), vlan("v2", 20)},
+				IPStaticHosts: []IPStaticHosts{hosts("h1", "v1", "10.0.1.10", 10, 24), hosts("h2", "v2", "10.0.2.10", 10, 24)},
+				IPRouters:     []IPRouter{{ID: "r1", DefaultContainer: "v1", IPAddress: "10.0.1.1", Netmask: 24}},
+			},
+		},
+		{
+			name: "duplicate id",
+			model: NetworkModel{
+				Interfaces: []NetInterface{iface},
+				VLANs:      []VLAN{vlan("x", 10)},
+				IPRouters:  []IPRouter{{ID: "x", DefaultContainer: "Interface 1", IPAddress: "10.0.1.1", Netmask: 24}},
+			},
+			want: []string{"id x is used by a vlan and a ip_router"},
+		},
+		{
+			name: "missing container",
+			model: NetworkModel{
+				Interfaces:    []NetInterface{iface},
+				IPStaticHosts: []IPStaticHosts{hosts("h1", "nowhere", "10.0.1.10", 10, 24)},
+			},
+			want: []string{"container nowhere does not exist"},
+		},
+		{
+			name: "duplicate MAC",
+			model: NetworkModel{
+				Interfaces: []NetInterface{iface, {ID: "Interface 2", Number: 2, MACAddress: "02:00:00:00:00:01"}},
+			},
+			want: []string{"MAC address 02:00:00:00:00:01 is used by Interface 1 and Interface 2"},
+		},
+		{
+			name: "overlapping ranges",
+			model: NetworkModel{
+				Interfaces:    []NetInterface{iface},
+				IPStaticHosts: []IPStaticHosts{hosts("h1", "Interface 1", "10.0.1.10", 10, 24), hosts("h2", "Interface 1", "10.0.1.15", 10, 24)},
+			},
+			want: []string{"addresses of ip_static_hosts h1 and ip_static_hosts h2 overlap"},
+		},
+		{
+			name: "overlapping subnets",
+			model: NetworkModel{
+				Interfaces:    []NetInterface{iface},
+				VLANs:         []VLAN{vlan("v1", 10)},
+				IPStaticHosts: []IPStaticHosts{hosts("h1", "Interface 1", "10.0.0.10", 10, 16), hosts("h2", "v1", "10.0.1.10", 10, 24)},
+			},
+			want: []string{"subnet 10.0.0.0/16 of ip_static_hosts h1 overlaps subnet 10.0.1.0/24"},
+		},
+		{
+			name: "same subnet on two containers",
+			model: NetworkModel{
+				Interfaces:    []NetInterface{iface},
+				VLANs:         []VLAN{vlan("v1", 10), vlan("v2", 20)},
+				IPStaticHosts: []IPStaticHosts{hosts("h1", "v1", "10.0.1.10", 10, 24), hosts("h2", "v2", "10.0.1.100", 10, 24)},
+			},
+			want: []string{"subnet 10.0.1.0/24 is used on both v1 and v2"},
+		},
+		{
+			name: "bad address",
+			model: NetworkModel{
+				Interfaces:    []NetInterface{iface},
+				IPStaticHosts: []IPStaticHosts{hosts("h1", "Interface 1", "10.0.1", 10, 24)},
+			},
+			want: []string{"ip_static_hosts h1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() = %v, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a path expression
+type pathSegment struct {
+	name     string
+	item     bool
+	wildcard bool
+}
+
+// Match is a node selected by a path expression
+type Match struct {
+	Path  string
+	Value interface{}
+}
+
+// parsePath splits a path expression into segments. Two syntaxes are
+// accepted:
+//
+//	networkModel/ip_router[0]/default_gateway   (brackets address list items)
+//	/networkModel/ip_router/0/default_gateway   (RFC 6901 JSON pointer)
+//
+// In both, "*" selects every element of a list or object, for example
+// component[*]/active.
+func parsePath(expr string) ([]pathSegment, error) {
+	if strings.HasPrefix(expr, "/") {
+		return parsePointer(expr)
+	}
+	var segs []pathSegment
+	for _, part := range strings.Split(expr, "/") {
+		if part == "" {
+			continue
+		}
+		name := part
+		var items []string
+		if i := strings.Index(part, "["); i >= 0 {
+			name = part[:i]
+			rest := part[i:]
+			for rest != "" {
+				if rest[0] != '[' {
+					return nil, fmt.Errorf("invalid path %q: unexpected %q", expr, rest)
+				}
+				end := strings.Index(rest, "]")
+				if end < 0 {
+					return nil, fmt.Errorf("invalid path %q: missing ]", expr)
+				}
+				items = append(items, rest[1:end])
+				rest = rest[end+1:]
+			}
+		}
+		if name != "" {
+			segs = append(segs, pathSegment{name: name, wildcard: name == "*"})
+		}
+		for _, item := range items {
+			if item == "" {
+				return nil, fmt.Errorf("invalid path %q: empty []", expr)
+			}
+			if n, err := strconv.Atoi(item); err == nil && n < 0 {
+				return nil, fmt.Errorf("invalid path %q: negative index %d", expr, n)
+			}
+			segs = append(segs, pathSegment{name: item, item: true, wildcard: item == "*"})
+		}
+	}
+	return segs, nil
+}
+
+func parsePointer(expr string) ([]pathSegment, error) {
+	var segs []pathSegment
+	for _, part := range strings.Split(expr[1:], "/") {
+		if strings.Contains(strings.ReplaceAll(strings.ReplaceAll(part, "~0", ""), "~1", ""), "~") {
+			return nil, fmt.Errorf("invalid JSON pointer %q: bad escape in %q", expr, part)
+		}
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		n, err := strconv.Atoi(part)
+		if err == nil && n < 0 {
+			return nil, fmt.Errorf("invalid JSON pointer %q: negative index %d", expr, n)
+		}
+		segs = append(segs, pathSegment{name: part, item: err == nil, wildcard: part == "*"})
+	}
+	return segs, nil
+}
+
+// Path returns the proxy addressed by a path expression relative to p. The
+// expression must not contain wildcards; use Select for those.
+func (p *DataModelProxy) Path(expr string) *DataModelProxy {
+	segs, err := parsePath(expr)
+	if err != nil {
+		return p.failed(err)
+	}
+	node := p
+	for _, seg := range segs {
+		if seg.wildcard {
+			return p.failed(fmt.Errorf("path %q contains a wildcard, use Select", expr))
+		}
+		if seg.item {
+			node = node.GetItem(seg.name)
+		} else {
+			node = node.GetField(seg.name)
+		}
+	}
+	return node
+}
+
+// Select returns every node matched by a path expression relative to p,
+// reading the data up to the first wildcard with a single request
+func (p *DataModelProxy) Select(expr string) ([]Match, error) {
+	segs, err := parsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	prefix := 0
+	for prefix < len(segs) && !segs[prefix].wildcard {
+		prefix++
+	}
+	base := p
+	for _, seg := range segs[:prefix] {
+		if seg.item {
+			base = base.GetItem(seg.name)
+		} else {
+			base = base.GetField(seg.name)
+		}
+	}
+	depth := len(segs) - prefix + 1
+	value, err := base.Get(&depth, nil)
+	if err != nil {
+		return nil, err
+	}
+	var out []Match
+	selectPath(base.fullPath(), value, segs[prefix:], &out)
+	return out, nil
+}
+
+// SetAll writes value to every node matched by a path expression and
+// returns the number of nodes written. Fields are written by patching their
+// parent object, as the component endpoints expect.
+func (p *DataModelProxy) SetAll(expr string, value interface{}) (int, error) {
+	segs, err := parsePath(expr)
+	if err != nil {
+		return 0, err
+	}
+	if len(segs) == 0 {
+		return 0, fmt.Errorf("empty path")
+	}
+	last := segs[len(segs)-1]
+	if last.wildcard || last.item {
+		return 0, fmt.Errorf("path %q must end with a field name", expr)
+	}
+	parents, err := p.Select(joinSegments(segs[:len(segs)-1]))
+	if err != nil {
+		return 0, err
+	}
+	for i, m := range parents {
+		parent := p.atPath(m.Path)
+		if err := parent.Set(map[string]interface{}{last.name: value}); err != nil {
+			return i, fmt.Errorf("%s/%s: %w", m.Path, last.name, err)
+		}
+	}
+	return len(parents), nil
+}
+
+func selectPath(at string, value interface{}, segs []pathSegment, out *[]Match) {
+	if len(segs) == 0 {
+		*out = append(*out, Match{Path: at, Value: value})
+		return
+	}
+	seg, rest := segs[0], segs[1:]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if seg.wildcard {
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				selectPath(at+"/"+k, v[k], rest, out)
+			}
+			return
+		}
+		if child, ok := v[seg.name]; ok {
+			selectPath(at+"/"+seg.name, child, rest, out)
+		}
+	case []interface{}:
+		for i, item := range v {
+			key := itemKey(item, i)
+			if seg.wildcard || seg.name == key || seg.name == strconv.Itoa(i) {
+				selectPath(at+"/"+key, item, rest, out)
+			}
+		}
+	}
+}
+
+// itemKey addresses a list element by its id when it has one, as the API
+// does for components, and by index otherwise
+func itemKey(item interface{}, index int) string {
+	if m, ok := item.(map[string]interface{}); ok {
+		if id, ok := m["id"]; ok && id != nil {
+			return fmt.Sprintf("%v", id)
+		}
+	}
+	return strconv.Itoa(index)
+}
+
+// joinSegments renders segments as a JSON pointer
+func joinSegments(segs []pathSegment) string {
+	var b strings.Builder
+	for _, seg := range segs {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(seg.name, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// atPath returns a proxy for an absolute API path sharing the wrapper,
+// schema and cache of p
+func (p *DataModelProxy) atPath(fullPath string) *DataModelProxy {
+	i := strings.LastIndex(fullPath, "/")
+	dir, name := fullPath[:i], fullPath[i+1:]
+	return p.child(name, dir, dir)
+}
+
+// failed returns a proxy that reports err on every request
+func (p *DataModelProxy) failed(err error) *DataModelProxy {
+	child := p.child(p.name, p.path, p.modelPath)
+	if child.err == nil {
+		child.err = err
+	}
+	return child
+}
@@ -0,0 +1,258 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    []pathSegment
+		wantErr string
+	}{
+		{
+			expr: "networkModel/ip_router[0]/default_gateway",
+			want: []pathSegment{{name: "networkModel"}, {name: "ip_router"}, {name: "0", item: true}, {name: "default_gateway"}},
+		},
+		{
+			expr: "component[*]/active",
+			want: []pathSegment{{name: "component"}, {name: "*", item: true, wildcard: true}, {name: "active"}},
+		},
+		{
+			expr: "matrix[1][2]",
+			want: []pathSegment{{name: "matrix"}, {name: "1", item: true}, {name: "2", item: true}},
+		},
+		{
+			expr: "a//b/",
+			want: []pathSegment{{name: "a"}, {name: "b"}},
+		},
+		{
+			expr: "*/name",
+			want: []pathSegment{{name: "*", wildcard: true}, {name: "name"}},
+		},
+		{expr: "ip_router[0", wantErr: "missing ]"},
+		{expr: "ip_router[]", wantErr: "empty []"},
+		{expr: "ip_router[0]x", wantErr: `unexpected "x"`},
+		{expr: "ip_router[-1]", wantErr: "negative index -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := parsePath(tt.expr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parsePath(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePath(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    []pathSegment
+		wantErr string
+	}{
+		{
+			expr: "/networkModel/ip_router/0/default_gateway",
+			want: []pathSegment{{name: "networkModel"}, {name: "ip_router"}, {name: "0", item: true}, {name: "default_gateway"}},
+		},
+		{
+			expr: "/a~1b/c~0d",
+			want: []pathSegment{{name: "a/b"}, {name: "c~d"}},
+		},
+		{
+			// ~01 is an escaped ~ followed by 1, not an escaped /
+			expr: "/x~01",
+			want: []pathSegment{{name: "x~1"}},
+		},
+		{
+			expr: "/component/*/active",
+			want: []pathSegment{{name: "component"}, {name: "*", wildcard: true}, {name: "active"}},
+		},
+		{expr: "/a~2", wantErr: "bad escape"},
+		{expr: "/a~", wantErr: "bad escape"},
+		{expr: "/ip_router/-1", wantErr: "negative index -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := parsePath(tt.expr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parsePath(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePath(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+			if joined := joinSegments(got); joined != tt.expr {
+				t.Errorf("joinSegments() = %q, want %q", joined, tt.expr)
+			}
+		})
+	}
+}
+
+// treeWrapper serves GETs from root and records the writes
+type treeWrapper struct {
+	recordingWrapper
+	root map[string]interface{}
+}
+
+func (w *treeWrapper) Get(path string, responseDepth *int, params map[string]string) (interface{}, error) {
+	var node interface{} = w.root
+	for _, part := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			node = v[part]
+		case []interface{}:
+			node = nil
+			for i, item := range v {
+				if key := itemKey(item, i); key == part || strconv.Itoa(i) == part {
+					node = item
+				}
+			}
+		default:
+			node = nil
+		}
+		if node == nil {
+			return nil, fmt.Errorf("GET %s: not found", path)
+		}
+	}
+	return node, nil
+}
+
+func TestSelectAndSetAll(t *testing.T) {
+	root := map[string]interface{}{
+		"network": map[string]interface{}{
+			"ip_router": []interface{}{
+				map[string]interface{}{"id": "r1", "default_gateway": "10.0.0.1"},
+				map[string]interface{}{"id": "r2", "default_gateway": "10.0.1.1"},
+			},
+			"vlan": []interface{}{},
+			"tags": []interface{}{"a", "b"},
+		},
+	}
+	tests := []struct {
+		name       string
+		expr       string
+		want       []string
+		wantErr    string
+		set        string
+		wantWrites []string
+	}{
+		{
+			name:       "wildcard over items",
+			expr:       "ip_router[*]/default_gateway",
+			want:       []string{"/network/ip_router/r1/default_gateway", "/network/ip_router/r2/default_gateway"},
+			set:        "ip_router[*]/default_gateway",
+			wantWrites: []string{"/network/ip_router/r1", "/network/ip_router/r2"},
+		},
+		{
+			name:       "item by index",
+			expr:       "/ip_router/1/default_gateway",
+			want:       []string{"/network/ip_router/1/default_gateway"},
+			set:        "/ip_router/1/default_gateway",
+			wantWrites: []string{"/network/ip_router/1"},
+		},
+		{
+			name: "item by id",
+			expr: "ip_router[r1]",
+			want: []string{"/network/ip_router/r1"},
+		},
+		{
+			name: "items without ids",
+			expr: "tags[*]",
+			want: []string{"/network/tags/0", "/network/tags/1"},
+		},
+		{
+			name: "wildcard over an empty list",
+			expr: "vlan[*]/id",
+			set:  "vlan[*]/id",
+		},
+		{
+			name: "index out of range after a wildcard",
+			expr: "*[2]/default_gateway",
+			set:  "*[2]/default_gateway",
+		},
+		{
+			name:    "index out of range",
+			expr:    "ip_router[2]/default_gateway",
+			wantErr: "not found",
+		},
+		{
+			name: "missing field",
+			expr: "ip_router[*]/nexthop",
+			set:  "ip_router[*]/missing/nexthop",
+		},
+		{
+			name:    "negative index",
+			expr:    "ip_router[-1]",
+			wantErr: "negative index",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &treeWrapper{root: root}
+			p := NewDataModelProxy(w, "network", "")
+			matches, err := p.Select(tt.expr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Select(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, m := range matches {
+				paths = append(paths, m.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("Select(%q) = %v, want %v", tt.expr, paths, tt.want)
+			}
+			if tt.set == "" {
+				return
+			}
+			n, err := p.SetAll(tt.set, "10.9.9.9")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != len(tt.wantWrites) || !reflect.DeepEqual(w.writes, tt.wantWrites) {
+				t.Errorf("SetAll(%q) = %d, writes %v, want %v", tt.set, n, w.writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestSetAllRejects(t *testing.T) {
+	p := NewDataModelProxy(&treeWrapper{}, "network", "")
+	for expr, want := range map[string]string{
+		"":                "empty path",
+		"ip_router[*]":    "must end with a field name",
+		"ip_router[0]":    "must end with a field name",
+		"ip_router[0]/x[": "missing ]",
+	} {
+		if _, err := p.SetAll(expr, 1); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("SetAll(%q) error = %v, want %q", expr, err, want)
+		}
+	}
+	if err := p.Path("ip_router[*]/x").Err(); err == nil || !strings.Contains(err.Error(), "use Select") {
+		t.Errorf("Path() with a wildcard error = %v", err)
+	}
+}
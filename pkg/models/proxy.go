@@ -1,6 +1,7 @@
 package models
 
 import (
+    "encoding/json"
     "fmt"
     "strconv"
 )
@@ -11,7 +12,9 @@ type DataModelProxy struct {
     name      string
     path      string
     modelPath string
-    cache     map[string]interface{}
+    cache     *Cache
+    schema    *Schema
+    err       error
 }
 
 // BPSWrapper interface defines the methods needed from BPS client
@@ -25,50 +28,162 @@ type BPSWrapper interface {
     Import(path, filename string, params map[string]interface{}) (interface{}, error)
 }
 
-// NewDataModelProxy creates a new data model proxy
+// baseURLer is implemented by wrappers that know their API base URL
+type baseURLer interface {
+    BaseURL() string
+}
+
+// NewDataModelProxy creates a root data model proxy with a cache of its
+// own, which the proxies derived from it share
 func NewDataModelProxy(wrapper BPSWrapper, name, path string) *DataModelProxy {
     return &DataModelProxy{
         wrapper:   wrapper,
         name:      name,
         path:      path,
         modelPath: path,
-        cache:     make(map[string]interface{}),
+        cache:     NewCache(DefaultCacheEntries, 0),
     }
 }
 
-// NewDataModelProxyWithModel creates a new data model proxy with explicit model path
+// NewDataModelProxyWithModel creates a root data model proxy with explicit
+// model path and a cache of its own
 func NewDataModelProxyWithModel(wrapper BPSWrapper, name, path, modelPath string) *DataModelProxy {
     return &DataModelProxy{
         wrapper:   wrapper,
         name:      name,
         path:      path,
         modelPath: modelPath,
-        cache:     make(map[string]interface{}),
+        cache:     NewCache(DefaultCacheEntries, 0),
+    }
+}
+
+// SetSchema attaches a data model schema used to check paths and values.
+// Proxies derived with GetField/GetItem inherit it.
+func (p *DataModelProxy) SetSchema(schema *Schema) {
+    p.schema = schema
+}
+
+// Schema returns the attached schema, if any
+func (p *DataModelProxy) Schema() *Schema {
+    return p.schema
+}
+
+// Err returns the error recorded while deriving this proxy, such as an
+// unknown field name
+func (p *DataModelProxy) Err() error {
+    return p.err
+}
+
+// Node returns the schema node for this proxy
+func (p *DataModelProxy) Node() (*SchemaNode, error) {
+    if p.err != nil {
+        return nil, p.err
+    }
+    if p.schema == nil {
+        return nil, fmt.Errorf("no schema loaded for %s", p.fullPath())
+    }
+    return p.schema.Lookup(p.fullPath())
+}
+
+// Fields lists the child field names of this node
+func (p *DataModelProxy) Fields() ([]string, error) {
+    node, err := p.Node()
+    if err != nil {
+        return nil, err
+    }
+    if node.Type != NodeObject {
+        return nil, fmt.Errorf("%s is a %s, not an object", p.fullPath(), node.Type)
+    }
+    return node.FieldNames(), nil
+}
+
+// IsList reports whether this node is a list according to the schema
+func (p *DataModelProxy) IsList() bool {
+    node, err := p.Node()
+    return err == nil && node.Type == NodeList
+}
+
+// IsObject reports whether this node is an object according to the schema
+func (p *DataModelProxy) IsObject() bool {
+    node, err := p.Node()
+    return err == nil && node.Type == NodeObject
+}
+
+// Validate checks value against the schema node of this proxy. It succeeds
+// when no schema is loaded.
+func (p *DataModelProxy) Validate(value interface{}) error {
+    if p.err != nil {
+        return p.err
+    }
+    if p.schema == nil {
+        return nil
+    }
+    node, err := p.Node()
+    if err != nil {
+        return err
+    }
+    if err := node.Validate(value); err != nil {
+        return fmt.Errorf("%s: %w", p.fullPath(), err)
+    }
+    return nil
+}
+
+// FullDepth is a responseDepth deep enough to return any node completely
+const FullDepth = 100
+
+// Decode retrieves the node and decodes it into dst
+func (p *DataModelProxy) Decode(responseDepth *int, dst interface{}) error {
+    data, err := p.Get(responseDepth, nil)
+    if err != nil {
+        return err
+    }
+    raw, err := json.Marshal(data)
+    if err != nil {
+        return err
     }
+    return json.Unmarshal(raw, dst)
 }
 
 // Get retrieves data from the endpoint
 func (p *DataModelProxy) Get(responseDepth *int, params map[string]string) (interface{}, error) {
+    if p.err != nil {
+        return nil, p.err
+    }
     fullPath := p.fullPath()
     return p.wrapper.Get(fullPath, responseDepth, params)
 }
 
 // Set updates data at the endpoint using PATCH
 func (p *DataModelProxy) Set(value interface{}) error {
+    if err := p.Validate(value); err != nil {
+        return err
+    }
     fullPath := p.fullPath()
-    return p.wrapper.Patch(fullPath, value)
+    err := p.wrapper.Patch(fullPath, value)
+    p.cache.Invalidate(fullPath)
+    return err
 }
 
 // Put updates data at the endpoint using PUT
 func (p *DataModelProxy) Put(value interface{}) error {
+    if err := p.Validate(value); err != nil {
+        return err
+    }
     fullPath := p.fullPath()
-    return p.wrapper.Put(fullPath, value)
+    err := p.wrapper.Put(fullPath, value)
+    p.cache.Invalidate(fullPath)
+    return err
 }
 
 // Delete removes data at the endpoint
 func (p *DataModelProxy) Delete() (interface{}, error) {
+    if p.err != nil {
+        return nil, p.err
+    }
     fullPath := p.fullPath()
-    return p.wrapper.Delete(fullPath)
+    result, err := p.wrapper.Delete(fullPath)
+    p.cache.Invalidate(fullPath)
+    return result, err
 }
 
 // GetItem creates a proxy for accessing array items
@@ -83,22 +198,36 @@ func (p *DataModelProxy) GetItem(index interface{}) *DataModelProxy {
         indexStr = fmt.Sprintf("%v", v)
     }
 
-    return NewDataModelProxyWithModel(
-        p.wrapper,
-        indexStr,
-        p.fullPath(),
-        p.dataModelPath(),
-    )
+    child := p.child(indexStr, p.fullPath(), p.dataModelPath())
+    if child.err == nil && p.schema != nil {
+        if node, err := p.Node(); err == nil && node.Type != NodeList && node.Type != NodeAny {
+            child.err = fmt.Errorf("%s is a %s, not a list", p.fullPath(), node.Type)
+        }
+    }
+    return child
 }
 
 // GetField creates a proxy for accessing nested fields
 func (p *DataModelProxy) GetField(fieldName string) *DataModelProxy {
-    return NewDataModelProxyWithModel(
-        p.wrapper,
-        fieldName,
-        p.fullPath(),
-        p.dataModelPath(),
-    )
+    child := p.child(fieldName, p.fullPath(), p.dataModelPath())
+    if child.err == nil && p.schema != nil {
+        _, child.err = p.schema.Lookup(child.fullPath())
+    }
+    return child
+}
+
+// child returns a proxy below p sharing its wrapper, schema, cache and
+// any recorded error. Only root proxies get a cache of their own.
+func (p *DataModelProxy) child(name, path, modelPath string) *DataModelProxy {
+    return &DataModelProxy{
+        wrapper:   p.wrapper,
+        name:      name,
+        path:      path,
+        modelPath: modelPath,
+        cache:     p.cache,
+        schema:    p.schema,
+        err:       p.err,
+    }
 }
 
 // fullPath returns the complete API path
@@ -117,9 +246,12 @@ func (p *DataModelProxy) dataModelPath() string {
     return p.modelPath + "/" + p.name
 }
 
-// URL returns the full API URL
+// URL returns the full API URL. Without a wrapper exposing its base URL
+// the path relative to the host is returned.
 func (p *DataModelProxy) URL() string {
-    // This would need the host from the wrapper, simplified for now
+    if b, ok := p.wrapper.(baseURLer); ok {
+        return b.BaseURL() + p.fullPath()
+    }
     return fmt.Sprintf("/bps/api/v2/core%s", p.fullPath())
 }
 
@@ -128,18 +260,56 @@ func (p *DataModelProxy) String() string {
     return fmt.Sprintf("DataModelProxy{name: %s, path: %s}", p.name, p.fullPath())
 }
 
+// SetCache replaces the cache of this proxy. Proxies derived with
+// GetField/GetItem share the cache of their parent.
+func (p *DataModelProxy) SetCache(cache *Cache) {
+    p.cache = cache
+}
+
+// Cache returns the cache used by this proxy
+func (p *DataModelProxy) Cache() *Cache {
+    return p.cache
+}
+
 // CachedGet retrieves and caches field values
 func (p *DataModelProxy) CachedGet(field string) (interface{}, error) {
-    if value, exists := p.cache[field]; exists {
+    return p.GetField(field).Cached()
+}
+
+// Cached returns the cached value of this node, reading it from the
+// endpoint on a miss
+func (p *DataModelProxy) Cached() (interface{}, error) {
+    if p.err != nil {
+        return nil, p.err
+    }
+    fullPath := p.fullPath()
+    if value, exists := p.cache.Get(fullPath); exists {
         return value, nil
     }
 
-    result, err := p.wrapper.Get(p.dataModelPath()+"/"+field, nil, nil)
+    result, err := p.wrapper.Get(fullPath, nil, nil)
     if err != nil {
         return nil, err
     }
 
-    p.cache[field] = result
+    p.cache.Set(fullPath, result)
     return result, nil
 }
 
+// Prefetch reads this node with the given responseDepth in a single
+// request and caches it together with every node below it, so that
+// subsequent Cached/CachedGet calls are served locally
+func (p *DataModelProxy) Prefetch(depth int) error {
+    result, err := p.Get(&depth, nil)
+    if err != nil {
+        return err
+    }
+    p.cache.fill(p.fullPath(), result, depth)
+    return nil
+}
+
+// Invalidate drops the cached values of this node and its subtree
+func (p *DataModelProxy) Invalidate() {
+    p.cache.Invalidate(p.fullPath())
+}
+
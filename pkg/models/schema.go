@@ -0,0 +1,414 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Schema node types
+const (
+	NodeObject  = "object"
+	NodeList    = "list"
+	NodeString  = "string"
+	NodeInteger = "integer"
+	NodeNumber  = "number"
+	NodeBoolean = "boolean"
+	NodeAny     = "any"
+)
+
+// SchemaNode describes one node of the BPS data model
+type SchemaNode struct {
+	Type     string                 `json:"type"`
+	Fields   map[string]*SchemaNode `json:"fields,omitempty"`
+	Items    *SchemaNode            `json:"items,omitempty"`
+	Enum     []string               `json:"enum,omitempty"`
+	Min      *float64               `json:"min,omitempty"`
+	Max      *float64               `json:"max,omitempty"`
+	ReadOnly bool                   `json:"readOnly,omitempty"`
+	// GoName and GoType are used by cmd/bpsgen to name the typed proxy of
+	// this node and the struct its value decodes into
+	GoName string `json:"goName,omitempty"`
+	GoType string `json:"goType,omitempty"`
+	// Inferred marks nodes derived from sample data. They are checked like
+	// any other node: the fields of a sample are the best evidence of the
+	// fields the chassis accepts.
+	Inferred bool `json:"inferred,omitempty"`
+	// Open objects accept fields the schema does not list, for nodes whose
+	// sample is known to be partial; see Schema.SetOpen
+	Open bool `json:"open,omitempty"`
+}
+
+// Schema describes the data model below the root nodes of the API
+type Schema struct {
+	Roots map[string]*SchemaNode `json:"roots"`
+}
+
+// DataModelRoots lists the root nodes of the BPS data model
+var DataModelRoots = []string{
+	"administration",
+	"capture",
+	"evasionProfile",
+	"loadProfile",
+	"network",
+	"remote",
+	"results",
+	"strikeList",
+	"superflow",
+	"testmodel",
+	"topology",
+}
+
+// ParseSchema decodes a schema document
+func ParseSchema(data []byte) (*Schema, error) {
+	s := &Schema{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	if s.Roots == nil {
+		return nil, fmt.Errorf("parse schema: no roots")
+	}
+	return s, nil
+}
+
+// LoadSchemaFile reads a schema document from disk
+func LoadSchemaFile(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchema(data)
+}
+
+// FetchSchema builds the schema from the chassis by reading each root node
+// with the given responseDepth and inferring the structure of the reply.
+// Roots that cannot be read are left out.
+func FetchSchema(wrapper BPSWrapper, depth int, roots ...string) (*Schema, error) {
+	if len(roots) == 0 {
+		roots = DataModelRoots
+	}
+	s := &Schema{Roots: make(map[string]*SchemaNode)}
+	var errs []string
+	for _, root := range roots {
+		data, err := wrapper.Get("/"+root, &depth, nil)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", root, err))
+			continue
+		}
+		s.Roots[root] = InferSchema(data)
+	}
+	if len(s.Roots) == 0 {
+		return nil, fmt.Errorf("fetch schema: %s", strings.Join(errs, "; "))
+	}
+	return s, nil
+}
+
+// InferSchema derives a schema node from a sample value. The nodes are
+// marked Inferred.
+func InferSchema(v interface{}) *SchemaNode {
+	node := inferSchema(v)
+	markInferred(node)
+	return node
+}
+
+func markInferred(n *SchemaNode) {
+	if n == nil {
+		return
+	}
+	n.Inferred = true
+	for _, f := range n.Fields {
+		markInferred(f)
+	}
+	markInferred(n.Items)
+}
+
+func inferSchema(v interface{}) *SchemaNode {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		node := &SchemaNode{Type: NodeObject, Fields: make(map[string]*SchemaNode, len(value))}
+		for k, item := range value {
+			node.Fields[k] = inferSchema(item)
+		}
+		return node
+	case []interface{}:
+		node := &SchemaNode{Type: NodeList}
+		for _, item := range value {
+			node.Items = mergeSchema(node.Items, inferSchema(item))
+		}
+		if node.Items == nil {
+			node.Items = &SchemaNode{Type: NodeAny}
+		}
+		return node
+	case string:
+		return &SchemaNode{Type: NodeString}
+	case bool:
+		return &SchemaNode{Type: NodeBoolean}
+	case float64, int, int64:
+		return &SchemaNode{Type: NodeNumber}
+	}
+	return &SchemaNode{Type: NodeAny}
+}
+
+func mergeSchema(a, b *SchemaNode) *SchemaNode {
+	switch {
+	case a == nil:
+		return b
+	case b == nil || b.Type == NodeAny:
+		return a
+	case a.Type == NodeAny:
+		return b
+	case a.Type != b.Type:
+		return &SchemaNode{Type: NodeAny}
+	}
+	switch a.Type {
+	case NodeObject:
+		for k, f := range b.Fields {
+			a.Fields[k] = mergeSchema(a.Fields[k], f)
+		}
+	case NodeList:
+		a.Items = mergeSchema(a.Items, b.Items)
+	}
+	return a
+}
+
+// SetOpen marks the object at path as open, so that fields missing from
+// its sample are accepted below it
+func (s *Schema) SetOpen(path string) error {
+	node, err := s.Lookup(path)
+	if err != nil {
+		return err
+	}
+	if node.Type != NodeObject {
+		return fmt.Errorf("%s is a %s, not an object", path, node.Type)
+	}
+	node.Open = true
+	return nil
+}
+
+// Lookup resolves a slash separated API path such as
+// "network/networkModel/ip_router/0/default_gateway". Segments following a
+// list node are taken as item indexes or ids.
+func (s *Schema) Lookup(path string) (*SchemaNode, error) {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	node, ok := s.Roots[segments[0]]
+	if !ok {
+		return nil, unknownField(segments[0], "/", s.Roots)
+	}
+	for i, seg := range segments[1:] {
+		parent := "/" + strings.Join(segments[:i+1], "/")
+		switch node.Type {
+		case NodeList:
+			node = node.Items
+		case NodeObject:
+			child, ok := node.Fields[seg]
+			if !ok && node.Open {
+				return &SchemaNode{Type: NodeAny}, nil
+			}
+			if !ok {
+				return nil, unknownField(seg, parent, node.Fields)
+			}
+			node = child
+		case NodeAny:
+			return &SchemaNode{Type: NodeAny}, nil
+		default:
+			return nil, fmt.Errorf("%s is a %s and has no field %q", parent, node.Type, seg)
+		}
+	}
+	return node, nil
+}
+
+// FieldNames returns the sorted child field names of an object node
+func (n *SchemaNode) FieldNames() []string {
+	names := make([]string, 0, len(n.Fields))
+	for k := range n.Fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks value against the node. The value is first passed
+// through JSON so structs, typed maps and slices are checked as they are
+// sent.
+func (n *SchemaNode) Validate(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("value: %w", err)
+	}
+	var plain interface{}
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return fmt.Errorf("value: %w", err)
+	}
+	return n.validate("", plain)
+}
+
+func (n *SchemaNode) validate(at string, value interface{}) error {
+	if n == nil || n.Type == NodeAny || value == nil {
+		return nil
+	}
+	where := at
+	if where == "" {
+		where = "value"
+	}
+	switch n.Type {
+	case NodeObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %T", where, value)
+		}
+		for k, v := range obj {
+			child, ok := n.Fields[k]
+			if !ok && n.Open {
+				continue
+			}
+			if !ok {
+				return unknownField(k, where, n.Fields)
+			}
+			if child.ReadOnly {
+				return fmt.Errorf("%s.%s is read only", where, k)
+			}
+			if err := child.validate(joinField(at, k), v); err != nil {
+				return err
+			}
+		}
+	case NodeList:
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected list, got %T", where, value)
+		}
+		for i, v := range list {
+			if err := n.Items.validate(fmt.Sprintf("%s[%d]", at, i), v); err != nil {
+				return err
+			}
+		}
+	case NodeString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string, got %T", where, value)
+		}
+		if len(n.Enum) > 0 && !containsString(n.Enum, s) {
+			return fmt.Errorf("%s: %q is not one of %s", where, s, strings.Join(n.Enum, ", "))
+		}
+	case NodeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %T", where, value)
+		}
+	case NodeInteger, NodeNumber:
+		f, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("%s: expected %s, got %T", where, n.Type, value)
+		}
+		if n.Type == NodeInteger && f != math.Trunc(f) {
+			return fmt.Errorf("%s: expected integer, got %v", where, value)
+		}
+		if n.Min != nil && f < *n.Min {
+			return fmt.Errorf("%s: %v is below minimum %v", where, value, *n.Min)
+		}
+		if n.Max != nil && f > *n.Max {
+			return fmt.Errorf("%s: %v is above maximum %v", where, value, *n.Max)
+		}
+	}
+	return nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func joinField(at, field string) string {
+	if at == "" {
+		return field
+	}
+	return at + "." + field
+}
+
+func splitPath(path string) []string {
+	var out []string
+	for _, seg := range strings.Split(path, "/") {
+		if seg != "" {
+			out = append(out, seg)
+		}
+	}
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownField reports a missing field, suggesting the closest known name
+func unknownField(name, parent string, fields map[string]*SchemaNode) error {
+	best, bestDist := "", -1
+	for k := range fields {
+		d := editDistance(strings.ToLower(name), strings.ToLower(k))
+		if bestDist < 0 || d < bestDist || (d == bestDist && k < best) {
+			best, bestDist = k, d
+		}
+	}
+	if best != "" && bestDist <= len(name)/3+1 {
+		return fmt.Errorf("unknown field %q under %s (did you mean %q?)", name, parent, best)
+	}
+	return fmt.Errorf("unknown field %q under %s", name, parent)
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
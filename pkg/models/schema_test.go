@@ -0,0 +1,154 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// recordingWrapper records the paths written through it
+type recordingWrapper struct {
+	BPSWrapper
+	writes []string
+}
+
+func (w *recordingWrapper) Patch(path string, value interface{}) error {
+	w.writes = append(w.writes, path)
+	return nil
+}
+
+func (w *recordingWrapper) Put(path string, value interface{}) error {
+	w.writes = append(w.writes, path)
+	return nil
+}
+
+func sampleSchema() *Schema {
+	return &Schema{Roots: map[string]*SchemaNode{
+		"loadProfile": InferSchema(map[string]interface{}{
+			"name":        "profile",
+			"description": "",
+			"phase": []interface{}{
+				map[string]interface{}{"duration": 10.0, "type": "up", "enabled": true},
+			},
+		}),
+		"network": InferSchema(map[string]interface{}{"name": "net"}),
+	}}
+}
+
+func TestSchemaLookup(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		open    string
+		want    string
+		wantErr string
+	}{
+		{name: "root", path: "/loadProfile", want: NodeObject},
+		{name: "list", path: "/loadProfile/phase", want: NodeList},
+		{name: "list item field", path: "/loadProfile/phase/0/duration", want: NodeNumber},
+		{name: "list item by id", path: "/loadProfile/phase/p1/type", want: NodeString},
+		{name: "unknown root", path: "/loadProfle", wantErr: `unknown field "loadProfle" under / (did you mean "loadProfile"?)`},
+		{name: "typo under an inferred object", path: "/loadProfile/descripton", wantErr: `did you mean "description"?`},
+		{name: "typo in a list item", path: "/loadProfile/phase/0/duraton", wantErr: `did you mean "duration"?`},
+		{name: "field of a scalar", path: "/loadProfile/name/x", wantErr: "is a string and has no field"},
+		{name: "open object", path: "/network/networkModel", open: "/network", want: NodeAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sampleSchema()
+			if tt.open != "" {
+				if err := s.SetOpen(tt.open); err != nil {
+					t.Fatal(err)
+				}
+			}
+			node, err := s.Lookup(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Lookup(%s) error = %v, want %q", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if node.Type != tt.want {
+				t.Errorf("Lookup(%s) = %s, want %s", tt.path, node.Type, tt.want)
+			}
+		})
+	}
+}
+
+func TestProxyIntrospection(t *testing.T) {
+	p := NewDataModelProxy(&recordingWrapper{}, "loadProfile", "")
+	p.SetSchema(sampleSchema())
+	if !p.IsObject() || p.IsList() {
+		t.Errorf("loadProfile: IsObject %v, IsList %v", p.IsObject(), p.IsList())
+	}
+	phase := p.GetField("phase")
+	if !phase.IsList() || phase.IsObject() {
+		t.Errorf("phase: IsObject %v, IsList %v", phase.IsObject(), phase.IsList())
+	}
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(fields, ","); got != "description,name,phase" {
+		t.Errorf("Fields() = %s", got)
+	}
+	if _, err := phase.GetItem(0).Fields(); err != nil {
+		t.Errorf("phase item Fields(): %v", err)
+	}
+	if err := p.GetField("loadProfle").Err(); err == nil {
+		t.Errorf("GetField(loadProfle) recorded no error")
+	}
+	if err := p.GetField("name").GetItem(0).Err(); err == nil || !strings.Contains(err.Error(), "not a list") {
+		t.Errorf("GetItem on a string: %v", err)
+	}
+}
+
+func TestProxySetValidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   []string
+		value   interface{}
+		wantErr string
+	}{
+		{name: "string", field: []string{"description"}, value: "nightly"},
+		{name: "number", field: []string{"phase", "0", "duration"}, value: 30},
+		{name: "object", field: []string{"phase", "0"}, value: map[string]interface{}{"duration": 5, "type": "steady"}},
+		{name: "number for a string", field: []string{"description"}, value: 3, wantErr: "expected string"},
+		{name: "string for a number", field: []string{"phase", "0", "duration"}, value: "30", wantErr: "expected number"},
+		{name: "string for a boolean", field: []string{"phase", "0", "enabled"}, value: "yes", wantErr: "expected boolean"},
+		{name: "unknown field in a value", field: []string{"phase", "0"}, value: map[string]interface{}{"duraton": 5}, wantErr: `did you mean "duration"?`},
+		{name: "unknown field", field: []string{"descripton"}, value: "x", wantErr: "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &recordingWrapper{}
+			p := NewDataModelProxy(w, "loadProfile", "")
+			p.SetSchema(sampleSchema())
+			for i, f := range tt.field {
+				if i > 0 && tt.field[i-1] == "phase" {
+					p = p.GetItem(f)
+				} else {
+					p = p.GetField(f)
+				}
+			}
+			err := p.Set(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Set(%v) error = %v, want %q", tt.value, err, tt.wantErr)
+				}
+				if len(w.writes) > 0 {
+					t.Errorf("rejected value was written to %v", w.writes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(w.writes) != 1 {
+				t.Errorf("writes = %v, want one", w.writes)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Timeline segment types
+const (
+	SegmentRampUp   = "RampUpSegment"
+	SegmentSteady   = "SteadySegment"
+	SegmentRampDown = "RampDownSegment"
+)
+
+// Phase is one stretch of a load shape, moving the load from the target of
+// the previous phase to Target over Duration
+type Phase struct {
+	Label    string
+	Type     string
+	Duration time.Duration
+	Target   float64
+}
+
+// LoadShape builds a component timeline from ramp up, steady state, step
+// and ramp down phases. Targets are rates or session counts in Unit.
+type LoadShape struct {
+	Unit   string
+	Phases []Phase
+	err    error
+}
+
+// NewLoadShape starts an empty load shape whose targets are in unit, such
+// as "Gbps" or "sessions"
+func NewLoadShape(unit string) *LoadShape {
+	return &LoadShape{Unit: unit}
+}
+
+// RampUp raises the load to target over d
+func (s *LoadShape) RampUp(d time.Duration, target float64) *LoadShape {
+	return s.add(Phase{Label: "Ramp Up", Type: SegmentRampUp, Duration: d, Target: target})
+}
+
+// Steady holds the current load for d
+func (s *LoadShape) Steady(d time.Duration) *LoadShape {
+	return s.add(Phase{Label: "Steady", Type: SegmentSteady, Duration: d, Target: s.current()})
+}
+
+// Hold runs at target for d, jumping to it without a ramp
+func (s *LoadShape) Hold(d time.Duration, target float64) *LoadShape {
+	return s.add(Phase{Label: "Steady", Type: SegmentSteady, Duration: d, Target: target})
+}
+
+// Steps holds every value from..to in increments of step for each, for
+// example Steps(1, 10, 1, time.Minute) for 1-10 Gbps in 1 Gbps steps. Such
+// a shape can be previewed but not turned into a single Timeline; use
+// StepValues to run one test per step.
+func (s *LoadShape) Steps(from, to, step float64, each time.Duration) *LoadShape {
+	values, err := StepValues(from, to, step)
+	if err != nil {
+		return s.fail(err)
+	}
+	for i, v := range values {
+		s.add(Phase{
+			Label:    fmt.Sprintf("Step %d (%s %s)", i+1, formatTarget(v), s.Unit),
+			Type:     SegmentSteady,
+			Duration: each,
+			Target:   v,
+		})
+	}
+	return s
+}
+
+// RampDown lowers the load to zero over d
+func (s *LoadShape) RampDown(d time.Duration) *LoadShape {
+	return s.add(Phase{Label: "Ramp Down", Type: SegmentRampDown, Duration: d})
+}
+
+func (s *LoadShape) add(p Phase) *LoadShape {
+	switch {
+	case p.Duration < time.Second:
+		return s.fail(fmt.Errorf("%s: duration %v is shorter than one second", p.Label, p.Duration))
+	case p.Duration%time.Second != 0:
+		return s.fail(fmt.Errorf("%s: duration %v is not a whole number of seconds", p.Label, p.Duration))
+	case p.Target < 0:
+		return s.fail(fmt.Errorf("%s: negative target %v", p.Label, p.Target))
+	}
+	s.Phases = append(s.Phases, p)
+	return s
+}
+
+func (s *LoadShape) fail(err error) *LoadShape {
+	if s.err == nil {
+		s.err = err
+	}
+	return s
+}
+
+func (s *LoadShape) current() float64 {
+	if len(s.Phases) == 0 {
+		return 0
+	}
+	return s.Phases[len(s.Phases)-1].Target
+}
+
+// Err returns the first error met while building the shape
+func (s *LoadShape) Err() error {
+	if s.err == nil && len(s.Phases) == 0 {
+		return fmt.Errorf("load shape has no phases")
+	}
+	return s.err
+}
+
+// Duration returns the total length of the shape
+func (s *LoadShape) Duration() time.Duration {
+	var d time.Duration
+	for _, p := range s.Phases {
+		d += p.Duration
+	}
+	return d
+}
+
+// Peak returns the highest target of the shape
+func (s *LoadShape) Peak() float64 {
+	peak := 0.0
+	for _, p := range s.Phases {
+		peak = math.Max(peak, p.Target)
+	}
+	return peak
+}
+
+// Timeline returns the component timeline for the shape. Timeline segments
+// carry no load of their own: a component ramps to and holds the single
+// target of its load parameter. Every ramp up and steady phase must
+// therefore target the peak of the shape; shapes with several levels, such
+// as Steps, are rejected and are run as one test per level instead.
+func (s *LoadShape) Timeline() (Timeline, error) {
+	if err := s.Err(); err != nil {
+		return Timeline{}, err
+	}
+	peak := s.Peak()
+	segments := make([]TimeSegment, len(s.Phases))
+	for i, p := range s.Phases {
+		if p.Type != SegmentRampDown && p.Target != peak {
+			return Timeline{}, fmt.Errorf("%s: target %s %s differs from the peak %s %s, which a component timeline cannot express",
+				p.Label, formatTarget(p.Target), s.Unit, formatTarget(peak), s.Unit)
+		}
+		segments[i] = TimeSegment{Label: p.Label, Size: int(p.Duration / time.Second), Type: p.Type}
+	}
+	return Timeline{TimeSegments: segments}, nil
+}
+
+// Preview renders the schedule as a table with the start, length and
+// target of every phase
+func (s *LoadShape) Preview() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PHASE\tSTART\tDURATION\tLOAD")
+	var start time.Duration
+	from := 0.0
+	for _, p := range s.Phases {
+		load := formatTarget(p.Target)
+		if from != p.Target {
+			load = formatTarget(from) + " -> " + load
+		}
+		fmt.Fprintf(w, "%s\t%v\t%v\t%s %s\n", p.Label, start, p.Duration, load, s.Unit)
+		start += p.Duration
+		from = p.Target
+	}
+	w.Flush()
+	fmt.Fprintf(&b, "total %v, peak %s %s\n", s.Duration(), formatTarget(s.Peak()), s.Unit)
+	if s.err != nil {
+		fmt.Fprintf(&b, "error: %v\n", s.err)
+	}
+	return b.String()
+}
+
+// StepValues returns from, from+step, ... up to and including to. It is
+// used both for step phases and to script a sweep across runs.
+func StepValues(from, to, step float64) ([]float64, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive, got %v", step)
+	}
+	if to < from {
+		return nil, fmt.Errorf("step range %v..%v is empty", from, to)
+	}
+	n := int(math.Floor((to-from)/step+1e-9)) + 1
+	values := make([]float64, n)
+	for i := range values {
+		// Multiply rather than accumulate so 0.1 steps do not drift
+		values[i] = math.Round((from+float64(i)*step)*1e9) / 1e9
+	}
+	return values, nil
+}
+
+func formatTarget(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
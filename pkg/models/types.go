@@ -119,14 +119,14 @@ type TimeSegment struct {
 
 // NetworkInfo represents network configuration information
 type NetworkInfo struct {
-    Name           string      `json:"name"`
-    Label          string      `json:"label"`
-    Description    string      `json:"description"`
-    InterfaceCount int         `json:"interfaceCount"`
-    CreatedBy      string      `json:"createdBy"`
-    CreatedOn      time.Time   `json:"createdOn"`
-    Revision       int         `json:"revision"`
-    NetworkModel   interface{} `json:"networkModel"`
+    Name           string        `json:"name"`
+    Label          string        `json:"label"`
+    Description    string        `json:"description"`
+    InterfaceCount int           `json:"interfaceCount"`
+    CreatedBy      string        `json:"createdBy"`
+    CreatedOn      time.Time     `json:"createdOn"`
+    Revision       int           `json:"revision"`
+    NetworkModel   *NetworkModel `json:"networkModel"`
 }
 
 // TopologyInfo represents chassis topology information
@@ -148,16 +148,19 @@ type Slot struct {
 
 // Port represents port information
 type Port struct {
-    ID           string `json:"id"`
-    Number       int    `json:"number"`
-    State        string `json:"state"`
-    Link         string `json:"link"`
-    Speed        int    `json:"speed"`
-    Media        string `json:"media"`
-    Group        int    `json:"group,omitempty"`
-    ReservedBy   string `json:"reservedBy,omitempty"`
-    Capture      bool   `json:"capture,omitempty"`
-    Owner        string `json:"owner,omitempty"`
+    ID                string `json:"id"`
+    Number            int    `json:"number"`
+    State             string `json:"state"`
+    Link              string `json:"link"`
+    Speed             int    `json:"speed"`
+    Media             string `json:"media"`
+    Group             int    `json:"group,omitempty"`
+    ReservedBy        string `json:"reservedBy,omitempty"`
+    Capture           bool   `json:"capture,omitempty"`
+    // CaptureFilter is a BPF expression, CaptureBufferSize in megabytes
+    CaptureFilter     string `json:"captureFilter,omitempty"`
+    CaptureBufferSize int    `json:"captureBufferSize,omitempty"`
+    Owner             string `json:"owner,omitempty"`
 }
 
 // StrikeInfo represents security strike information
@@ -0,0 +1,1307 @@
+// Code generated by bpsgen from api/bps-api.json. DO NOT EDIT.
+
+package models
+
+// NewAppProfileNode returns a typed proxy for /appProfile
+func NewAppProfileNode(wrapper BPSWrapper) *AppProfileNode {
+	return &AppProfileNode{NewDataModelProxy(wrapper, "appProfile", "")}
+}
+
+// AppProfileNode is a typed proxy for /appProfile
+type AppProfileNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *AppProfileNode) Value() (*AppProfile, error) {
+	depth := FullDepth
+	v := &AppProfile{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Description returns the description field
+func (n *AppProfileNode) Description() *DataModelProxy {
+	return n.GetField("description")
+}
+
+// Name returns the name field
+func (n *AppProfileNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// Superflow returns the superflow list
+func (n *AppProfileNode) Superflow() *AppProfileSuperflowItems {
+	return &AppProfileSuperflowItems{n.GetField("superflow")}
+}
+
+// WeightType returns the weightType field
+func (n *AppProfileNode) WeightType() *DataModelProxy {
+	return n.GetField("weightType")
+}
+
+// AppProfileSuperflowItems is a typed proxy for /appProfile/superflow
+type AppProfileSuperflowItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *AppProfileSuperflowItems) Item(index interface{}) *AppProfileSuperflowNode {
+	return &AppProfileSuperflowNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *AppProfileSuperflowItems) Values() ([]AppProfileSuperflow, error) {
+	depth := FullDepth
+	var v []AppProfileSuperflow
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// AppProfileSuperflowNode is a typed proxy for /appProfile/superflow/{item}
+type AppProfileSuperflowNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *AppProfileSuperflowNode) Value() (*AppProfileSuperflow, error) {
+	depth := FullDepth
+	v := &AppProfileSuperflow{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ID returns the id field
+func (n *AppProfileSuperflowNode) ID() *DataModelProxy {
+	return n.GetField("id")
+}
+
+// Seed returns the seed field
+func (n *AppProfileSuperflowNode) Seed() *DataModelProxy {
+	return n.GetField("seed")
+}
+
+// Superflow returns the superflow field
+func (n *AppProfileSuperflowNode) Superflow() *DataModelProxy {
+	return n.GetField("superflow")
+}
+
+// Weight returns the weight field
+func (n *AppProfileSuperflowNode) Weight() *DataModelProxy {
+	return n.GetField("weight")
+}
+
+// NewEvasionProfileNode returns a typed proxy for /evasionProfile
+func NewEvasionProfileNode(wrapper BPSWrapper) *EvasionProfileNode {
+	return &EvasionProfileNode{NewDataModelProxy(wrapper, "evasionProfile", "")}
+}
+
+// EvasionProfileNode is a typed proxy for /evasionProfile
+type EvasionProfileNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *EvasionProfileNode) Value() (*EvasionProfile, error) {
+	depth := FullDepth
+	v := &EvasionProfile{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// StrikeOptions returns the StrikeOptions field
+func (n *EvasionProfileNode) StrikeOptions() *DataModelProxy {
+	return n.GetField("StrikeOptions")
+}
+
+// Description returns the description field
+func (n *EvasionProfileNode) Description() *DataModelProxy {
+	return n.GetField("description")
+}
+
+// Label returns the label field
+func (n *EvasionProfileNode) Label() *DataModelProxy {
+	return n.GetField("label")
+}
+
+// Name returns the name field
+func (n *EvasionProfileNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// NewLoadProfileNode returns a typed proxy for /loadProfile
+func NewLoadProfileNode(wrapper BPSWrapper) *LoadProfileNode {
+	return &LoadProfileNode{NewDataModelProxy(wrapper, "loadProfile", "")}
+}
+
+// LoadProfileNode is a typed proxy for /loadProfile
+type LoadProfileNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *LoadProfileNode) Value() (*LoadProfile, error) {
+	depth := FullDepth
+	v := &LoadProfile{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Description returns the description field
+func (n *LoadProfileNode) Description() *DataModelProxy {
+	return n.GetField("description")
+}
+
+// Label returns the label field
+func (n *LoadProfileNode) Label() *DataModelProxy {
+	return n.GetField("label")
+}
+
+// Name returns the name field
+func (n *LoadProfileNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// Phase returns the phase field
+func (n *LoadProfileNode) Phase() *DataModelProxy {
+	return n.GetField("phase")
+}
+
+// NewNetworkNode returns a typed proxy for /network
+func NewNetworkNode(wrapper BPSWrapper) *NetworkNode {
+	return &NetworkNode{NewDataModelProxy(wrapper, "network", "")}
+}
+
+// NetworkNode is a typed proxy for /network
+type NetworkNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *NetworkNode) Value() (*Network, error) {
+	depth := FullDepth
+	v := &Network{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// CreatedBy returns the createdBy field
+func (n *NetworkNode) CreatedBy() *DataModelProxy {
+	return n.GetField("createdBy")
+}
+
+// CreatedOn returns the createdOn field
+func (n *NetworkNode) CreatedOn() *DataModelProxy {
+	return n.GetField("createdOn")
+}
+
+// Description returns the description field
+func (n *NetworkNode) Description() *DataModelProxy {
+	return n.GetField("description")
+}
+
+// InterfaceCount returns the interfaceCount field
+func (n *NetworkNode) InterfaceCount() *DataModelProxy {
+	return n.GetField("interfaceCount")
+}
+
+// Label returns the label field
+func (n *NetworkNode) Label() *DataModelProxy {
+	return n.GetField("label")
+}
+
+// Name returns the name field
+func (n *NetworkNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// NetworkModel returns the networkModel field
+func (n *NetworkNode) NetworkModel() *DataModelProxy {
+	return n.GetField("networkModel")
+}
+
+// Revision returns the revision field
+func (n *NetworkNode) Revision() *DataModelProxy {
+	return n.GetField("revision")
+}
+
+// NewStrikeListNode returns a typed proxy for /strikeList
+func NewStrikeListNode(wrapper BPSWrapper) *StrikeListNode {
+	return &StrikeListNode{NewDataModelProxy(wrapper, "strikeList", "")}
+}
+
+// StrikeListNode is a typed proxy for /strikeList
+type StrikeListNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *StrikeListNode) Value() (*StrikeList, error) {
+	depth := FullDepth
+	v := &StrikeList{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Description returns the description field
+func (n *StrikeListNode) Description() *DataModelProxy {
+	return n.GetField("description")
+}
+
+// Name returns the name field
+func (n *StrikeListNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// NumStrikes returns the numStrikes field
+func (n *StrikeListNode) NumStrikes() *DataModelProxy {
+	return n.GetField("numStrikes")
+}
+
+// QueryString returns the queryString field
+func (n *StrikeListNode) QueryString() *DataModelProxy {
+	return n.GetField("queryString")
+}
+
+// Strikes returns the strikes list
+func (n *StrikeListNode) Strikes() *StrikeItems {
+	return &StrikeItems{n.GetField("strikes")}
+}
+
+// StrikeItems is a typed proxy for /strikeList/strikes
+type StrikeItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *StrikeItems) Item(index interface{}) *StrikeNode {
+	return &StrikeNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *StrikeItems) Values() ([]StrikeInfo, error) {
+	depth := FullDepth
+	var v []StrikeInfo
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// StrikeNode is a typed proxy for /strikeList/strikes/{item}
+type StrikeNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *StrikeNode) Value() (*StrikeInfo, error) {
+	depth := FullDepth
+	v := &StrikeInfo{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Category returns the category field
+func (n *StrikeNode) Category() *DataModelProxy {
+	return n.GetField("category")
+}
+
+// Direction returns the direction field
+func (n *StrikeNode) Direction() *DataModelProxy {
+	return n.GetField("direction")
+}
+
+// FileExtension returns the fileExtension field
+func (n *StrikeNode) FileExtension() *DataModelProxy {
+	return n.GetField("fileExtension")
+}
+
+// FileSize returns the fileSize field
+func (n *StrikeNode) FileSize() *DataModelProxy {
+	return n.GetField("fileSize")
+}
+
+// ID returns the id field
+func (n *StrikeNode) ID() *DataModelProxy {
+	return n.GetField("id")
+}
+
+// Keyword returns the keyword list
+func (n *StrikeNode) Keyword() *KeywordItems {
+	return &KeywordItems{n.GetField("keyword")}
+}
+
+// Name returns the name field
+func (n *StrikeNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// PathField returns the path field
+func (n *StrikeNode) PathField() *DataModelProxy {
+	return n.GetField("path")
+}
+
+// Protocol returns the protocol field
+func (n *StrikeNode) Protocol() *DataModelProxy {
+	return n.GetField("protocol")
+}
+
+// Reference returns the reference list
+func (n *StrikeNode) Reference() *ReferenceItems {
+	return &ReferenceItems{n.GetField("reference")}
+}
+
+// Severity returns the severity field
+func (n *StrikeNode) Severity() *DataModelProxy {
+	return n.GetField("severity")
+}
+
+// Variants returns the variants field
+func (n *StrikeNode) Variants() *DataModelProxy {
+	return n.GetField("variants")
+}
+
+// Year returns the year field
+func (n *StrikeNode) Year() *DataModelProxy {
+	return n.GetField("year")
+}
+
+// KeywordItems is a typed proxy for /strikeList/strikes/{item}/keyword
+type KeywordItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *KeywordItems) Item(index interface{}) *KeywordNode {
+	return &KeywordNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *KeywordItems) Values() ([]Keyword, error) {
+	depth := FullDepth
+	var v []Keyword
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// KeywordNode is a typed proxy for /strikeList/strikes/{item}/keyword/{item}
+type KeywordNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *KeywordNode) Value() (*Keyword, error) {
+	depth := FullDepth
+	v := &Keyword{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Name returns the name field
+func (n *KeywordNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// ReferenceItems is a typed proxy for /strikeList/strikes/{item}/reference
+type ReferenceItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *ReferenceItems) Item(index interface{}) *ReferenceNode {
+	return &ReferenceNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *ReferenceItems) Values() ([]Reference, error) {
+	depth := FullDepth
+	var v []Reference
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ReferenceNode is a typed proxy for /strikeList/strikes/{item}/reference/{item}
+type ReferenceNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *ReferenceNode) Value() (*Reference, error) {
+	depth := FullDepth
+	v := &Reference{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Label returns the label field
+func (n *ReferenceNode) Label() *DataModelProxy {
+	return n.GetField("label")
+}
+
+// Type returns the type field
+func (n *ReferenceNode) Type() *DataModelProxy {
+	return n.GetField("type")
+}
+
+// ValueField returns the value field
+func (n *ReferenceNode) ValueField() *DataModelProxy {
+	return n.GetField("value")
+}
+
+// NewSuperflowNode returns a typed proxy for /superflow
+func NewSuperflowNode(wrapper BPSWrapper) *SuperflowNode {
+	return &SuperflowNode{NewDataModelProxy(wrapper, "superflow", "")}
+}
+
+// SuperflowNode is a typed proxy for /superflow
+type SuperflowNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *SuperflowNode) Value() (*Superflow, error) {
+	depth := FullDepth
+	v := &Superflow{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Actions returns the actions list
+func (n *SuperflowNode) Actions() *SuperflowActionItems {
+	return &SuperflowActionItems{n.GetField("actions")}
+}
+
+// Description returns the description field
+func (n *SuperflowNode) Description() *DataModelProxy {
+	return n.GetField("description")
+}
+
+// Flows returns the flows list
+func (n *SuperflowNode) Flows() *SuperflowFlowItems {
+	return &SuperflowFlowItems{n.GetField("flows")}
+}
+
+// Hosts returns the hosts list
+func (n *SuperflowNode) Hosts() *SuperflowHostItems {
+	return &SuperflowHostItems{n.GetField("hosts")}
+}
+
+// Name returns the name field
+func (n *SuperflowNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// PercentBandwidth returns the percentBandwidth field
+func (n *SuperflowNode) PercentBandwidth() *DataModelProxy {
+	return n.GetField("percentBandwidth")
+}
+
+// PercentFlows returns the percentFlows field
+func (n *SuperflowNode) PercentFlows() *DataModelProxy {
+	return n.GetField("percentFlows")
+}
+
+// SuperflowActionItems is a typed proxy for /superflow/actions
+type SuperflowActionItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *SuperflowActionItems) Item(index interface{}) *SuperflowActionNode {
+	return &SuperflowActionNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *SuperflowActionItems) Values() ([]SuperflowAction, error) {
+	depth := FullDepth
+	var v []SuperflowAction
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SuperflowActionNode is a typed proxy for /superflow/actions/{item}
+type SuperflowActionNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *SuperflowActionNode) Value() (*SuperflowAction, error) {
+	depth := FullDepth
+	v := &SuperflowAction{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ActionInfo returns the actionInfo field
+func (n *SuperflowActionNode) ActionInfo() *DataModelProxy {
+	return n.GetField("actionInfo")
+}
+
+// Flowid returns the flowid field
+func (n *SuperflowActionNode) Flowid() *DataModelProxy {
+	return n.GetField("flowid")
+}
+
+// ID returns the id field
+func (n *SuperflowActionNode) ID() *DataModelProxy {
+	return n.GetField("id")
+}
+
+// Label returns the label field
+func (n *SuperflowActionNode) Label() *DataModelProxy {
+	return n.GetField("label")
+}
+
+// Source returns the source field
+func (n *SuperflowActionNode) Source() *DataModelProxy {
+	return n.GetField("source")
+}
+
+// Type returns the type field
+func (n *SuperflowActionNode) Type() *DataModelProxy {
+	return n.GetField("type")
+}
+
+// SuperflowFlowItems is a typed proxy for /superflow/flows
+type SuperflowFlowItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *SuperflowFlowItems) Item(index interface{}) *SuperflowFlowNode {
+	return &SuperflowFlowNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *SuperflowFlowItems) Values() ([]SuperflowFlow, error) {
+	depth := FullDepth
+	var v []SuperflowFlow
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SuperflowFlowNode is a typed proxy for /superflow/flows/{item}
+type SuperflowFlowNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *SuperflowFlowNode) Value() (*SuperflowFlow, error) {
+	depth := FullDepth
+	v := &SuperflowFlow{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// From returns the from field
+func (n *SuperflowFlowNode) From() *DataModelProxy {
+	return n.GetField("from")
+}
+
+// ID returns the id field
+func (n *SuperflowFlowNode) ID() *DataModelProxy {
+	return n.GetField("id")
+}
+
+// Label returns the label field
+func (n *SuperflowFlowNode) Label() *DataModelProxy {
+	return n.GetField("label")
+}
+
+// Params returns the params field
+func (n *SuperflowFlowNode) Params() *DataModelProxy {
+	return n.GetField("params")
+}
+
+// Protocol returns the protocol field
+func (n *SuperflowFlowNode) Protocol() *DataModelProxy {
+	return n.GetField("protocol")
+}
+
+// To returns the to field
+func (n *SuperflowFlowNode) To() *DataModelProxy {
+	return n.GetField("to")
+}
+
+// SuperflowHostItems is a typed proxy for /superflow/hosts
+type SuperflowHostItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *SuperflowHostItems) Item(index interface{}) *SuperflowHostNode {
+	return &SuperflowHostNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *SuperflowHostItems) Values() ([]SuperflowHost, error) {
+	depth := FullDepth
+	var v []SuperflowHost
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SuperflowHostNode is a typed proxy for /superflow/hosts/{item}
+type SuperflowHostNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *SuperflowHostNode) Value() (*SuperflowHost, error) {
+	depth := FullDepth
+	v := &SuperflowHost{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Hostname returns the hostname field
+func (n *SuperflowHostNode) Hostname() *DataModelProxy {
+	return n.GetField("hostname")
+}
+
+// ID returns the id field
+func (n *SuperflowHostNode) ID() *DataModelProxy {
+	return n.GetField("id")
+}
+
+// Iface returns the iface field
+func (n *SuperflowHostNode) Iface() *DataModelProxy {
+	return n.GetField("iface")
+}
+
+// NewTestModelNode returns a typed proxy for /testmodel
+func NewTestModelNode(wrapper BPSWrapper) *TestModelNode {
+	return &TestModelNode{NewDataModelProxy(wrapper, "testmodel", "")}
+}
+
+// TestModelNode is a typed proxy for /testmodel
+type TestModelNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *TestModelNode) Value() (*TestModel, error) {
+	depth := FullDepth
+	v := &TestModel{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Author returns the author field
+func (n *TestModelNode) Author() *DataModelProxy {
+	return n.GetField("author")
+}
+
+// Component returns the component list
+func (n *TestModelNode) Component() *ComponentItems {
+	return &ComponentItems{n.GetField("component")}
+}
+
+// CreatedBy returns the createdBy field
+func (n *TestModelNode) CreatedBy() *DataModelProxy {
+	return n.GetField("createdBy")
+}
+
+// CreatedOn returns the createdOn field
+func (n *TestModelNode) CreatedOn() *DataModelProxy {
+	return n.GetField("createdOn")
+}
+
+// Description returns the description field
+func (n *TestModelNode) Description() *DataModelProxy {
+	return n.GetField("description")
+}
+
+// Dut returns the dut field
+func (n *TestModelNode) Dut() *DataModelProxy {
+	return n.GetField("dut")
+}
+
+// Name returns the name field
+func (n *TestModelNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// Network returns the network field
+func (n *TestModelNode) Network() *DataModelProxy {
+	return n.GetField("network")
+}
+
+// Revision returns the revision field
+func (n *TestModelNode) Revision() *DataModelProxy {
+	return n.GetField("revision")
+}
+
+// ComponentItems is a typed proxy for /testmodel/component
+type ComponentItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *ComponentItems) Item(index interface{}) *ComponentNode {
+	return &ComponentNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *ComponentItems) Values() ([]ComponentInfo, error) {
+	depth := FullDepth
+	var v []ComponentInfo
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ComponentNode is a typed proxy for /testmodel/component/{item}
+type ComponentNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *ComponentNode) Value() (*ComponentInfo, error) {
+	depth := FullDepth
+	v := &ComponentInfo{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Active returns the active field
+func (n *ComponentNode) Active() *DataModelProxy {
+	return n.GetField("active")
+}
+
+// ID returns the id field
+func (n *ComponentNode) ID() *DataModelProxy {
+	return n.GetField("id")
+}
+
+// Label returns the label field
+func (n *ComponentNode) Label() *DataModelProxy {
+	return n.GetField("label")
+}
+
+// OriginalPreset returns the originalPreset field
+func (n *ComponentNode) OriginalPreset() *DataModelProxy {
+	return n.GetField("originalPreset")
+}
+
+// OriginalPresetLabel returns the originalPresetLabel field
+func (n *ComponentNode) OriginalPresetLabel() *DataModelProxy {
+	return n.GetField("originalPresetLabel")
+}
+
+// ReportResults returns the reportResults field
+func (n *ComponentNode) ReportResults() *DataModelProxy {
+	return n.GetField("reportResults")
+}
+
+// Tags returns the tags list
+func (n *ComponentNode) Tags() *TagItems {
+	return &TagItems{n.GetField("tags")}
+}
+
+// Timeline returns the timeline field
+func (n *ComponentNode) Timeline() *TimelineNode {
+	return &TimelineNode{n.GetField("timeline")}
+}
+
+// Type returns the type field
+func (n *ComponentNode) Type() *DataModelProxy {
+	return n.GetField("type")
+}
+
+// TagItems is a typed proxy for /testmodel/component/{item}/tags
+type TagItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *TagItems) Item(index interface{}) *TagNode {
+	return &TagNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *TagItems) Values() ([]Tag, error) {
+	depth := FullDepth
+	var v []Tag
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TagNode is a typed proxy for /testmodel/component/{item}/tags/{item}
+type TagNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *TagNode) Value() (*Tag, error) {
+	depth := FullDepth
+	v := &Tag{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// DomainId returns the domainId field
+func (n *TagNode) DomainId() *DomainIDNode {
+	return &DomainIDNode{n.GetField("domainId")}
+}
+
+// ID returns the id field
+func (n *TagNode) ID() *DataModelProxy {
+	return n.GetField("id")
+}
+
+// Type returns the type field
+func (n *TagNode) Type() *DataModelProxy {
+	return n.GetField("type")
+}
+
+// DomainIDNode is a typed proxy for /testmodel/component/{item}/tags/{item}/domainId
+type DomainIDNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *DomainIDNode) Value() (*DomainID, error) {
+	depth := FullDepth
+	v := &DomainID{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// External returns the external field
+func (n *DomainIDNode) External() *DataModelProxy {
+	return n.GetField("external")
+}
+
+// Iface returns the iface field
+func (n *DomainIDNode) Iface() *DataModelProxy {
+	return n.GetField("iface")
+}
+
+// Name returns the name field
+func (n *DomainIDNode) Name() *DataModelProxy {
+	return n.GetField("name")
+}
+
+// TimelineNode is a typed proxy for /testmodel/component/{item}/timeline
+type TimelineNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *TimelineNode) Value() (*Timeline, error) {
+	depth := FullDepth
+	v := &Timeline{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Timesegment returns the timesegment list
+func (n *TimelineNode) Timesegment() *TimeSegmentItems {
+	return &TimeSegmentItems{n.GetField("timesegment")}
+}
+
+// TimeSegmentItems is a typed proxy for /testmodel/component/{item}/timeline/timesegment
+type TimeSegmentItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *TimeSegmentItems) Item(index interface{}) *TimeSegmentNode {
+	return &TimeSegmentNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *TimeSegmentItems) Values() ([]TimeSegment, error) {
+	depth := FullDepth
+	var v []TimeSegment
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TimeSegmentNode is a typed proxy for /testmodel/component/{item}/timeline/timesegment/{item}
+type TimeSegmentNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *TimeSegmentNode) Value() (*TimeSegment, error) {
+	depth := FullDepth
+	v := &TimeSegment{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Label returns the label field
+func (n *TimeSegmentNode) Label() *DataModelProxy {
+	return n.GetField("label")
+}
+
+// Size returns the size field
+func (n *TimeSegmentNode) Size() *DataModelProxy {
+	return n.GetField("size")
+}
+
+// Type returns the type field
+func (n *TimeSegmentNode) Type() *DataModelProxy {
+	return n.GetField("type")
+}
+
+// NewTopologyNode returns a typed proxy for /topology
+func NewTopologyNode(wrapper BPSWrapper) *TopologyNode {
+	return &TopologyNode{NewDataModelProxy(wrapper, "topology", "")}
+}
+
+// TopologyNode is a typed proxy for /topology
+type TopologyNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *TopologyNode) Value() (*TopologyInfo, error) {
+	depth := FullDepth
+	v := &TopologyInfo{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Model returns the model field
+func (n *TopologyNode) Model() *DataModelProxy {
+	return n.GetField("model")
+}
+
+// RunningTest returns the runningTest field
+func (n *TopologyNode) RunningTest() *DataModelProxy {
+	return n.GetField("runningTest")
+}
+
+// SerialNumber returns the serialNumber field
+func (n *TopologyNode) SerialNumber() *DataModelProxy {
+	return n.GetField("serialNumber")
+}
+
+// Slot returns the slot list
+func (n *TopologyNode) Slot() *SlotItems {
+	return &SlotItems{n.GetField("slot")}
+}
+
+// SlotItems is a typed proxy for /topology/slot
+type SlotItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *SlotItems) Item(index interface{}) *SlotNode {
+	return &SlotNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *SlotItems) Values() ([]Slot, error) {
+	depth := FullDepth
+	var v []Slot
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// SlotNode is a typed proxy for /topology/slot/{item}
+type SlotNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *SlotNode) Value() (*Slot, error) {
+	depth := FullDepth
+	v := &Slot{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ID returns the id field
+func (n *SlotNode) ID() *DataModelProxy {
+	return n.GetField("id")
+}
+
+// InterfaceCount returns the interfaceCount field
+func (n *SlotNode) InterfaceCount() *DataModelProxy {
+	return n.GetField("interfaceCount")
+}
+
+// Model returns the model field
+func (n *SlotNode) Model() *DataModelProxy {
+	return n.GetField("model")
+}
+
+// Port returns the port list
+func (n *SlotNode) Port() *PortItems {
+	return &PortItems{n.GetField("port")}
+}
+
+// SerialNumber returns the serialNumber field
+func (n *SlotNode) SerialNumber() *DataModelProxy {
+	return n.GetField("serialNumber")
+}
+
+// State returns the state field
+func (n *SlotNode) State() *DataModelProxy {
+	return n.GetField("state")
+}
+
+// PortItems is a typed proxy for /topology/slot/{item}/port
+type PortItems struct {
+	*DataModelProxy
+}
+
+// Item returns the list element with the given index or id
+func (l *PortItems) Item(index interface{}) *PortNode {
+	return &PortNode{l.GetItem(index)}
+}
+
+// Values reads every element of the list
+func (l *PortItems) Values() ([]Port, error) {
+	depth := FullDepth
+	var v []Port
+	if err := l.Decode(&depth, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// PortNode is a typed proxy for /topology/slot/{item}/port/{item}
+type PortNode struct {
+	*DataModelProxy
+}
+
+// Value reads the node
+func (n *PortNode) Value() (*Port, error) {
+	depth := FullDepth
+	v := &Port{}
+	if err := n.Decode(&depth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Capture returns the capture field
+func (n *PortNode) Capture() *DataModelProxy {
+	return n.GetField("capture")
+}
+
+// CaptureBufferSize returns the captureBufferSize field
+func (n *PortNode) CaptureBufferSize() *DataModelProxy {
+	return n.GetField("captureBufferSize")
+}
+
+// CaptureFilter returns the captureFilter field
+func (n *PortNode) CaptureFilter() *DataModelProxy {
+	return n.GetField("captureFilter")
+}
+
+// Group returns the group field
+func (n *PortNode) Group() *DataModelProxy {
+	return n.GetField("group")
+}
+
+// ID returns the id field
+func (n *PortNode) ID() *DataModelProxy {
+	return n.GetField("id")
+}
+
+// Link returns the link field
+func (n *PortNode) Link() *DataModelProxy {
+	return n.GetField("link")
+}
+
+// Media returns the media field
+func (n *PortNode) Media() *DataModelProxy {
+	return n.GetField("media")
+}
+
+// Number returns the number field
+func (n *PortNode) Number() *DataModelProxy {
+	return n.GetField("number")
+}
+
+// Owner returns the owner field
+func (n *PortNode) Owner() *DataModelProxy {
+	return n.GetField("owner")
+}
+
+// ReservedBy returns the reservedBy field
+func (n *PortNode) ReservedBy() *DataModelProxy {
+	return n.GetField("reservedBy")
+}
+
+// Speed returns the speed field
+func (n *PortNode) Speed() *DataModelProxy {
+	return n.GetField("speed")
+}
+
+// State returns the state field
+func (n *PortNode) State() *DataModelProxy {
+	return n.GetField("state")
+}
+
+// AppProfile is the value of a AppProfileNode
+type AppProfile struct {
+	Description string                `json:"description,omitempty"`
+	Name        string                `json:"name,omitempty"`
+	Superflow   []AppProfileSuperflow `json:"superflow,omitempty"`
+	WeightType  string                `json:"weightType,omitempty"`
+}
+
+// AppProfileSuperflow is the value of a AppProfileSuperflowNode
+type AppProfileSuperflow struct {
+	ID        int    `json:"id,omitempty"`
+	Seed      int    `json:"seed,omitempty"`
+	Superflow string `json:"superflow,omitempty"`
+	Weight    int    `json:"weight,omitempty"`
+}
+
+// EvasionProfile is the value of a EvasionProfileNode
+type EvasionProfile struct {
+	StrikeOptions interface{} `json:"StrikeOptions,omitempty"`
+	Description   string      `json:"description,omitempty"`
+	Label         string      `json:"label,omitempty"`
+	Name          string      `json:"name,omitempty"`
+}
+
+// LoadProfile is the value of a LoadProfileNode
+type LoadProfile struct {
+	Description string      `json:"description,omitempty"`
+	Label       string      `json:"label,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Phase       []LoadPhase `json:"phase,omitempty"`
+}
+
+// Network is the value of a NetworkNode
+type Network struct {
+	CreatedBy      string        `json:"createdBy,omitempty"`
+	CreatedOn      string        `json:"createdOn,omitempty"`
+	Description    string        `json:"description,omitempty"`
+	InterfaceCount int           `json:"interfaceCount,omitempty"`
+	Label          string        `json:"label,omitempty"`
+	Name           string        `json:"name,omitempty"`
+	NetworkModel   *NetworkModel `json:"networkModel,omitempty"`
+	Revision       int           `json:"revision,omitempty"`
+}
+
+// StrikeList is the value of a StrikeListNode
+type StrikeList struct {
+	Description string       `json:"description,omitempty"`
+	Name        string       `json:"name,omitempty"`
+	NumStrikes  int          `json:"numStrikes,omitempty"`
+	QueryString string       `json:"queryString,omitempty"`
+	Strikes     []StrikeInfo `json:"strikes,omitempty"`
+}
+
+// Superflow is the value of a SuperflowNode
+type Superflow struct {
+	Actions          []SuperflowAction `json:"actions,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Flows            []SuperflowFlow   `json:"flows,omitempty"`
+	Hosts            []SuperflowHost   `json:"hosts,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	PercentBandwidth float64           `json:"percentBandwidth,omitempty"`
+	PercentFlows     float64           `json:"percentFlows,omitempty"`
+}
+
+// SuperflowAction is the value of a SuperflowActionNode
+type SuperflowAction struct {
+	ActionInfo interface{} `json:"actionInfo,omitempty"`
+	Flowid     int         `json:"flowid,omitempty"`
+	ID         int         `json:"id,omitempty"`
+	Label      string      `json:"label,omitempty"`
+	Source     string      `json:"source,omitempty"`
+	Type       string      `json:"type,omitempty"`
+}
+
+// SuperflowFlow is the value of a SuperflowFlowNode
+type SuperflowFlow struct {
+	From     string      `json:"from,omitempty"`
+	ID       int         `json:"id,omitempty"`
+	Label    string      `json:"label,omitempty"`
+	Params   interface{} `json:"params,omitempty"`
+	Protocol string      `json:"protocol,omitempty"`
+	To       string      `json:"to,omitempty"`
+}
+
+// SuperflowHost is the value of a SuperflowHostNode
+type SuperflowHost struct {
+	Hostname string `json:"hostname,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Iface    string `json:"iface,omitempty"`
+}
+
+// TestModel is the value of a TestModelNode
+type TestModel struct {
+	Author      string          `json:"author,omitempty"`
+	Component   []ComponentInfo `json:"component,omitempty"`
+	CreatedBy   string          `json:"createdBy,omitempty"`
+	CreatedOn   string          `json:"createdOn,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Dut         string          `json:"dut,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Network     string          `json:"network,omitempty"`
+	Revision    int             `json:"revision,omitempty"`
+}
@@ -0,0 +1,38 @@
+package netplan
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes one row per subnet of a plan, the form firewall rules
+// are usually requested in.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	header := []string{"domain", "interface", "vlan", "subnet", "gateway", "first_host", "last_host", "hosts"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		vlan := ""
+		if e.VLAN != 0 {
+			vlan = strconv.Itoa(e.VLAN)
+		}
+		row := []string{
+			e.Domain,
+			strconv.Itoa(e.Interface),
+			vlan,
+			e.Subnet.String(),
+			e.Gateway.String(),
+			e.FirstHost.String(),
+			e.LastHost.String(),
+			strconv.Itoa(e.Hosts),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,297 @@
+// Package netplan generates IPv4 address plans for network neighborhoods:
+// per domain it carves a CIDR block into one subnet per VLAN, places a
+// gateway and a host range in each and emits the matching network
+// elements.
+package netplan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"bps-client-go/pkg/models"
+	"bps-client-go/pkg/operations"
+)
+
+// Domain is the plan of one domain.
+type Domain struct {
+	Name string
+	// Interface is the test interface number the domain lives on.
+	Interface int
+	// CIDR is the block subnets are carved from, e.g. "10.1.0.0/16".
+	CIDR string
+	// SubnetPrefix is the prefix length of each subnet; zero uses the
+	// whole block as a single subnet.
+	SubnetPrefix int
+	// FirstVLAN and VLANs select VLAN IDs FirstVLAN..FirstVLAN+VLANs-1, one
+	// subnet each; VLANs zero plans a single untagged subnet.
+	FirstVLAN int
+	VLANs     int
+	// Hosts is the number of hosts per subnet.
+	Hosts int
+	// Gateway is the offset of the gateway, the DUT address hosts route
+	// through, within each subnet: 1 is the first usable address, -1 the
+	// last. Zero defaults to 1. Hosts follow the gateway when it is at the
+	// start of the subnet and start at the first usable address otherwise.
+	Gateway int
+}
+
+// Plan is a set of domains and the DUT subnets they must stay clear of.
+type Plan struct {
+	Domains    []Domain
+	DUTSubnets []string
+}
+
+// Entry is one generated subnet.
+type Entry struct {
+	Domain    string
+	Interface int
+	VLAN      int
+	Subnet    *net.IPNet
+	Gateway   net.IP
+	FirstHost net.IP
+	LastHost  net.IP
+	Hosts     int
+	// HostsID and VLANID are the ids of the generated elements.
+	HostsID string
+	VLANID  string
+}
+
+// Generate builds the network model of the plan. It fails if a domain does
+// not fit its block, if subnets of different domains overlap, if any of
+// them overlaps a DUT subnet or if two domains tag the same VLAN on one
+// interface.
+func (p *Plan) Generate() (*models.NetworkModel, []Entry, error) {
+	var dut []*net.IPNet
+	for _, s := range p.DUTSubnets {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DUT subnet: %w", err)
+		}
+		dut = append(dut, n)
+	}
+	m := &models.NetworkModel{}
+	ifaces := make(map[int]bool)
+	vlans := make(map[[2]int]string)
+	var entries []Entry
+	for _, d := range p.Domains {
+		es, err := d.subnets()
+		if err != nil {
+			return nil, nil, fmt.Errorf("domain %s: %w", d.Name, err)
+		}
+		for _, e := range es {
+			for _, n := range dut {
+				if overlaps(e.Subnet, n) {
+					return nil, nil, fmt.Errorf("domain %s: subnet %s overlaps DUT subnet %s", d.Name, e.Subnet, n)
+				}
+			}
+			for _, prev := range entries {
+				if overlaps(e.Subnet, prev.Subnet) {
+					return nil, nil, fmt.Errorf("domain %s: subnet %s overlaps subnet %s of domain %s", d.Name, e.Subnet, prev.Subnet, prev.Domain)
+				}
+			}
+			if e.VLAN == 0 {
+				continue
+			}
+			key := [2]int{d.Interface, e.VLAN}
+			if other, ok := vlans[key]; ok {
+				return nil, nil, fmt.Errorf("domain %s: VLAN %d on interface %d is already used by domain %s", d.Name, e.VLAN, d.Interface, other)
+			}
+			vlans[key] = d.Name
+		}
+		if !ifaces[d.Interface] {
+			ifaces[d.Interface] = true
+			m.Interfaces = append(m.Interfaces, models.NetInterface{
+				ID:         interfaceID(d.Interface),
+				Number:     d.Interface,
+				MACAddress: fmt.Sprintf("02:1a:c5:%02x:00:00", d.Interface),
+			})
+		}
+		for i := range es {
+			e := &es[i]
+			container := interfaceID(d.Interface)
+			if e.VLAN != 0 {
+				e.VLANID = fmt.Sprintf("%s VLAN %d", d.Name, e.VLAN)
+				m.VLANs = append(m.VLANs, models.VLAN{ID: e.VLANID, DefaultContainer: container, InnerVLAN: e.VLAN})
+				container = e.VLANID
+			}
+			e.HostsID = fmt.Sprintf("%s hosts %d", d.Name, i+1)
+			ones, _ := e.Subnet.Mask.Size()
+			m.IPStaticHosts = append(m.IPStaticHosts, models.IPStaticHosts{
+				ID:               e.HostsID,
+				DefaultContainer: container,
+				Tags: []models.Tag{{
+					ID:       d.Name,
+					Type:     "domain",
+					DomainID: models.DomainID{Name: d.Name, Iface: interfaceID(d.Interface)},
+				}},
+				IPAddress:        e.FirstHost.String(),
+				Count:            e.Hosts,
+				Netmask:          ones,
+				GatewayIPAddress: e.Gateway.String(),
+			})
+		}
+		entries = append(entries, es...)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return m, entries, nil
+}
+
+// Apply generates the plan and saves it with ops as the network
+// neighborhood name. With an empty base the plan is written into a new
+// network; otherwise it is added to the elements of the network base,
+// reusing its interfaces by number, and the result is saved as name, which
+// may be base itself.
+func (p *Plan) Apply(ops *operations.NetworkOps, name, base string, force bool) ([]Entry, error) {
+	m, entries, err := p.Generate()
+	if err != nil {
+		return nil, err
+	}
+	if base == "" {
+		if err := ops.Create(name, nil, m, force); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+	edit := func(dst *models.NetworkModel) error { return merge(dst, m) }
+	if _, err := ops.Modify(base, name, edit, force); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// merge adds the elements of the generated model src to dst. Interfaces
+// dst already has for a number are reused, and a VLAN tag dst already uses
+// on an interface is an error.
+func merge(dst, src *models.NetworkModel) error {
+	byNumber := make(map[int]string)
+	for _, iface := range dst.Interfaces {
+		byNumber[iface.Number] = iface.ID
+	}
+	rename := make(map[string]string)
+	for _, iface := range src.Interfaces {
+		if id, ok := byNumber[iface.Number]; ok {
+			rename[iface.ID] = id
+			continue
+		}
+		dst.Interfaces = append(dst.Interfaces, iface)
+	}
+	container := func(id string) string {
+		if to, ok := rename[id]; ok {
+			return to
+		}
+		return id
+	}
+	for _, v := range src.VLANs {
+		v.DefaultContainer = container(v.DefaultContainer)
+		for _, prev := range dst.VLANs {
+			if prev.DefaultContainer == v.DefaultContainer && prev.InnerVLAN == v.InnerVLAN {
+				return fmt.Errorf("VLAN %d on %s is already used by %s", v.InnerVLAN, v.DefaultContainer, prev.ID)
+			}
+		}
+		dst.VLANs = append(dst.VLANs, v)
+	}
+	for _, h := range src.IPStaticHosts {
+		h.DefaultContainer = container(h.DefaultContainer)
+		tags := make([]models.Tag, len(h.Tags))
+		for i, t := range h.Tags {
+			t.DomainID.Iface = container(t.DomainID.Iface)
+			tags[i] = t
+		}
+		h.Tags = tags
+		dst.IPStaticHosts = append(dst.IPStaticHosts, h)
+	}
+	return nil
+}
+
+// subnets carves the block of d into its subnets.
+func (d *Domain) subnets() ([]Entry, error) {
+	_, block, err := net.ParseCIDR(d.CIDR)
+	if err != nil {
+		return nil, err
+	}
+	if block.IP.To4() == nil {
+		return nil, fmt.Errorf("%s is not an IPv4 block", d.CIDR)
+	}
+	blockOnes, _ := block.Mask.Size()
+	prefix := d.SubnetPrefix
+	if prefix == 0 {
+		prefix = blockOnes
+	}
+	if prefix < blockOnes || prefix > 30 {
+		return nil, fmt.Errorf("subnet prefix /%d does not fit block %s", prefix, block)
+	}
+	count := d.VLANs
+	if count == 0 {
+		count = 1
+	}
+	if available := 1 << uint(prefix-blockOnes); count > available {
+		return nil, fmt.Errorf("%d subnets of /%d do not fit in %s (room for %d)", count, prefix, block, available)
+	}
+	if d.VLANs > 0 && (d.FirstVLAN < 1 || d.FirstVLAN+d.VLANs-1 > 4094) {
+		return nil, fmt.Errorf("VLANs %d..%d out of range 1..4094", d.FirstVLAN, d.FirstVLAN+d.VLANs-1)
+	}
+	size := uint32(1) << uint(32-prefix)
+	usable := int(size) - 2
+	if d.Hosts < 1 || d.Hosts > usable-1 {
+		return nil, fmt.Errorf("%d hosts do not fit a /%d with a gateway (at most %d)", d.Hosts, prefix, usable-1)
+	}
+	gw := d.Gateway
+	if gw == 0 {
+		gw = 1
+	}
+	if gw > usable || -gw > usable {
+		return nil, fmt.Errorf("gateway offset %d outside the subnet", gw)
+	}
+	base := binary.BigEndian.Uint32(block.IP.To4())
+	mask := net.CIDRMask(prefix, 32)
+	entries := make([]Entry, count)
+	for i := range entries {
+		network := base + uint32(i)*size
+		var gateway, first uint32
+		if gw > 0 {
+			gateway = network + uint32(gw)
+			first = gateway + 1
+		} else {
+			gateway = network + size - 1 + uint32(int32(gw))
+			first = network + 1
+		}
+		last := first + uint32(d.Hosts) - 1
+		if first <= gateway && gateway <= last {
+			return nil, fmt.Errorf("gateway offset %d falls inside the %d hosts", gw, d.Hosts)
+		}
+		if last >= network+size-1 {
+			return nil, fmt.Errorf("%d hosts after gateway offset %d do not fit a /%d", d.Hosts, gw, prefix)
+		}
+		e := Entry{
+			Domain:    d.Name,
+			Interface: d.Interface,
+			Subnet:    &net.IPNet{IP: ipv4(network), Mask: mask},
+			Gateway:   ipv4(gateway),
+			FirstHost: ipv4(first),
+			LastHost:  ipv4(last),
+			Hosts:     d.Hosts,
+		}
+		if d.VLANs > 0 {
+			e.VLAN = d.FirstVLAN + i
+		}
+		entries[i] = e
+	}
+	return entries, nil
+}
+
+func interfaceID(n int) string {
+	return fmt.Sprintf("Interface %d", n)
+}
+
+func ipv4(v uint32) net.IP {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, v)
+	return ip
+}
+
+func overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
@@ -0,0 +1,215 @@
+package netplan
+
+import (
+	"strings"
+	"testing"
+
+	"bps-client-go/pkg/models"
+)
+
+func TestSubnets(t *testing.T) {
+	type subnet struct {
+		vlan                         int
+		subnet, gateway, first, last string
+	}
+	tests := []struct {
+		name    string
+		domain  Domain
+		want    []subnet
+		wantErr string
+	}{
+		{
+			name:   "untagged whole block",
+			domain: Domain{CIDR: "10.1.0.0/24", Hosts: 10},
+			want:   []subnet{{0, "10.1.0.0/24", "10.1.0.1", "10.1.0.2", "10.1.0.11"}},
+		},
+		{
+			name:   "one subnet per VLAN",
+			domain: Domain{CIDR: "10.1.0.0/16", SubnetPrefix: 24, FirstVLAN: 100, VLANs: 2, Hosts: 5},
+			want: []subnet{
+				{100, "10.1.0.0/24", "10.1.0.1", "10.1.0.2", "10.1.0.6"},
+				{101, "10.1.1.0/24", "10.1.1.1", "10.1.1.2", "10.1.1.6"},
+			},
+		},
+		{
+			name:   "gateway at the end",
+			domain: Domain{CIDR: "192.168.0.0/28", Hosts: 4, Gateway: -1},
+			want:   []subnet{{0, "192.168.0.0/28", "192.168.0.14", "192.168.0.1", "192.168.0.4"}},
+		},
+		{
+			name:   "block not aligned",
+			domain: Domain{CIDR: "10.1.2.3/24", Hosts: 1},
+			want:   []subnet{{0, "10.1.2.0/24", "10.1.2.1", "10.1.2.2", "10.1.2.2"}},
+		},
+		{
+			name:    "too many subnets",
+			domain:  Domain{CIDR: "10.1.0.0/23", SubnetPrefix: 24, FirstVLAN: 1, VLANs: 3, Hosts: 1},
+			wantErr: "do not fit",
+		},
+		{
+			name:    "too many hosts",
+			domain:  Domain{CIDR: "10.1.0.0/29", Hosts: 6},
+			wantErr: "hosts do not fit",
+		},
+		{
+			name:    "gateway inside the hosts",
+			domain:  Domain{CIDR: "10.1.0.0/29", Hosts: 5, Gateway: -2},
+			wantErr: "falls inside",
+		},
+		{
+			name:    "VLAN out of range",
+			domain:  Domain{CIDR: "10.1.0.0/16", SubnetPrefix: 24, FirstVLAN: 4094, VLANs: 2, Hosts: 1},
+			wantErr: "out of range",
+		},
+		{
+			name:    "IPv6 block",
+			domain:  Domain{CIDR: "2001:db8::/64", Hosts: 1},
+			wantErr: "not an IPv4 block",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.domain.subnets()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("subnets() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d subnets, want %d", len(got), len(tt.want))
+			}
+			for i, w := range tt.want {
+				e := got[i]
+				if e.VLAN != w.vlan || e.Subnet.String() != w.subnet || e.Gateway.String() != w.gateway ||
+					e.FirstHost.String() != w.first || e.LastHost.String() != w.last {
+					t.Errorf("subnet %d = %d %s gw %s hosts %s-%s, want %d %s gw %s hosts %s-%s", i,
+						e.VLAN, e.Subnet, e.Gateway, e.FirstHost, e.LastHost,
+						w.vlan, w.subnet, w.gateway, w.first, w.last)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		plan    Plan
+		wantErr string
+	}{
+		{
+			name: "two domains",
+			plan: Plan{Domains: []Domain{
+				{Name: "client", Interface: 1, CIDR: "10.1.0.0/16", SubnetPrefix: 24, FirstVLAN: 10, VLANs: 2, Hosts: 10},
+				{Name: "server", Interface: 2, CIDR: "10.2.0.0/24", Hosts: 10},
+			}},
+		},
+		{
+			name: "same VLAN on different interfaces",
+			plan: Plan{Domains: []Domain{
+				{Name: "a", Interface: 1, CIDR: "10.1.0.0/24", FirstVLAN: 10, VLANs: 1, Hosts: 10},
+				{Name: "b", Interface: 2, CIDR: "10.2.0.0/24", FirstVLAN: 10, VLANs: 1, Hosts: 10},
+			}},
+		},
+		{
+			name: "same VLAN on one interface",
+			plan: Plan{Domains: []Domain{
+				{Name: "a", Interface: 1, CIDR: "10.1.0.0/24", FirstVLAN: 10, VLANs: 1, Hosts: 10},
+				{Name: "b", Interface: 1, CIDR: "10.2.0.0/24", FirstVLAN: 10, VLANs: 1, Hosts: 10},
+			}},
+			wantErr: "VLAN 10 on interface 1 is already used by domain a",
+		},
+		{
+			name: "overlapping domains",
+			plan: Plan{Domains: []Domain{
+				{Name: "a", Interface: 1, CIDR: "10.1.0.0/16", Hosts: 10},
+				{Name: "b", Interface: 2, CIDR: "10.1.2.0/24", Hosts: 10},
+			}},
+			wantErr: "overlaps subnet 10.1.0.0/16 of domain a",
+		},
+		{
+			name: "overlapping a DUT subnet",
+			plan: Plan{
+				Domains:    []Domain{{Name: "a", Interface: 1, CIDR: "10.1.0.0/24", Hosts: 10}},
+				DUTSubnets: []string{"10.0.0.0/8"},
+			},
+			wantErr: "overlaps DUT subnet",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, entries, err := tt.plan.Generate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Generate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(m.IPStaticHosts) != len(entries) {
+				t.Errorf("%d hosts elements for %d entries", len(m.IPStaticHosts), len(entries))
+			}
+			for _, e := range entries {
+				if e.VLAN != 0 && e.VLANID == "" {
+					t.Errorf("entry %s has VLAN %d but no VLAN element", e.Subnet, e.VLAN)
+				}
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := func() *models.NetworkModel {
+		return &models.NetworkModel{
+			Interfaces: []models.NetInterface{{ID: "client port", Number: 1}},
+			VLANs:      []models.VLAN{{ID: "old", DefaultContainer: "client port", InnerVLAN: 5}},
+		}
+	}
+	tests := []struct {
+		name    string
+		domain  Domain
+		wantErr string
+	}{
+		{
+			name:   "reuses the interface",
+			domain: Domain{Name: "a", Interface: 1, CIDR: "10.1.0.0/24", FirstVLAN: 10, VLANs: 1, Hosts: 10},
+		},
+		{
+			name:   "adds a new interface",
+			domain: Domain{Name: "a", Interface: 2, CIDR: "10.1.0.0/24", FirstVLAN: 5, VLANs: 1, Hosts: 10},
+		},
+		{
+			name:    "VLAN already tagged",
+			domain:  Domain{Name: "a", Interface: 1, CIDR: "10.1.0.0/24", FirstVLAN: 5, VLANs: 1, Hosts: 10},
+			wantErr: "VLAN 5 on client port is already used by old",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, _, err := (&Plan{Domains: []Domain{tt.domain}}).Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			dst := base()
+			err = merge(dst, src)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("merge() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := dst.Validate(); err != nil {
+				t.Errorf("merged model: %v", err)
+			}
+		})
+	}
+}
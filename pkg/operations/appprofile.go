@@ -1,41 +1,131 @@
 package operations
 
-type AppProfileOps struct {
-	Client ClientWrapper
+import (
+	"fmt"
+	"strings"
+
+	"bps-client-go/pkg/models"
+)
+
+// WorkingProfile reads the working app profile.
+func (a *AppProfileOps) WorkingProfile() (*models.AppProfile, error) {
+	depth := models.FullDepth
+	resp, err := a.Client.Get("/appProfile", &depth, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read app profile: %w", err)
+	}
+	p := &models.AppProfile{}
+	if err := decodeInto(resp, p); err != nil {
+		return nil, fmt.Errorf("decode app profile: %w", err)
+	}
+	return p, nil
 }
 
-func (a *AppProfileOps) Add(add []map[string]interface{}) (interface{}, error) {
-	return a.Client.Post("/appProfile/operations/add", map[string]interface{}{"add": add})
+// CheckSuperflows looks every superflow of p up by its exact name and
+// reports the ones that do not exist.
+func (a *AppProfileOps) CheckSuperflows(p *models.AppProfile) error {
+	superflows := &SuperflowOps{Client: a.Client}
+	var missing []string
+	for _, name := range p.Names() {
+		found, err := findByName(superflows.Search, name, "result", "items")
+		if err != nil {
+			return fmt.Errorf("superflow %s: %w", name, err)
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("app profile %s: unknown superflows: %s", p.Name, strings.Join(missing, ", "))
+	}
+	return nil
 }
 
-func (a *AppProfileOps) Delete(name string) (interface{}, error) {
-	return a.Client.Post("/appProfile/operations/delete", map[string]interface{}{"name": name})
+// Create validates p, checks its superflows exist and saves it as a new
+// app profile named p.Name.
+func (a *AppProfileOps) Create(p *models.AppProfile, force bool) error {
+	if err := a.check(p); err != nil {
+		return err
+	}
+	if _, err := a.New(nil); err != nil {
+		return fmt.Errorf("new app profile: %w", err)
+	}
+	return a.write(p.Name, p, nil, force)
 }
 
-func (a *AppProfileOps) ExportAppProfile(name string, attachments bool, filepath string) error {
-	params := map[string]interface{}{
-		"name":        name,
-		"attachments": attachments,
-		"filepath":    filepath,
+// Modify lets edit change the app profile name and rebuilds it from the
+// result: the superflows it held are removed and the edited ones added in
+// their new order. The profile is saved as saveAs, replacing an existing
+// one only with force, or over name itself when saveAs is empty. Nothing
+// is written when edit fails, the profile is invalid or one of its
+// superflows does not exist.
+func (a *AppProfileOps) Modify(name, saveAs string, edit func(*models.AppProfile) error, force bool) (*models.AppProfile, error) {
+	if _, err := a.Load(name); err != nil {
+		return nil, fmt.Errorf("load app profile %s: %w", name, err)
+	}
+	p, err := a.WorkingProfile()
+	if err != nil {
+		return nil, err
+	}
+	current := append([]models.AppProfileSuperflow(nil), p.Superflow...)
+	if err := edit(p); err != nil {
+		return nil, err
 	}
-	return a.Client.Export("/appProfile/operations/exportAppProfile", filepath, params)
+	if err := a.check(p); err != nil {
+		return nil, err
+	}
+	if saveAs == "" {
+		saveAs = name
+		force = true
+	}
+	p.Name = saveAs
+	return p, a.write(saveAs, p, current, force)
 }
 
-func (a *AppProfileOps) ImportAppProfile(name, filename string, force bool) (interface{}, error) {
-	params := map[string]interface{}{
-		"name":     name,
-		"filename": filename,
-		"force":    force,
+// Normalize rescales the weights of the saved app profile name to add up
+// to total and saves it in place.
+func (a *AppProfileOps) Normalize(name string, total int) (*models.AppProfile, error) {
+	return a.Modify(name, "", func(p *models.AppProfile) error {
+		return p.Normalize(total)
+	}, true)
+}
+
+func (a *AppProfileOps) check(p *models.AppProfile) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("app profile %s: %w", p.Name, err)
 	}
-	return a.Client.Import("/appProfile/operations/importAppProfile", filename, params)
+	return a.CheckSuperflows(p)
 }
 
-func (a *AppProfileOps) Search(searchString, limit, sort, sortorder string) (interface{}, error) {
-	params := map[string]interface{}{
-		"searchString": searchString,
-		"limit":        limit,
-		"sort":         sort,
-		"sortorder":    sortorder,
+// write replaces the superflows of the working profile, which currently
+// holds current, with those of p and saves it as name.
+func (a *AppProfileOps) write(name string, p *models.AppProfile, current []models.AppProfileSuperflow, force bool) error {
+	if len(current) > 0 {
+		ids := make([]int, len(current))
+		for i, e := range current {
+			ids[i] = e.ID
+		}
+		if _, err := a.Remove(ids); err != nil {
+			return fmt.Errorf("remove superflows: %w", err)
+		}
+	}
+	add := make([]map[string]interface{}, len(p.Superflow))
+	for i, e := range p.Superflow {
+		entry := map[string]interface{}{"superflow": e.Superflow, "weight": e.Weight}
+		if e.Seed != 0 {
+			entry["seed"] = e.Seed
+		}
+		add[i] = entry
 	}
-	return a.Client.Post("/appProfile/operations/search", params)
-}
\ No newline at end of file
+	if _, err := a.Add(add); err != nil {
+		return fmt.Errorf("add superflows: %w", err)
+	}
+	patch := map[string]interface{}{"weightType": p.WeightType, "description": p.Description}
+	if err := a.Client.Patch("/appProfile", patch); err != nil {
+		return fmt.Errorf("write app profile: %w", err)
+	}
+	if _, err := a.SaveAs(name, force); err != nil {
+		return fmt.Errorf("save app profile %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package operations
+
+import "strings"
+
+// stubClient records every request and answers them with handle
+type stubClient struct {
+	handle func(method, path string, body interface{}) (interface{}, error)
+	calls  []string
+}
+
+func (c *stubClient) do(method, path string, body interface{}) (interface{}, error) {
+	c.calls = append(c.calls, method+" "+path)
+	if c.handle == nil {
+		return nil, nil
+	}
+	return c.handle(method, path, body)
+}
+
+func (c *stubClient) Get(path string, responseDepth *int, params map[string]string) (interface{}, error) {
+	return c.do("GET", path, params)
+}
+
+func (c *stubClient) Post(path string, data interface{}) (interface{}, error) {
+	return c.do("POST", path, data)
+}
+
+func (c *stubClient) Put(path string, value interface{}) error {
+	_, err := c.do("PUT", path, value)
+	return err
+}
+
+func (c *stubClient) Patch(path string, value interface{}) error {
+	_, err := c.do("PATCH", path, value)
+	return err
+}
+
+func (c *stubClient) Delete(path string) (interface{}, error) {
+	return c.do("DELETE", path, nil)
+}
+
+func (c *stubClient) Export(path, filepath string, params map[string]interface{}) error {
+	_, err := c.do("EXPORT", path, params)
+	return err
+}
+
+func (c *stubClient) Import(path, filename string, params map[string]interface{}) (interface{}, error) {
+	return c.do("IMPORT", path, params)
+}
+
+func (c *stubClient) EnableProfiling(enabled bool) {}
+func (c *stubClient) PrintVersions()               {}
+func (c *stubClient) PrintProfilingData()          {}
+
+// writes returns the recorded requests that may change the chassis
+func (c *stubClient) writes() []string {
+	var out []string
+	for _, call := range c.calls {
+		if !strings.HasPrefix(call, "GET ") && !strings.HasSuffix(call, "/operations/search") {
+			out = append(out, call)
+		}
+	}
+	return out
+}
@@ -0,0 +1,494 @@
+package operations
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bps-client-go/pkg/models"
+)
+
+// Component types with typed accessors
+const (
+	ComponentAppSim       = "appsim"
+	ComponentSecurityNP   = "security_np"
+	ComponentClientSim    = "clientsim"
+	ComponentRoutingRobot = "routingrobot"
+)
+
+// Choice is one allowed value of an enumerated parameter.
+type Choice struct {
+	Value string
+	Label string
+}
+
+// ParamDef describes a component parameter as returned by
+// testComponentDefinition. Nested parameters are flattened into dotted
+// names such as "sessions.max".
+type ParamDef struct {
+	Name        string
+	Label       string
+	Type        string
+	Description string
+	Default     interface{}
+	Min         *float64
+	Max         *float64
+	Choices     []Choice
+}
+
+// Resolve checks value against the definition and returns the value to
+// send. Enumerated values may be given by value or by label.
+func (d *ParamDef) Resolve(value interface{}) (interface{}, error) {
+	if len(d.Choices) > 0 {
+		s := fmt.Sprintf("%v", value)
+		for _, c := range d.Choices {
+			if c.Value == s || strings.EqualFold(c.Label, s) {
+				return c.Value, nil
+			}
+		}
+		allowed := make([]string, len(d.Choices))
+		for i, c := range d.Choices {
+			allowed[i] = c.Value
+		}
+		return nil, fmt.Errorf("parameter %s: %q is not one of %s", d.Name, s, strings.Join(allowed, ", "))
+	}
+	switch strings.ToLower(d.Type) {
+	case "integer", "int", "long":
+		f, ok := number(value)
+		if !ok || f != math.Trunc(f) {
+			return nil, fmt.Errorf("parameter %s: expected integer, got %v", d.Name, value)
+		}
+		return int64(f), d.checkRange(f)
+	case "number", "float", "double":
+		f, ok := number(value)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s: expected number, got %v", d.Name, value)
+		}
+		return f, d.checkRange(f)
+	case "boolean", "bool":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("parameter %s: expected boolean, got %v", d.Name, value)
+	case "string", "text":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("parameter %s: expected string, got %T", d.Name, value)
+	}
+	return value, nil
+}
+
+// Integer reports whether the parameter takes whole numbers only.
+func (d *ParamDef) Integer() bool {
+	switch strings.ToLower(d.Type) {
+	case "integer", "int", "long":
+		return true
+	}
+	return false
+}
+
+func (d *ParamDef) checkRange(f float64) error {
+	if d.Min != nil && f < *d.Min {
+		return fmt.Errorf("parameter %s: %v is below minimum %v", d.Name, f, *d.Min)
+	}
+	if d.Max != nil && f > *d.Max {
+		return fmt.Errorf("parameter %s: %v is above maximum %v", d.Name, f, *d.Max)
+	}
+	return nil
+}
+
+// ComponentDefinition is the parameter schema of a component type.
+type ComponentDefinition struct {
+	Type   string
+	Params map[string]*ParamDef
+}
+
+// Param returns the definition of a parameter.
+func (d *ComponentDefinition) Param(name string) (*ParamDef, error) {
+	if p, ok := d.Params[name]; ok {
+		return p, nil
+	}
+	best := ""
+	for n := range d.Params {
+		if strings.EqualFold(n, name) || (best == "" && strings.Contains(strings.ToLower(n), strings.ToLower(name))) {
+			best = n
+		}
+	}
+	if best != "" {
+		return nil, fmt.Errorf("%s has no parameter %q (did you mean %q?)", d.Type, name, best)
+	}
+	return nil, fmt.Errorf("%s has no parameter %q", d.Type, name)
+}
+
+// Names returns the sorted parameter names.
+func (d *ComponentDefinition) Names() []string {
+	names := make([]string, 0, len(d.Params))
+	for n := range d.Params {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ComponentDefinition reads the parameter schema of a component type with
+// dynamic enums resolved by the chassis.
+func (t *TestModelOps) ComponentDefinition(componentType string) (*ComponentDefinition, error) {
+	resp, err := t.TestComponentDefinition(componentType, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("read definition of %s: %w", componentType, err)
+	}
+	def := &ComponentDefinition{Type: componentType, Params: make(map[string]*ParamDef)}
+	parseParams(def.Params, "", resp)
+	if len(def.Params) == 0 {
+		return nil, fmt.Errorf("definition of %s has no parameters", componentType)
+	}
+	return def, nil
+}
+
+// parseParams flattens the parameter tree of a definition. Parameters are
+// given either as a list of objects carrying a name/id or as an object
+// keyed by name; groups nest further parameters.
+func parseParams(out map[string]*ParamDef, prefix string, v interface{}) {
+	switch value := v.(type) {
+	case []interface{}:
+		for _, item := range value {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := toString(m["id"])
+			if name == "" {
+				name = toString(m["name"])
+			}
+			if name != "" {
+				parseParam(out, prefix+name, m)
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"parameters", "params", "parameter", "inputs", "fields"} {
+			if children, ok := value[key]; ok {
+				parseParams(out, prefix, children)
+				return
+			}
+		}
+		for name, item := range value {
+			if m, ok := item.(map[string]interface{}); ok {
+				parseParam(out, prefix+name, m)
+			}
+		}
+	}
+}
+
+func parseParam(out map[string]*ParamDef, name string, m map[string]interface{}) {
+	for _, key := range []string{"parameters", "params", "fields"} {
+		if children, ok := m[key]; ok {
+			parseParams(out, name+".", children)
+			return
+		}
+	}
+	def := &ParamDef{
+		Name:        name,
+		Label:       toString(m["label"]),
+		Type:        toString(m["type"]),
+		Description: toString(m["description"]),
+		Default:     m["default"],
+		Min:         optNumber(m, "min", "minimum"),
+		Max:         optNumber(m, "max", "maximum"),
+	}
+	for _, key := range []string{"choice", "choices", "enum", "values"} {
+		items, ok := m[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			switch c := item.(type) {
+			case map[string]interface{}:
+				value := toString(c["value"])
+				if value == "" {
+					value = toString(c["name"])
+				}
+				def.Choices = append(def.Choices, Choice{Value: value, Label: toString(c["label"])})
+			default:
+				def.Choices = append(def.Choices, Choice{Value: toString(c)})
+			}
+		}
+		break
+	}
+	out[name] = def
+}
+
+func optNumber(m map[string]interface{}, keys ...string) *float64 {
+	for _, k := range keys {
+		if f, ok := number(m[k]); ok {
+			return &f
+		}
+	}
+	return nil
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Component edits the parameters of one test model component, checking
+// every value against the component definition. Changes are staged on the
+// ModelEdit the component was obtained from.
+type Component struct {
+	ID   string
+	Type string
+	Def  *ComponentDefinition
+	edit *ModelEdit
+}
+
+// Component returns an editor for the component id of the working model.
+func (e *ModelEdit) Component(id string) (*Component, error) {
+	def, err := e.definition(id)
+	if err != nil {
+		return nil, err
+	}
+	return &Component{ID: id, Type: def.Type, Def: def, edit: e}, nil
+}
+
+// definition returns the definition of the type of component id, reading
+// each component and definition once per edit.
+func (e *ModelEdit) definition(id string) (*ComponentDefinition, error) {
+	compType, ok := e.types[id]
+	if !ok {
+		resp, err := e.ops.GetComponent(id)
+		if err != nil {
+			return nil, fmt.Errorf("read component %s: %w", id, err)
+		}
+		m, ok := resp.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected response for component %s: %#v", id, resp)
+		}
+		compType = toString(m["type"])
+		e.types[id] = compType
+	}
+	def, ok := e.defs[compType]
+	if !ok {
+		var err error
+		if def, err = e.ops.ComponentDefinition(compType); err != nil {
+			return nil, err
+		}
+		e.defs[compType] = def
+	}
+	return def, nil
+}
+
+// check resolves the values of a staged patch against def. A value given
+// for a group of parameters as a whole, such as a timeline, is sent as is.
+func (d *ComponentDefinition) check(prefix string, patch map[string]interface{}) error {
+	for k, value := range patch {
+		name := prefix + k
+		if p, ok := d.Params[name]; ok {
+			resolved, err := p.Resolve(value)
+			if err != nil {
+				return err
+			}
+			patch[k] = resolved
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			if err := d.check(name+".", nested); err != nil {
+				return err
+			}
+			continue
+		}
+		if !d.group(name) {
+			_, err := d.Param(name)
+			return err
+		}
+	}
+	return nil
+}
+
+// group reports whether name is the prefix of nested parameters
+func (d *ComponentDefinition) group(name string) bool {
+	for n := range d.Params {
+		if strings.HasPrefix(n, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Get reads the current value of a parameter from the working model.
+func (c *Component) Get(param string) (interface{}, error) {
+	if _, err := c.Def.Param(param); err != nil {
+		return nil, err
+	}
+	path := "/testmodel/component/" + c.ID + "/" + strings.ReplaceAll(param, ".", "/")
+	return c.edit.ops.Client.Get(path, nil, nil)
+}
+
+// Set checks value against the definition and stages it.
+func (c *Component) Set(param string, value interface{}) error {
+	def, err := c.Def.Param(param)
+	if err != nil {
+		return err
+	}
+	resolved, err := def.Resolve(value)
+	if err != nil {
+		return fmt.Errorf("component %s: %w", c.ID, err)
+	}
+	return c.edit.set(c.ID, param, resolved)
+}
+
+// SetLoadShape stages shape as the component timeline and, when
+// targetParam is not empty, checks the peak of the shape against that
+// parameter and stages it too.
+func (c *Component) SetLoadShape(shape *models.LoadShape, targetParam string) error {
+	if _, err := shape.Timeline(); err != nil {
+		return fmt.Errorf("component %s: %w", c.ID, err)
+	}
+	if targetParam != "" {
+		if err := c.Set(targetParam, shape.Peak()); err != nil {
+			return err
+		}
+	}
+	return c.edit.SetLoadShape(c.ID, shape, "")
+}
+
+func (e *ModelEdit) typed(id, want string) (*Component, error) {
+	c, err := e.Component(id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Type != want {
+		return nil, fmt.Errorf("component %s is a %s, not a %s", id, c.Type, want)
+	}
+	return c, nil
+}
+
+// AppSim edits an Application Simulator component.
+type AppSim struct{ *Component }
+
+// AppSim returns an editor for an Application Simulator component.
+func (e *ModelEdit) AppSim(id string) (*AppSim, error) {
+	c, err := e.typed(id, ComponentAppSim)
+	if err != nil {
+		return nil, err
+	}
+	return &AppSim{c}, nil
+}
+
+// SetAppProfile selects the application profile by name.
+func (a *AppSim) SetAppProfile(name string) error {
+	return a.Set("profile", name)
+}
+
+// SetMaxSessions sets the maximum number of simultaneous sessions.
+func (a *AppSim) SetMaxSessions(n int) error {
+	return a.Set("sessions.max", n)
+}
+
+// SetMaxSessionRate sets the maximum number of sessions opened per second.
+func (a *AppSim) SetMaxSessionRate(perSecond int) error {
+	return a.Set("sessions.maxPerSecond", perSecond)
+}
+
+// SetRamp sets the ramp up, steady state and ramp down durations in
+// seconds.
+func (a *AppSim) SetRamp(up, steady, down int) error {
+	if err := a.Set("rampDist.up", up); err != nil {
+		return err
+	}
+	if err := a.Set("rampDist.steady", steady); err != nil {
+		return err
+	}
+	return a.Set("rampDist.down", down)
+}
+
+// SecurityNP edits a Security NP component.
+type SecurityNP struct{ *Component }
+
+// SecurityNP returns an editor for a Security NP component.
+func (e *ModelEdit) SecurityNP(id string) (*SecurityNP, error) {
+	c, err := e.typed(id, ComponentSecurityNP)
+	if err != nil {
+		return nil, err
+	}
+	return &SecurityNP{c}, nil
+}
+
+// SetStrikeList selects the strike list by name.
+func (s *SecurityNP) SetStrikeList(name string) error {
+	return s.Set("attackPlan", name)
+}
+
+// SetEvasionProfile selects the evasion profile by name.
+func (s *SecurityNP) SetEvasionProfile(name string) error {
+	return s.Set("attackProfile", name)
+}
+
+// SetIterations sets how many times the strike list is run.
+func (s *SecurityNP) SetIterations(n int) error {
+	return s.Set("attackPlanIterations", n)
+}
+
+// ClientSim edits a Client Simulator component.
+type ClientSim struct{ *Component }
+
+// ClientSim returns an editor for a Client Simulator component.
+func (e *ModelEdit) ClientSim(id string) (*ClientSim, error) {
+	c, err := e.typed(id, ComponentClientSim)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientSim{c}, nil
+}
+
+// SetSuperflow selects the superflow by name.
+func (c *ClientSim) SetSuperflow(name string) error {
+	return c.Set("superflow", name)
+}
+
+// SetMaxSessions sets the maximum number of simultaneous sessions.
+func (c *ClientSim) SetMaxSessions(n int) error {
+	return c.Set("sessions.max", n)
+}
+
+// RoutingRobot edits a Routing Robot component.
+type RoutingRobot struct{ *Component }
+
+// RoutingRobot returns an editor for a Routing Robot component.
+func (e *ModelEdit) RoutingRobot(id string) (*RoutingRobot, error) {
+	c, err := e.typed(id, ComponentRoutingRobot)
+	if err != nil {
+		return nil, err
+	}
+	return &RoutingRobot{c}, nil
+}
+
+// SetRate sets a constant data rate in the given unit, such as "mbps".
+func (r *RoutingRobot) SetRate(rate float64, unit string) error {
+	if err := r.Set("rateDist.unit", unit); err != nil {
+		return err
+	}
+	return r.Set("rateDist.min", rate)
+}
+
+// SetFrameSize sets a constant frame size in bytes.
+func (r *RoutingRobot) SetFrameSize(size int) error {
+	if err := r.Set("sizeDist.type", "constant"); err != nil {
+		return err
+	}
+	return r.Set("sizeDist.min", size)
+}
@@ -0,0 +1,132 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// decodeInto converts a loosely typed API response into dst by
+// round-tripping it through JSON.
+func decodeInto(src interface{}, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// listFrom extracts the result list from a search style response. The
+// API returns either a bare array or an object wrapping it under one of keys;
+// failing those, the first list found in key order is used.
+func listFrom(resp interface{}, keys ...string) ([]interface{}, error) {
+	switch v := resp.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		for _, k := range keys {
+			if items, ok := v[k].([]interface{}); ok {
+				return items, nil
+			}
+		}
+		for _, k := range sortedKeys(v) {
+			if items, ok := v[k].([]interface{}); ok {
+				return items, nil
+			}
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unexpected response type: %#v", resp)
+}
+
+// searchFunc is the signature of the generated Search operations.
+type searchFunc func(searchString string, limit int, sort string, sortorder string) (interface{}, error)
+
+// maxSearchLimit bounds the result lists findByName asks for.
+const maxSearchLimit = 12800
+
+// findByName reports whether search lists an object named exactly name.
+// Searches match substrings, so a common name can be crowded out of a
+// short result list; the limit is raised until the name shows up or the
+// results run out. keys are passed on to listFrom.
+func findByName(search searchFunc, name string, keys ...string) (bool, error) {
+	for limit := 50; ; limit *= 4 {
+		resp, err := search(name, limit, "name", "ascending")
+		if err != nil {
+			return false, fmt.Errorf("search %s: %w", name, err)
+		}
+		items, err := listFrom(resp, keys...)
+		if err != nil {
+			return false, fmt.Errorf("search %s: %w", name, err)
+		}
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok && toString(m["name"]) == name {
+				return true, nil
+			}
+		}
+		if len(items) < limit {
+			return false, nil
+		}
+		if limit >= maxSearchLimit {
+			return false, fmt.Errorf("search %s: more than %d matches without an exact one", name, limit)
+		}
+	}
+}
+
+// SearchItem is a saved configuration object as listed by the search
+// operations.
+type SearchItem struct {
+	Name        string
+	Label       string
+	Description string
+	Revision    int
+	CreatedBy   string
+}
+
+// SearchItems extracts the named objects from a search response.
+func SearchItems(resp interface{}) ([]SearchItem, error) {
+	items, err := listFrom(resp, "result", "items")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SearchItem, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		out = append(out, SearchItem{
+			Name:        toString(m["name"]),
+			Label:       toString(m["label"]),
+			Description: toString(m["description"]),
+			Revision:    toInt(m["revision"]),
+			CreatedBy:   toString(m["createdBy"]),
+		})
+	}
+	return out, nil
+}
+
+func toInt(v interface{}) int {
+	switch value := v.(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	case string:
+		i, _ := strconv.Atoi(value)
+		return i
+	}
+	return 0
+}
+
+func toString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,421 @@
+package operations
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"bps-client-go/pkg/models"
+)
+
+// Evasion option groups of the strike options catalog
+const (
+	EvasionGlobal   = "Global"
+	EvasionEthernet = "Ethernet"
+	EvasionIP       = "IP"
+	EvasionIPv6     = "IPv6"
+	EvasionTCP      = "TCP"
+	EvasionUDP      = "UDP"
+	EvasionHTTP     = "HTTP"
+	EvasionSMB      = "SMB"
+	EvasionSMB2     = "SMB2"
+	EvasionDCERPC   = "DCERPC"
+	EvasionFTP      = "FTP"
+	EvasionSMTP     = "SMTP"
+)
+
+// EvasionCatalog is the set of evasion options a profile can set, keyed
+// by "Group.Option", e.g. "IP.FragEvasion".
+type EvasionCatalog struct {
+	Options map[string]*ParamDef
+}
+
+// Catalog reads the evasion option catalog.
+func (e *EvasionProfileOps) Catalog() (*EvasionCatalog, error) {
+	resp, err := e.GetStrikeOptions()
+	if err != nil {
+		return nil, fmt.Errorf("read strike options: %w", err)
+	}
+	c := &EvasionCatalog{Options: make(map[string]*ParamDef)}
+	parseOptions(c.Options, "", resp)
+	if len(c.Options) == 0 {
+		return nil, fmt.Errorf("strike options catalog is empty")
+	}
+	return c, nil
+}
+
+// parseOptions flattens the catalog getStrikeOptions returns into dotted
+// option names. The reply lists the option groups, each with its options
+// and possibly nested groups:
+//
+//	{"result": [
+//		{"name": "IP", "options": [
+//			{"name": "FragEvasion", "type": "boolean", "default": false, ...},
+//			...
+//		]},
+//		{"name": "SMB", "options": [
+//			{"name": "Signing", "options": [{"name": "Enabled", ...}]},
+//			...
+//		]},
+//		...
+//	]}
+//
+// which gives IP.FragEvasion and SMB.Signing.Enabled. Some releases key
+// groups and options by name instead of listing them, e.g.
+// {"IP": {"FragEvasion": {...}}}; both forms are accepted. An object with
+// a type, default or choices is an option, any other object a group.
+func parseOptions(out map[string]*ParamDef, prefix string, v interface{}) {
+	switch value := v.(type) {
+	case []interface{}:
+		for _, item := range value {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := toString(m["name"])
+			if name == "" {
+				name = toString(m["id"])
+			}
+			if name != "" {
+				parseOptionNode(out, prefix+name, m)
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"options", "groups", "StrikeOptions", "result"} {
+			if children, ok := value[key]; ok {
+				parseOptions(out, prefix, children)
+				return
+			}
+		}
+		for name, item := range value {
+			if m, ok := item.(map[string]interface{}); ok {
+				parseOptionNode(out, prefix+name, m)
+			}
+		}
+	}
+}
+
+func parseOptionNode(out map[string]*ParamDef, name string, m map[string]interface{}) {
+	for _, key := range []string{"type", "default", "choice", "choices", "enum"} {
+		if _, ok := m[key]; ok {
+			parseParam(out, name, m)
+			return
+		}
+	}
+	if children, ok := m["options"]; ok {
+		parseOptions(out, name+".", children)
+		return
+	}
+	parseOptions(out, name+".", m)
+}
+
+// Option returns the definition of an option.
+func (c *EvasionCatalog) Option(name string) (*ParamDef, error) {
+	if d, ok := c.Options[name]; ok {
+		return d, nil
+	}
+	def := &ComponentDefinition{Type: "evasion profile", Params: c.Options}
+	return def.Param(name)
+}
+
+// Groups returns the sorted option group names.
+func (c *EvasionCatalog) Groups() []string {
+	seen := make(map[string]bool)
+	var groups []string
+	for name := range c.Options {
+		g := optionGroup(name)
+		if !seen[g] {
+			seen[g] = true
+			groups = append(groups, g)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// Group returns the options of group sorted by name.
+func (c *EvasionCatalog) Group(group string) []*ParamDef {
+	var defs []*ParamDef
+	for name, d := range c.Options {
+		if optionGroup(name) == group {
+			defs = append(defs, d)
+		}
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+func optionGroup(name string) string {
+	if i := strings.Index(name, "."); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// EvasionSettings is a typed evasion profile. Options not set keep their
+// catalog default.
+type EvasionSettings struct {
+	Name        string
+	Description string
+	Catalog     *EvasionCatalog
+	values      map[string]interface{}
+}
+
+// NewSettings returns an evasion profile with every option at its
+// default.
+func (c *EvasionCatalog) NewSettings(name string) *EvasionSettings {
+	return &EvasionSettings{Name: name, Catalog: c, values: make(map[string]interface{})}
+}
+
+// Set checks value against the catalog and sets option.
+func (s *EvasionSettings) Set(option string, value interface{}) error {
+	d, err := s.Catalog.Option(option)
+	if err != nil {
+		return err
+	}
+	v, err := d.Resolve(value)
+	if err != nil {
+		return err
+	}
+	s.values[option] = v
+	return nil
+}
+
+// Unset returns option to its catalog default, which is written back when
+// the profile is saved.
+func (s *EvasionSettings) Unset(option string) {
+	delete(s.values, option)
+}
+
+// Get returns the value of option, its default if it is not set.
+func (s *EvasionSettings) Get(option string) interface{} {
+	if v, ok := s.values[option]; ok {
+		return v
+	}
+	if d, ok := s.Catalog.Options[option]; ok {
+		return d.Default
+	}
+	return nil
+}
+
+// Changed lists the options set to something other than their default.
+func (s *EvasionSettings) Changed() []string {
+	var names []string
+	for name, v := range s.values {
+		if d, ok := s.Catalog.Options[name]; !ok || !sameValue(v, d.Default) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Group returns a setter for the options of group.
+func (s *EvasionSettings) Group(group string) *EvasionGroup {
+	return &EvasionGroup{Name: group, settings: s}
+}
+
+// IPFragmentation fragments IP packets into fragments of at most size
+// bytes; zero disables fragmentation.
+func (s *EvasionSettings) IPFragmentation(size int) error {
+	ip := s.Group(EvasionIP)
+	if size == 0 {
+		return ip.Set("FragEvasion", false)
+	}
+	if err := ip.Set("FragEvasion", true); err != nil {
+		return err
+	}
+	return ip.Set("MaxFragSize", size)
+}
+
+// TCPSegmentation splits TCP payloads into segments of at most size
+// bytes; zero restores the default segmentation.
+func (s *EvasionSettings) TCPSegmentation(size int) error {
+	if size == 0 {
+		s.Unset(EvasionTCP + ".MaxSegmentSize")
+		return nil
+	}
+	return s.Group(EvasionTCP).Set("MaxSegmentSize", size)
+}
+
+// HTTPChunked makes the server send chunked transfer encoded responses.
+func (s *EvasionSettings) HTTPChunked(enabled bool) error {
+	return s.Group(EvasionHTTP).Set("ServerChunkedTransfer", enabled)
+}
+
+// StrikeOptions returns the options set in the nested form of the
+// StrikeOptions field of a profile, Group -> Option -> value, with one
+// level per dot of the option name.
+func (s *EvasionSettings) StrikeOptions() map[string]interface{} {
+	return nestValues(s.values)
+}
+
+// effective returns the value of every option: the ones set and the
+// catalog default of the others.
+func (s *EvasionSettings) effective() map[string]interface{} {
+	out := make(map[string]interface{}, len(s.Catalog.Options)+len(s.values))
+	for name, d := range s.Catalog.Options {
+		if d.Default != nil {
+			out[name] = d.Default
+		}
+	}
+	for name, v := range s.values {
+		out[name] = v
+	}
+	return out
+}
+
+// nestValues is the inverse of flattenValues.
+func nestValues(values map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for name, v := range values {
+		parts := strings.Split(name, ".")
+		m := out
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := m[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				m[part] = child
+			}
+			m = child
+		}
+		m[parts[len(parts)-1]] = v
+	}
+	return out
+}
+
+// EvasionGroup sets the options of one group of an EvasionSettings.
+type EvasionGroup struct {
+	Name     string
+	settings *EvasionSettings
+}
+
+// Set checks value against the catalog and sets option of the group.
+func (g *EvasionGroup) Set(option string, value interface{}) error {
+	return g.settings.Set(g.Name+"."+option, value)
+}
+
+// Get returns the value of option of the group.
+func (g *EvasionGroup) Get(option string) interface{} {
+	return g.settings.Get(g.Name + "." + option)
+}
+
+// EvasionChange is an option whose value differs between two profiles.
+type EvasionChange struct {
+	Option string
+	From   interface{}
+	To     interface{}
+}
+
+func (c EvasionChange) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Option, c.From, c.To)
+}
+
+// Diff lists the options whose effective value differs from other, sorted
+// by option name.
+func (s *EvasionSettings) Diff(other *EvasionSettings) []EvasionChange {
+	names := make(map[string]interface{})
+	for name := range s.values {
+		names[name] = nil
+	}
+	for name := range other.values {
+		names[name] = nil
+	}
+	var changes []EvasionChange
+	for _, name := range sortedKeys(names) {
+		from, to := s.Get(name), other.Get(name)
+		if !sameValue(from, to) {
+			changes = append(changes, EvasionChange{Option: name, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+func sameValue(a, b interface{}) bool {
+	if fa, ok := number(a); ok {
+		if fb, ok := number(b); ok {
+			return fa == fb
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Open loads the evasion profile name and reads its options. Options the
+// catalog does not know are kept unchecked.
+func (e *EvasionProfileOps) Open(name string, catalog *EvasionCatalog) (*EvasionSettings, error) {
+	if _, err := e.Load(name); err != nil {
+		return nil, fmt.Errorf("load evasion profile %s: %w", name, err)
+	}
+	depth := models.FullDepth
+	resp, err := e.Client.Get("/evasionProfile", &depth, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read evasion profile: %w", err)
+	}
+	p := &models.EvasionProfile{}
+	if err := decodeInto(resp, p); err != nil {
+		return nil, fmt.Errorf("decode evasion profile: %w", err)
+	}
+	s := catalog.NewSettings(name)
+	s.Description = p.Description
+	flattenValues(s.values, "", p.StrikeOptions)
+	return s, nil
+}
+
+func flattenValues(out map[string]interface{}, prefix string, v interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		if prefix != "" {
+			out[strings.TrimSuffix(prefix, ".")] = v
+		}
+		return
+	}
+	for k, child := range m {
+		flattenValues(out, prefix+k+".", child)
+	}
+}
+
+// Create saves s as a new evasion profile named s.Name.
+func (e *EvasionProfileOps) Create(s *EvasionSettings, force bool) error {
+	if _, err := e.New(nil); err != nil {
+		return fmt.Errorf("new evasion profile: %w", err)
+	}
+	return e.write(s.Name, s, force)
+}
+
+// Modify opens the evasion profile name against catalog, lets edit change
+// its options and writes all of them back, unset ones at their catalog
+// default. The profile is saved as saveAs, replacing an existing one only
+// with force, or over name itself when saveAs is empty.
+func (e *EvasionProfileOps) Modify(name, saveAs string, catalog *EvasionCatalog, edit func(*EvasionSettings) error, force bool) (*EvasionSettings, error) {
+	s, err := e.Open(name, catalog)
+	if err != nil {
+		return nil, err
+	}
+	if err := edit(s); err != nil {
+		return nil, err
+	}
+	if saveAs == "" {
+		saveAs = name
+		force = true
+	}
+	s.Name = saveAs
+	return s, e.write(saveAs, s, force)
+}
+
+// write saves s as name. Options that are not set are written with their
+// catalog default so that options unset since the profile was loaded are
+// reset.
+func (e *EvasionProfileOps) write(name string, s *EvasionSettings, force bool) error {
+	patch := map[string]interface{}{
+		"StrikeOptions": nestValues(s.effective()),
+		"description":   s.Description,
+	}
+	if err := e.Client.Patch("/evasionProfile", patch); err != nil {
+		return fmt.Errorf("write evasion profile: %w", err)
+	}
+	if _, err := e.SaveAs(name, force); err != nil {
+		return fmt.Errorf("save evasion profile %s: %w", name, err)
+	}
+	return nil
+}
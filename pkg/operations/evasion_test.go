@@ -0,0 +1,212 @@
+package operations
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// listedOptions is the catalog in the form that lists groups and options
+var listedOptions = map[string]interface{}{"result": []interface{}{
+	map[string]interface{}{"name": "IP", "options": []interface{}{
+		map[string]interface{}{"name": "FragEvasion", "type": "boolean", "default": false},
+		map[string]interface{}{"name": "MaxFragSize", "type": "integer", "default": 1480.0, "min": 8.0, "max": 1480.0},
+		map[string]interface{}{"name": "FragOrder", "type": "string", "default": "normal", "choices": []interface{}{
+			map[string]interface{}{"value": "normal", "label": "Normal"},
+			map[string]interface{}{"value": "reverse", "label": "Reverse"},
+		}},
+	}},
+	map[string]interface{}{"name": "SMB", "options": []interface{}{
+		map[string]interface{}{"name": "Signing", "options": []interface{}{
+			map[string]interface{}{"name": "Enabled", "type": "boolean", "default": false},
+		}},
+	}},
+	map[string]interface{}{"name": "TCP", "options": []interface{}{
+		map[string]interface{}{"name": "MaxSegmentSize", "type": "integer", "default": 0.0},
+	}},
+}}
+
+// keyedOptions is the same catalog keyed by name
+var keyedOptions = map[string]interface{}{
+	"IP": map[string]interface{}{
+		"FragEvasion": map[string]interface{}{"type": "boolean", "default": false},
+		"MaxFragSize": map[string]interface{}{"type": "integer", "default": 1480.0, "min": 8.0, "max": 1480.0},
+		"FragOrder":   map[string]interface{}{"default": "normal", "enum": []interface{}{"normal", "reverse"}},
+	},
+	"SMB": map[string]interface{}{
+		"Signing": map[string]interface{}{
+			"Enabled": map[string]interface{}{"type": "boolean", "default": false},
+		},
+	},
+	"TCP": map[string]interface{}{
+		"MaxSegmentSize": map[string]interface{}{"type": "integer", "default": 0.0},
+	},
+}
+
+func catalogFrom(t *testing.T, options interface{}) *EvasionCatalog {
+	t.Helper()
+	c := &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+		return options, nil
+	}}
+	catalog, err := (&EvasionProfileOps{Client: c}).Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return catalog
+}
+
+func TestEvasionCatalog(t *testing.T) {
+	for name, options := range map[string]interface{}{"listed": listedOptions, "keyed": keyedOptions} {
+		t.Run(name, func(t *testing.T) {
+			catalog := catalogFrom(t, options)
+			want := []string{"IP.FragEvasion", "IP.FragOrder", "IP.MaxFragSize", "SMB.Signing.Enabled", "TCP.MaxSegmentSize"}
+			var got []string
+			for name := range catalog.Options {
+				got = append(got, name)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("options = %v, want %v", got, want)
+			}
+			if got := catalog.Groups(); !reflect.DeepEqual(got, []string{"IP", "SMB", "TCP"}) {
+				t.Errorf("Groups() = %v", got)
+			}
+			if got := len(catalog.Group("IP")); got != 3 {
+				t.Errorf("Group(IP) has %d options", got)
+			}
+			if d, _ := catalog.Option("IP.MaxFragSize"); d == nil || !d.Integer() || *d.Max != 1480 {
+				t.Errorf("IP.MaxFragSize = %+v", d)
+			}
+			if _, err := catalog.Option("IP.FragEvas"); err == nil || !strings.Contains(err.Error(), `did you mean "IP.FragEvasion"`) {
+				t.Errorf("Option(IP.FragEvas) error = %v", err)
+			}
+		})
+	}
+	c := &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+		return map[string]interface{}{"result": []interface{}{}}, nil
+	}}
+	if _, err := (&EvasionProfileOps{Client: c}).Catalog(); err == nil {
+		t.Errorf("Catalog() accepted an empty catalog")
+	}
+}
+
+func TestNestValues(t *testing.T) {
+	flat := map[string]interface{}{
+		"IP.FragEvasion":      true,
+		"IP.MaxFragSize":      int64(8),
+		"SMB.Signing.Enabled": true,
+	}
+	nested := nestValues(flat)
+	want := map[string]interface{}{
+		"IP":  map[string]interface{}{"FragEvasion": true, "MaxFragSize": int64(8)},
+		"SMB": map[string]interface{}{"Signing": map[string]interface{}{"Enabled": true}},
+	}
+	if !reflect.DeepEqual(nested, want) {
+		t.Errorf("nestValues() = %v, want %v", nested, want)
+	}
+	back := make(map[string]interface{})
+	flattenValues(back, "", nested)
+	if !reflect.DeepEqual(back, flat) {
+		t.Errorf("flattenValues() = %v, want %v", back, flat)
+	}
+}
+
+func TestEvasionSettings(t *testing.T) {
+	catalog := catalogFrom(t, listedOptions)
+	s := catalog.NewSettings("frag")
+	if err := s.IPFragmentation(8); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("IP.FragOrder", "Reverse"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.IPFragmentation(4); err == nil {
+		t.Errorf("IPFragmentation(4) accepted a size below the minimum")
+	}
+	if err := s.TCPSegmentation(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Changed(); !reflect.DeepEqual(got, []string{"IP.FragEvasion", "IP.FragOrder", "IP.MaxFragSize"}) {
+		t.Errorf("Changed() = %v", got)
+	}
+	if got := s.Get("IP.FragOrder"); got != "reverse" {
+		t.Errorf("Get(IP.FragOrder) = %v", got)
+	}
+	if got := s.Get("SMB.Signing.Enabled"); got != false {
+		t.Errorf("default SMB.Signing.Enabled = %v", got)
+	}
+
+	other := catalog.NewSettings("other")
+	other.Set("IP.FragEvasion", true)
+	other.Set("IP.MaxFragSize", 1480)
+	var diff []string
+	for _, c := range s.Diff(other) {
+		diff = append(diff, c.String())
+	}
+	if want := []string{"IP.FragOrder: reverse -> normal", "IP.MaxFragSize: 8 -> 1480"}; !reflect.DeepEqual(diff, want) {
+		t.Errorf("Diff() = %q, want %q", diff, want)
+	}
+}
+
+func TestEvasionModify(t *testing.T) {
+	catalog := catalogFrom(t, listedOptions)
+	var patch map[string]interface{}
+	c := &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+		switch {
+		case method == "GET" && path == "/evasionProfile":
+			return map[string]interface{}{
+				"name":        "frag",
+				"description": "fragments",
+				"StrikeOptions": map[string]interface{}{
+					"IP":      map[string]interface{}{"FragEvasion": true, "MaxFragSize": 8.0},
+					"Unknown": map[string]interface{}{"Kept": "yes"},
+				},
+			}, nil
+		case method == "PATCH":
+			patch = body.(map[string]interface{})
+		}
+		return nil, nil
+	}}
+	s, err := (&EvasionProfileOps{Client: c}).Modify("frag", "", catalog, func(s *EvasionSettings) error {
+		s.Unset("IP.MaxFragSize")
+		return s.HTTPChunked(true)
+	}, false)
+	if err == nil || !strings.Contains(err.Error(), `no parameter "HTTP.ServerChunkedTransfer"`) || s != nil {
+		t.Fatalf("Modify() with an unknown option = %v, %v", s, err)
+	}
+	if patch != nil {
+		t.Fatalf("failed edit was written: %v", patch)
+	}
+
+	c.calls = nil
+	s, err = (&EvasionProfileOps{Client: c}).Modify("frag", "", catalog, func(s *EvasionSettings) error {
+		s.Unset("IP.MaxFragSize")
+		return s.Set("SMB.Signing.Enabled", true)
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"description": "fragments",
+		"StrikeOptions": map[string]interface{}{
+			"IP":      map[string]interface{}{"FragEvasion": true, "MaxFragSize": 1480.0, "FragOrder": "normal"},
+			"SMB":     map[string]interface{}{"Signing": map[string]interface{}{"Enabled": true}},
+			"TCP":     map[string]interface{}{"MaxSegmentSize": 0.0},
+			"Unknown": map[string]interface{}{"Kept": "yes"},
+		},
+	}
+	if !reflect.DeepEqual(patch, want) {
+		t.Errorf("patch = %v, want %v", patch, want)
+	}
+	if got := c.writes(); !reflect.DeepEqual(got, []string{
+		"POST /evasionProfile/operations/load",
+		"PATCH /evasionProfile",
+		"POST /evasionProfile/operations/saveAs",
+	}) {
+		t.Errorf("writes = %v", got)
+	}
+	if s.Name != "frag" {
+		t.Errorf("Name = %s", s.Name)
+	}
+}
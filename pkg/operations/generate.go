@@ -0,0 +1,3 @@
+package operations
+
+//go:generate go run ../../cmd/bpsgen -spec ../../api/bps-api.json -ops zz_generated_ops.go -models ../models/zz_generated_datamodel.go
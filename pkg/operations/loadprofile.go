@@ -1,59 +1,120 @@
 package operations
 
-type LoadProfileOps struct {
-	Client ClientWrapper
-}
+import (
+	"fmt"
 
-func (l *LoadProfileOps) CreateNew(loadProfile string) (interface{}, error) {
-	params := map[string]interface{}{
-		"loadProfile": loadProfile,
-	}
-	return l.Client.Post("/loadprofile/operations/createNew", params)
-}
+	"bps-client-go/pkg/models"
+)
 
-func (l *LoadProfileOps) Delete(name string) (interface{}, error) {
-	params := map[string]interface{}{
-		"name": name,
+// WorkingProfile reads the working load profile.
+func (l *LoadProfileOps) WorkingProfile() (*models.LoadProfile, error) {
+	depth := models.FullDepth
+	resp, err := l.Client.Get("/loadProfile", &depth, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read load profile: %w", err)
 	}
-	return l.Client.Post("/loadprofile/operations/delete", params)
+	p := &models.LoadProfile{}
+	if err := decodeInto(resp, p); err != nil {
+		return nil, fmt.Errorf("decode load profile: %w", err)
+	}
+	return p, nil
 }
 
-func (l *LoadProfileOps) Load(template string) (interface{}, error) {
-	params := map[string]interface{}{
-		"template": template,
+// Create validates p and saves it as a new load profile named p.Name.
+func (l *LoadProfileOps) Create(p *models.LoadProfile) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("load profile %s: %w", p.Name, err)
+	}
+	if _, err := l.CreateNew(p.Name); err != nil {
+		return fmt.Errorf("new load profile %s: %w", p.Name, err)
 	}
-	return l.Client.Post("/loadprofile/operations/load", params)
+	if err := l.write(p); err != nil {
+		return err
+	}
+	if _, err := l.Save(); err != nil {
+		return fmt.Errorf("save load profile %s: %w", p.Name, err)
+	}
+	return nil
 }
 
-func (l *LoadProfileOps) Save() (interface{}, error) {
-	params := map[string]interface{}{}
-	return l.Client.Post("/loadprofile/operations/save", params)
+// Modify lets edit change the phases of the saved load profile name and
+// writes the validated phases and description back, over name itself or
+// as the new profile saveAs. Nothing is written when edit or the
+// validation fails.
+func (l *LoadProfileOps) Modify(name, saveAs string, edit func(*models.LoadProfile) error) (*models.LoadProfile, error) {
+	if _, err := l.Load(name); err != nil {
+		return nil, fmt.Errorf("load load profile %s: %w", name, err)
+	}
+	p, err := l.WorkingProfile()
+	if err != nil {
+		return nil, err
+	}
+	if err := edit(p); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("load profile %s: %w", name, err)
+	}
+	if err := l.write(p); err != nil {
+		return nil, err
+	}
+	if saveAs == "" || saveAs == name {
+		if _, err := l.Save(); err != nil {
+			return nil, fmt.Errorf("save load profile %s: %w", name, err)
+		}
+		return p, nil
+	}
+	if _, err := l.SaveAs(saveAs); err != nil {
+		return nil, fmt.Errorf("save load profile %s: %w", saveAs, err)
+	}
+	p.Name = saveAs
+	return p, nil
 }
 
-func (l *LoadProfileOps) SaveAs(name string) (interface{}, error) {
-	params := map[string]interface{}{
-		"name": name,
+// CheckSpeed validates the phases of p against the speed of the slowest
+// port of group.
+func (l *LoadProfileOps) CheckSpeed(p *models.LoadProfile, group int) error {
+	speed, err := (&TopologyOps{Client: l.Client}).GroupPortSpeed(group)
+	if err != nil {
+		return err
+	}
+	if err := p.ValidateSpeed(float64(speed)); err != nil {
+		return fmt.Errorf("load profile %s on group %d: %w", p.Name, group, err)
 	}
-	return l.Client.Post("/loadprofile/operations/saveAs", params)
+	return nil
 }
 
-func (l *LoadProfileOps) Search(searchString, limit, sort, sortorder string) (interface{}, error) {
-	params := map[string]interface{}{
-		"searchString": searchString,
-		"limit":        limit,
-		"sort":         sort,
-		"sortorder":    sortorder,
+func (l *LoadProfileOps) write(p *models.LoadProfile) error {
+	patch := map[string]interface{}{"phase": p.Phase, "description": p.Description}
+	if err := l.Client.Patch("/loadProfile", patch); err != nil {
+		return fmt.Errorf("write load profile %s: %w", p.Name, err)
 	}
-	return l.Client.Post("/loadprofile/operations/search", params)
+	return nil
 }
 
-func (l *LoadProfileOps) SearchDynamic(searchString string, limit string, sort string, sortorder string, offset string) (interface{}, error) {
-	params := map[string]interface{}{
-		"searchString": searchString,
-		"limit":        limit,
-		"sort":         sort,
-		"sortorder":    sortorder,
-		"offset":       offset,
+// GroupPortSpeed returns the speed, in Mbps, of the slowest port reserved
+// in group. Every port of the group carries the rate of a phase on its
+// own, so it is the slowest one that bounds the rate, not their sum.
+func (t *TopologyOps) GroupPortSpeed(group int) (int, error) {
+	depth := models.FullDepth
+	resp, err := t.Client.Get("/topology", &depth, nil)
+	if err != nil {
+		return 0, fmt.Errorf("read topology: %w", err)
 	}
-	return l.Client.Post("/loadprofile/operations/searchDynamic", params)
-}
\ No newline at end of file
+	topo := &models.TopologyInfo{}
+	if err := decodeInto(resp, topo); err != nil {
+		return 0, fmt.Errorf("decode topology: %w", err)
+	}
+	speed := 0
+	for _, slot := range topo.Slots {
+		for _, port := range slot.Ports {
+			if port.Group == group && port.Speed > 0 && (speed == 0 || port.Speed < speed) {
+				speed = port.Speed
+			}
+		}
+	}
+	if speed == 0 {
+		return 0, fmt.Errorf("no ports with a known speed in group %d", group)
+	}
+	return speed, nil
+}
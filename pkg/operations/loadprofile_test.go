@@ -0,0 +1,82 @@
+package operations
+
+import (
+	"strings"
+	"testing"
+
+	"bps-client-go/pkg/models"
+)
+
+func topologyStub(ports ...map[string]interface{}) *stubClient {
+	items := make([]interface{}, len(ports))
+	for i, p := range ports {
+		items[i] = p
+	}
+	return &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+		return map[string]interface{}{"slot": []interface{}{
+			map[string]interface{}{"id": 1, "port": items},
+		}}, nil
+	}}
+}
+
+func TestGroupPortSpeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		ports   []map[string]interface{}
+		want    int
+		wantErr string
+	}{
+		{
+			name: "slowest port of the group",
+			ports: []map[string]interface{}{
+				{"id": "1", "group": 1, "speed": 10000},
+				{"id": "2", "group": 1, "speed": 1000},
+				{"id": "3", "group": 2, "speed": 100},
+				{"id": "4", "group": 1, "speed": 0},
+			},
+			want: 1000,
+		},
+		{
+			name:    "no ports in the group",
+			ports:   []map[string]interface{}{{"id": "1", "group": 2, "speed": 1000}},
+			wantErr: "no ports with a known speed in group 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := topologyStub(tt.ports...)
+			got, err := (&TopologyOps{Client: c}).GroupPortSpeed(1)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("GroupPortSpeed() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GroupPortSpeed() = %d, want %d", got, tt.want)
+			}
+			if len(c.calls) != 1 || c.calls[0] != "GET /topology" {
+				t.Errorf("calls = %v", c.calls)
+			}
+		})
+	}
+}
+
+func TestCheckSpeed(t *testing.T) {
+	c := topologyStub(
+		map[string]interface{}{"id": "1", "group": 1, "speed": 10000},
+		map[string]interface{}{"id": "2", "group": 1, "speed": 1000},
+	)
+	ops := &LoadProfileOps{Client: c}
+	p := models.NewLoadProfile("fast").Steady(0, 0, 2, "Gbps")
+	err := ops.CheckSpeed(p, 1)
+	if err == nil || !strings.Contains(err.Error(), "load profile fast on group 1: phase 1: rate 2000 Mbps exceeds port speed 1000 Mbps") {
+		t.Errorf("CheckSpeed() error = %v", err)
+	}
+	if err := ops.CheckSpeed(models.NewLoadProfile("slow").Steady(0, 0, 1, "Gbps"), 1); err != nil {
+		t.Errorf("CheckSpeed() = %v", err)
+	}
+}
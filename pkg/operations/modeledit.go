@@ -0,0 +1,248 @@
+package operations
+
+import (
+	"fmt"
+	"strings"
+
+	"bps-client-go/pkg/models"
+)
+
+// ModelEdit collects changes to the components of the working test model
+// and applies them in the order they were made followed by a single save.
+// Consecutive changes to one component are sent as one PATCH. If any step
+// fails the model is reloaded, discarding the applied changes.
+type ModelEdit struct {
+	ops    *TestModelOps
+	name   string
+	saveAs string
+	group  string
+	steps  []editStep
+	err    error
+	defs   map[string]*ComponentDefinition
+	types  map[string]string
+}
+
+// editStep is one PATCH of a component
+type editStep struct {
+	component string
+	patch     map[string]interface{}
+}
+
+// Edit starts a transaction on the test model name, which must be the
+// model currently loaded.
+func (t *TestModelOps) Edit(name string) *ModelEdit {
+	return &ModelEdit{
+		ops:   t,
+		name:  name,
+		defs:  make(map[string]*ComponentDefinition),
+		types: make(map[string]string),
+	}
+}
+
+// SaveAs makes Commit save the model as name instead of over the model
+// being edited, which stays untouched and is what a failed commit reloads.
+func (e *ModelEdit) SaveAs(name string) *ModelEdit {
+	e.saveAs = name
+	return e
+}
+
+// ValidateFor makes Commit also validate the model against the port group
+// it will run on before saving.
+func (e *ModelEdit) ValidateFor(group string) *ModelEdit {
+	e.group = group
+	return e
+}
+
+// SetActive enables or disables a component.
+func (e *ModelEdit) SetActive(componentID string, active bool) *ModelEdit {
+	return e.SetParameter(componentID, "active", active)
+}
+
+// SetLabel renames a component.
+func (e *ModelEdit) SetLabel(componentID, label string) *ModelEdit {
+	return e.SetParameter(componentID, "label", label)
+}
+
+// SetTimeline replaces the timeline segments of a component.
+func (e *ModelEdit) SetTimeline(componentID string, segments []models.TimeSegment) *ModelEdit {
+	return e.SetParameter(componentID, "timeline", models.Timeline{TimeSegments: segments})
+}
+
+// SetLoadShape replaces the timeline of a component with shape. When
+// targetParam is not empty, the target of the shape is written to it as
+// well, e.g. "sessions.max" for an Application Simulator. Shapes with more
+// than one load level are rejected, see models.LoadShape.Timeline.
+func (e *ModelEdit) SetLoadShape(componentID string, shape *models.LoadShape, targetParam string) error {
+	timeline, err := shape.Timeline()
+	if err != nil {
+		return fmt.Errorf("component %s: %w", componentID, err)
+	}
+	e.SetParameter(componentID, "timeline", timeline)
+	if targetParam != "" {
+		e.SetParameter(componentID, targetParam, shape.Peak())
+	}
+	return nil
+}
+
+// SetParameter sets a component parameter. Nested parameters are given as
+// a dotted path such as "rampDist.upBehavior". A path that would replace a
+// pending value with an object, or an object with a value, is an error
+// returned by Err and Commit.
+func (e *ModelEdit) SetParameter(componentID, param string, value interface{}) *ModelEdit {
+	if err := e.set(componentID, param, value); err != nil && e.err == nil {
+		e.err = err
+	}
+	return e
+}
+
+func (e *ModelEdit) set(componentID, param string, value interface{}) error {
+	if n := len(e.steps); n == 0 || e.steps[n-1].component != componentID {
+		e.steps = append(e.steps, editStep{component: componentID, patch: make(map[string]interface{})})
+	}
+	patch := e.steps[len(e.steps)-1].patch
+	keys := strings.Split(param, ".")
+	for i, k := range keys[:len(keys)-1] {
+		child, exists := patch[k]
+		next, ok := child.(map[string]interface{})
+		if exists && !ok {
+			return fmt.Errorf("component %s: %s is already set to %v", componentID, strings.Join(keys[:i+1], "."), child)
+		}
+		if !exists {
+			next = make(map[string]interface{})
+			patch[k] = next
+		}
+		patch = next
+	}
+	last := keys[len(keys)-1]
+	if old, exists := patch[last]; exists {
+		_, wasObject := old.(map[string]interface{})
+		_, isObject := value.(map[string]interface{})
+		if wasObject != isObject {
+			return fmt.Errorf("component %s: %s is already set to %v", componentID, param, old)
+		}
+	}
+	patch[last] = value
+	return nil
+}
+
+// Err returns the first error recorded by SetParameter.
+func (e *ModelEdit) Err() error {
+	return e.err
+}
+
+// Len returns the number of components with pending changes.
+func (e *ModelEdit) Len() int {
+	seen := make(map[string]bool)
+	for _, step := range e.steps {
+		seen[step.component] = true
+	}
+	return len(seen)
+}
+
+// Commit checks the pending changes against the component definitions,
+// applies them in the order they were made, validates the model when a
+// group was given with ValidateFor and saves it once. Nothing is sent when
+// there are no changes, SetParameter recorded an error or a value does not
+// match its definition.
+func (e *ModelEdit) Commit() error {
+	if err := e.err; err != nil {
+		e.Reset()
+		return err
+	}
+	if len(e.steps) == 0 {
+		return nil
+	}
+	for _, step := range e.steps {
+		def, err := e.definition(step.component)
+		if err == nil {
+			err = def.check("", step.patch)
+		}
+		if err != nil {
+			e.Reset()
+			return fmt.Errorf("component %s: %w", step.component, err)
+		}
+	}
+	for _, step := range e.steps {
+		if err := e.ops.Client.Patch("/testmodel/component/"+step.component, step.patch); err != nil {
+			return e.rollback(fmt.Errorf("update component %s: %w", step.component, err))
+		}
+	}
+	if e.group != "" {
+		resp, err := e.ops.Validate(e.group)
+		if err == nil {
+			err = validationError(resp)
+		}
+		if err != nil {
+			return e.rollback(fmt.Errorf("validate test model %s: %w", e.name, err))
+		}
+	}
+	name := e.name
+	if e.saveAs != "" {
+		name = e.saveAs
+	}
+	if _, err := e.ops.Save(name, true); err != nil {
+		return e.rollback(fmt.Errorf("save test model %s: %w", name, err))
+	}
+	e.Reset()
+	return nil
+}
+
+// Reset discards the pending changes without touching the model.
+func (e *ModelEdit) Reset() {
+	e.steps = nil
+	e.err = nil
+}
+
+func (e *ModelEdit) rollback(cause error) error {
+	e.Reset()
+	if _, err := e.ops.Load(e.name, false); err != nil {
+		return fmt.Errorf("%w (rollback failed: %v)", cause, err)
+	}
+	return cause
+}
+
+// validationError turns the messages of a failed validation into an error.
+// Warnings and informational messages do not fail the validation.
+func validationError(resp interface{}) error {
+	m, ok := resp.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if valid, ok := m["valid"].(bool); ok && valid {
+		return nil
+	}
+	var msgs []string
+	for _, key := range []string{"errors", "messages", "failures"} {
+		items, _ := m[key].([]interface{})
+		for _, item := range items {
+			if im, ok := item.(map[string]interface{}); ok {
+				if !isFailure(im) {
+					continue
+				}
+				if im["message"] != nil {
+					item = im["message"]
+				}
+			}
+			msgs = append(msgs, fmt.Sprintf("%v", item))
+		}
+	}
+	if valid, ok := m["valid"].(bool); ok && !valid && len(msgs) == 0 {
+		msgs = append(msgs, "model is not valid")
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(msgs, "; "))
+}
+
+// isFailure reports whether a validation message is an error rather than
+// a warning or a note, judged by its level, severity or type
+func isFailure(msg map[string]interface{}) bool {
+	for _, key := range []string{"level", "severity", "type"} {
+		level := strings.ToLower(toString(msg[key]))
+		if strings.HasPrefix(level, "warn") || strings.HasPrefix(level, "info") || level == "notice" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package operations
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bps-client-go/pkg/models"
+)
+
+// modelStub serves a model with an appsim_1 and a security_1 component,
+// fails PATCHes of the components in failing and records loads and saves
+func modelStub(failing string, loads, saves *[]string, patches map[string]interface{}) *stubClient {
+	return &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+		switch {
+		case method == "GET" && strings.HasPrefix(path, "/testmodel/component/"):
+			id := strings.TrimPrefix(path, "/testmodel/component/")
+			return map[string]interface{}{"id": id, "type": strings.TrimSuffix(id, "_1")}, nil
+		case path == "/testmodel/operations/testComponentDefinition":
+			if body.(map[string]interface{})["name"] == "security" {
+				return map[string]interface{}{"parameters": []interface{}{
+					map[string]interface{}{"id": "attackPlan", "type": "string"},
+				}}, nil
+			}
+			return map[string]interface{}{"parameters": []interface{}{
+				map[string]interface{}{"id": "active", "type": "boolean"},
+				map[string]interface{}{"id": "profile", "type": "string", "choice": []interface{}{
+					map[string]interface{}{"value": "web_1", "label": "Web"},
+				}},
+				map[string]interface{}{"id": "sessions", "parameters": []interface{}{
+					map[string]interface{}{"id": "max", "type": "integer", "min": 1},
+				}},
+				map[string]interface{}{"id": "timeline", "parameters": []interface{}{
+					map[string]interface{}{"id": "timesegment", "type": "list"},
+				}},
+			}}, nil
+		case method == "PATCH":
+			id := strings.TrimPrefix(path, "/testmodel/component/")
+			if id == failing {
+				return nil, errors.New("PATCH failed: 400")
+			}
+			patches[id] = body
+		case path == "/testmodel/operations/load":
+			*loads = append(*loads, body.(map[string]interface{})["template"].(string))
+		case path == "/testmodel/operations/save":
+			*saves = append(*saves, body.(map[string]interface{})["name"].(string))
+		}
+		return nil, nil
+	}}
+}
+
+func TestModelEditCommit(t *testing.T) {
+	shape := models.NewLoadShape("sessions").RampUp(10*time.Second, 100)
+	tests := []struct {
+		name    string
+		edit    func(e *ModelEdit)
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "values resolved",
+			edit: func(e *ModelEdit) {
+				e.SetParameter("appsim_1", "profile", "web").SetParameter("appsim_1", "sessions.max", 10.0)
+			},
+			want: map[string]interface{}{"appsim_1": map[string]interface{}{
+				"profile":  "web_1",
+				"sessions": map[string]interface{}{"max": int64(10)},
+			}},
+		},
+		{
+			name: "group value",
+			edit: func(e *ModelEdit) {
+				if err := e.SetLoadShape("appsim_1", shape, ""); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: map[string]interface{}{"appsim_1": map[string]interface{}{"timeline": mustTimeline(t, shape)}},
+		},
+		{
+			name:    "unknown parameter",
+			edit:    func(e *ModelEdit) { e.SetParameter("appsim_1", "sesions.max", 10) },
+			wantErr: `component appsim_1: appsim has no parameter "sesions.max"`,
+		},
+		{
+			name:    "every component checked",
+			edit:    func(e *ModelEdit) { e.SetActive("security_1", true).SetParameter("appsim_1", "sessions.max", 0) },
+			wantErr: "component security_1: security has no parameter \"active\"",
+		},
+		{
+			name:    "below minimum",
+			edit:    func(e *ModelEdit) { e.SetParameter("appsim_1", "sessions.max", 0) },
+			wantErr: "parameter sessions.max: 0 is below minimum 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loads, saves []string
+			patches := make(map[string]interface{})
+			c := modelStub("", &loads, &saves, patches)
+			e := (&TestModelOps{Client: c}).Edit("base")
+			tt.edit(e)
+			err := e.Commit()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Commit() error = %v, want %q", err, tt.wantErr)
+				}
+				if w := c.writes(); len(w) != 1 || !strings.HasSuffix(w[0], "testComponentDefinition") {
+					t.Errorf("writes = %v, want none", w)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(patches, tt.want) {
+				t.Errorf("patches = %#v, want %#v", patches, tt.want)
+			}
+			if !reflect.DeepEqual(saves, []string{"base"}) {
+				t.Errorf("saves = %v", saves)
+			}
+		})
+	}
+}
+
+func mustTimeline(t *testing.T, shape *models.LoadShape) interface{} {
+	timeline, err := shape.Timeline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return timeline
+}
+
+func TestModelEditRollback(t *testing.T) {
+	var loads, saves []string
+	c := modelStub("appsim_1", &loads, &saves, make(map[string]interface{}))
+	tm := &TestModelOps{Client: c}
+	err := tm.Edit("base").SaveAs("base_throughput").SetActive("appsim_1", false).Commit()
+	if err == nil || !strings.Contains(err.Error(), "update component appsim_1") {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if !reflect.DeepEqual(loads, []string{"base"}) || saves != nil {
+		t.Errorf("loads = %v, saves = %v, want the edited model reloaded and nothing saved", loads, saves)
+	}
+
+	loads = nil
+	c = modelStub("", &loads, &saves, make(map[string]interface{}))
+	tm = &TestModelOps{Client: c}
+	if err := tm.Edit("base").SaveAs("base_throughput").SetActive("appsim_1", false).Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if loads != nil || !reflect.DeepEqual(saves, []string{"base_throughput"}) {
+		t.Errorf("loads = %v, saves = %v, want a save as base_throughput", loads, saves)
+	}
+}
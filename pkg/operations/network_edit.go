@@ -0,0 +1,70 @@
+package operations
+
+import (
+	"fmt"
+
+	"bps-client-go/pkg/models"
+)
+
+// WorkingNetwork reads the network model of the working network
+// neighborhood.
+func (n *NetworkOps) WorkingNetwork() (*models.NetworkModel, error) {
+	depth := models.FullDepth
+	resp, err := n.Client.Get("/network/networkModel", &depth, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read network model: %w", err)
+	}
+	m := &models.NetworkModel{}
+	if err := decodeInto(resp, m); err != nil {
+		return nil, fmt.Errorf("decode network model: %w", err)
+	}
+	return m, nil
+}
+
+// Create validates model and saves it as a new network neighborhood
+// called name, optionally based on template.
+func (n *NetworkOps) Create(name string, template *string, model *models.NetworkModel, force bool) error {
+	if err := model.Validate(); err != nil {
+		return fmt.Errorf("network %s: %w", name, err)
+	}
+	if _, err := n.New(template); err != nil {
+		return fmt.Errorf("new network: %w", err)
+	}
+	return n.write(name, model, force)
+}
+
+// Modify lets edit change the elements of the network neighborhood name
+// and replaces its whole network model with the result once it passes
+// NetworkModel.Validate. The neighborhood is saved as saveAs, overwriting
+// an existing one only with force, or over name itself when saveAs is
+// empty. Nothing is written when edit or the validation fails.
+func (n *NetworkOps) Modify(name, saveAs string, edit func(*models.NetworkModel) error, force bool) (*models.NetworkModel, error) {
+	if _, err := n.Load(name); err != nil {
+		return nil, fmt.Errorf("load network %s: %w", name, err)
+	}
+	model, err := n.WorkingNetwork()
+	if err != nil {
+		return nil, err
+	}
+	if err := edit(model); err != nil {
+		return nil, err
+	}
+	if err := model.Validate(); err != nil {
+		return nil, fmt.Errorf("network %s: %w", name, err)
+	}
+	if saveAs == "" {
+		saveAs = name
+		force = true
+	}
+	return model, n.write(saveAs, model, force)
+}
+
+func (n *NetworkOps) write(name string, model *models.NetworkModel, force bool) error {
+	if err := n.Client.Put("/network/networkModel", model); err != nil {
+		return fmt.Errorf("write network model: %w", err)
+	}
+	if _, err := n.SaveAs(name, false, force); err != nil {
+		return fmt.Errorf("save network %s: %w", name, err)
+	}
+	return nil
+}
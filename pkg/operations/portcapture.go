@@ -0,0 +1,266 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CaptureDirection selects the traffic of a port capture to export.
+type CaptureDirection string
+
+const (
+	CaptureTx   CaptureDirection = "tx"
+	CaptureRx   CaptureDirection = "rx"
+	CaptureBoth CaptureDirection = "both"
+)
+
+// CaptureUnit is the unit of the start and size of an export window.
+type CaptureUnit string
+
+const (
+	CaptureFrames     CaptureUnit = "frames"
+	CaptureMegabytes  CaptureUnit = "megabytes"
+	CapturePercentage CaptureUnit = "percentage"
+	CaptureSeconds    CaptureUnit = "seconds"
+)
+
+// CaptureSettings configures the capture of a port.
+type CaptureSettings struct {
+	// Filter is a BPF expression such as "tcp port 80"; empty captures
+	// everything.
+	Filter string
+	// BufferSize limits the capture buffer in megabytes; zero keeps the
+	// port default.
+	BufferSize int
+}
+
+// CaptureExport selects what ExportCapture writes. A zero Size exports
+// the whole buffer.
+type CaptureExport struct {
+	Slot      int
+	Port      int
+	Direction CaptureDirection
+	Start     int
+	StartType CaptureUnit
+	Size      int
+	SizeType  CaptureUnit
+}
+
+// Args returns the arguments of the exportCapture operation.
+func (e CaptureExport) Args() map[string]interface{} {
+	args := map[string]interface{}{
+		"slot": e.Slot,
+		"port": e.Port,
+		"dir":  string(e.Direction),
+	}
+	if e.Direction == "" {
+		args["dir"] = string(CaptureBoth)
+	}
+	if e.Start > 0 {
+		args["start"] = e.Start
+		args["starttype"] = string(unitOr(e.StartType, CaptureFrames))
+	}
+	if e.Size > 0 {
+		args["size"] = e.Size
+		args["sizetype"] = string(unitOr(e.SizeType, CaptureFrames))
+	}
+	return args
+}
+
+func unitOr(u, def CaptureUnit) CaptureUnit {
+	if u == "" {
+		return def
+	}
+	return u
+}
+
+func portPath(slot, port int) string {
+	return fmt.Sprintf("/topology/slot/%d/port/%d", slot, port)
+}
+
+// EnableCapture arms capture on a reserved port.
+func (t *TopologyOps) EnableCapture(slot, port int, settings CaptureSettings) error {
+	if err := CheckCaptureFilter(settings.Filter); err != nil {
+		return err
+	}
+	if settings.BufferSize < 0 {
+		return fmt.Errorf("capture buffer size %d is negative", settings.BufferSize)
+	}
+	patch := map[string]interface{}{"capture": true, "captureFilter": settings.Filter}
+	if settings.BufferSize > 0 {
+		patch["captureBufferSize"] = settings.BufferSize
+	}
+	if err := t.Client.Patch(portPath(slot, port), patch); err != nil {
+		return fmt.Errorf("enable capture on %d/%d: %w", slot, port, err)
+	}
+	return nil
+}
+
+// DisableCapture stops capturing on a port.
+func (t *TopologyOps) DisableCapture(slot, port int) error {
+	if err := t.Client.Patch(portPath(slot, port), map[string]interface{}{"capture": false}); err != nil {
+		return fmt.Errorf("disable capture on %d/%d: %w", slot, port, err)
+	}
+	return nil
+}
+
+// Export writes the capture selected by e to path.
+func (t *TopologyOps) Export(path string, e CaptureExport) error {
+	if err := t.ExportCapture(path, e.Args()); err != nil {
+		return fmt.Errorf("export capture of %d/%d %s: %w", e.Slot, e.Port, e.Direction, err)
+	}
+	return nil
+}
+
+// CheckCaptureFilter catches filter mistakes that would otherwise only
+// show up as an empty capture: unbalanced parentheses, dangling operators
+// and misplaced keywords. It does not compile the expression.
+func CheckCaptureFilter(filter string) error {
+	expr := strings.NewReplacer("(", " ( ", ")", " ) ", "!", " not ", "&&", " and ", "||", " or ").Replace(filter)
+	depth := 0
+	prevOp := true
+	for _, tok := range strings.Fields(expr) {
+		switch tok {
+		case "(":
+			depth++
+			prevOp = true
+		case ")":
+			depth--
+			if depth < 0 || prevOp {
+				return fmt.Errorf("capture filter %q: unexpected )", filter)
+			}
+		case "and", "or":
+			if prevOp {
+				return fmt.Errorf("capture filter %q: unexpected %s", filter, tok)
+			}
+			prevOp = true
+		case "not":
+			prevOp = true
+		default:
+			prevOp = false
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("capture filter %q: unbalanced parentheses", filter)
+	}
+	if prevOp && strings.TrimSpace(filter) != "" {
+		return fmt.Errorf("capture filter %q: incomplete expression", filter)
+	}
+	return nil
+}
+
+// CapturePort is a port taking part in a CaptureSession.
+type CapturePort struct {
+	Slot int
+	Port int
+}
+
+// CaptureSession arms capture on a set of ports for the length of a run,
+// exports the captures when the run or its check fails and disarms the
+// ports again.
+type CaptureSession struct {
+	Topology *TopologyOps
+	Ports    []CapturePort
+	Settings CaptureSettings
+	// Dir receives the exported files, named
+	// run<id>_slot<slot>_port<port>.pcap.
+	Dir string
+	// Always exports after successful runs as well.
+	Always bool
+
+	armed []CapturePort
+	files []string
+}
+
+// Arm enables capture on every port of the session. Ports armed before a
+// failure stay armed until Close.
+func (c *CaptureSession) Arm() error {
+	for _, p := range c.Ports {
+		if err := c.Topology.EnableCapture(p.Slot, p.Port, c.Settings); err != nil {
+			return err
+		}
+		c.armed = append(c.armed, p)
+	}
+	return nil
+}
+
+// Export writes the capture of every armed port for runID and returns the
+// files written. A port that fails to export does not stop the others;
+// the failures are returned together.
+func (c *CaptureSession) Export(runID int) ([]string, error) {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	var files, errs []string
+	for _, p := range c.armed {
+		path := filepath.Join(c.Dir, fmt.Sprintf("run%d_slot%d_port%d.pcap", runID, p.Slot, p.Port))
+		if err := c.Topology.Export(path, CaptureExport{Slot: p.Slot, Port: p.Port, Direction: CaptureBoth}); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		files = append(files, path)
+	}
+	c.files = append(c.files, files...)
+	if len(errs) > 0 {
+		return files, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return files, nil
+}
+
+// Run arms the ports, runs the model until it completes and disarms the
+// ports. When the run cannot be started or waited for, or check rejects
+// it, the captures are exported before the error is returned. A run that
+// completes counts as a success whatever its test result, so a failed
+// result is only caught by a check reading it; check may be nil.
+func (c *CaptureSession) Run(ctx context.Context, tm *TestModelOps, modelname string, group int, interval time.Duration, check func(runID int) error) (int, []string, error) {
+	defer c.Close()
+	if err := c.Arm(); err != nil {
+		return 0, nil, err
+	}
+	runID, err := tm.RunAndWait(ctx, modelname, group, false, interval)
+	if err == nil && check != nil {
+		err = check(runID)
+	}
+	if runID == 0 || (err == nil && !c.Always) {
+		return runID, nil, err
+	}
+	files, exportErr := c.Export(runID)
+	if exportErr != nil {
+		if err == nil {
+			return runID, files, exportErr
+		}
+		return runID, files, fmt.Errorf("%w (capture export failed: %v)", err, exportErr)
+	}
+	return runID, files, err
+}
+
+// Close disarms every armed port. It is a no-op on a nil session.
+func (c *CaptureSession) Close() error {
+	if c == nil {
+		return nil
+	}
+	var first error
+	for _, p := range c.armed {
+		if err := c.Topology.DisableCapture(p.Slot, p.Port); err != nil && first == nil {
+			first = err
+		}
+	}
+	c.armed = nil
+	return first
+}
+
+// RemoveExports deletes the files exported by the session.
+func (c *CaptureSession) RemoveExports() error {
+	var first error
+	for _, f := range c.files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) && first == nil {
+			first = err
+		}
+	}
+	c.files = nil
+	return first
+}
@@ -0,0 +1,128 @@
+package operations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ReportTable is a report section flattened into rows keyed by column
+// header.
+type ReportTable struct {
+	Section string
+	Columns []string
+	Rows    []map[string]string
+}
+
+// Table reads a report section and flattens it. Sections are returned
+// either row-wise, as a list of objects keyed by header, or column-wise,
+// as objects mapping each header to its list of values.
+func (r *ReportsOps) Table(runid int, sectionId string) (*ReportTable, error) {
+	resp, err := r.GetReportTable(runid, sectionId)
+	if err != nil {
+		return nil, fmt.Errorf("report %d section %s: %w", runid, sectionId, err)
+	}
+	t := &ReportTable{Section: sectionId}
+	items, ok := resp.([]interface{})
+	if !ok {
+		items = []interface{}{resp}
+	}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if columnar(m) {
+			t.addColumns(m)
+		} else {
+			t.addRow(m)
+		}
+	}
+	return t, nil
+}
+
+func columnar(m map[string]interface{}) bool {
+	for _, v := range m {
+		if _, ok := v.([]interface{}); !ok {
+			return false
+		}
+	}
+	return len(m) > 0
+}
+
+func (t *ReportTable) addRow(m map[string]interface{}) {
+	row := make(map[string]string, len(m))
+	for _, k := range sortedKeys(m) {
+		t.addColumn(k)
+		row[k] = toString(m[k])
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+func (t *ReportTable) addColumns(m map[string]interface{}) {
+	start := len(t.Rows)
+	for _, k := range sortedKeys(m) {
+		t.addColumn(k)
+		for i, v := range m[k].([]interface{}) {
+			for len(t.Rows) <= start+i {
+				t.Rows = append(t.Rows, make(map[string]string))
+			}
+			t.Rows[start+i][k] = toString(v)
+		}
+	}
+}
+
+func (t *ReportTable) addColumn(name string) {
+	for _, c := range t.Columns {
+		if c == name {
+			return
+		}
+	}
+	t.Columns = append(t.Columns, name)
+}
+
+// Value returns a cell of the table. The row is selected by key, matched
+// against every cell of the row case-insensitively; an empty key selects
+// the last row, which holds the totals in most sections.
+func (t *ReportTable) Value(column, key string) (string, error) {
+	if len(t.Rows) == 0 {
+		return "", fmt.Errorf("section %s is empty", t.Section)
+	}
+	row := t.Rows[len(t.Rows)-1]
+	if key != "" {
+		row = nil
+		for _, r := range t.Rows {
+			if rowHas(r, key) {
+				row = r
+				break
+			}
+		}
+		if row == nil {
+			return "", fmt.Errorf("section %s has no row %q", t.Section, key)
+		}
+	}
+	for c, v := range row {
+		if strings.EqualFold(c, column) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("section %s has no column %q (columns: %s)", t.Section, column, strings.Join(t.Columns, ", "))
+}
+
+func rowHas(row map[string]string, key string) bool {
+	for _, v := range row {
+		if strings.EqualFold(strings.TrimSpace(v), key) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,171 @@
+package operations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bps-client-go/pkg/models"
+)
+
+// CVECoverage describes how a set of CVE identifiers maps onto strikes.
+type CVECoverage struct {
+	Name      string
+	CVEs      []string
+	Strikes   map[string][]models.StrikeInfo
+	Uncovered []string
+	Multiple  []string
+}
+
+// Covered returns the number of CVEs resolved to at least one strike.
+func (c *CVECoverage) Covered() int {
+	return len(c.CVEs) - len(c.Uncovered)
+}
+
+// Summary returns a one line coverage summary suitable for a list description.
+func (c *CVECoverage) Summary() string {
+	s := fmt.Sprintf("%d/%d CVEs covered", c.Covered(), len(c.CVEs))
+	if len(c.Uncovered) > 0 {
+		s += "; no coverage: " + strings.Join(c.Uncovered, ", ")
+	}
+	return s
+}
+
+// strikeIDs returns the distinct strike paths in deterministic order.
+func (c *CVECoverage) strikeIDs() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, cve := range c.CVEs {
+		for _, strike := range c.Strikes[cve] {
+			id := strikeID(strike)
+			if id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// ResolveCVEs looks up the strikes referencing each CVE.
+func (s *StrikesOps) ResolveCVEs(cves []string) (*CVECoverage, error) {
+	cov := &CVECoverage{Strikes: make(map[string][]models.StrikeInfo)}
+	for _, raw := range cves {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		cve := normalizeCVE(raw)
+		if _, done := cov.Strikes[cve]; done {
+			continue
+		}
+		strikes, err := s.Query(NewStrikeQuery().CVE(cve))
+		if err != nil {
+			return nil, fmt.Errorf("resolve %s: %w", cve, err)
+		}
+		cov.CVEs = append(cov.CVEs, cve)
+		cov.Strikes[cve] = strikes
+		switch {
+		case len(strikes) == 0:
+			cov.Uncovered = append(cov.Uncovered, cve)
+		case len(strikes) > 1:
+			cov.Multiple = append(cov.Multiple, cve)
+		}
+	}
+	sort.Strings(cov.Uncovered)
+	sort.Strings(cov.Multiple)
+	return cov, nil
+}
+
+// BuildFromCVEs resolves cves to strikes and saves them as the strike list
+// name. When update is set the existing list is loaded and extended,
+// otherwise a new list is created. The coverage summary is stored as the
+// list description.
+func (s *StrikeListOps) BuildFromCVEs(name string, cves []string, update, force bool) (*CVECoverage, error) {
+	strikes := &StrikesOps{Client: s.Client}
+	cov, err := strikes.ResolveCVEs(cves)
+	if err != nil {
+		return nil, err
+	}
+	cov.Name = name
+
+	existing := make(map[string]bool)
+	if update {
+		if _, err := s.Load(name); err != nil {
+			return cov, fmt.Errorf("load strike list %s: %w", name, err)
+		}
+		ids, err := s.CurrentStrikes()
+		if err != nil {
+			return cov, err
+		}
+		for _, id := range ids {
+			existing[id] = true
+		}
+	} else if _, err := s.New(nil); err != nil {
+		return cov, fmt.Errorf("create strike list: %w", err)
+	}
+
+	var add []string
+	for _, id := range cov.strikeIDs() {
+		if !existing[id] {
+			add = append(add, id)
+		}
+	}
+	if len(add) > 0 {
+		if _, err := s.Add(strikeRefs(add), true, nil); err != nil {
+			return cov, fmt.Errorf("add strikes: %w", err)
+		}
+	}
+	if err := s.Client.Patch("/strikeList", map[string]interface{}{"description": cov.Summary()}); err != nil {
+		return cov, fmt.Errorf("set strike list description: %w", err)
+	}
+	if update {
+		_, err = s.Save(&name, force)
+	} else {
+		_, err = s.SaveAs(name, force)
+	}
+	if err != nil {
+		return cov, fmt.Errorf("save strike list %s: %w", name, err)
+	}
+	return cov, nil
+}
+
+// CurrentStrikes returns the strike paths of the working strike list.
+func (s *StrikeListOps) CurrentStrikes() ([]string, error) {
+	resp, err := s.Client.Get("/strikeList/strikes", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read strike list contents: %w", err)
+	}
+	items, err := listFrom(resp, "strikes", "strike")
+	if err != nil {
+		return nil, err
+	}
+	var strikes []models.StrikeInfo
+	if err := decodeInto(items, &strikes); err != nil {
+		return nil, fmt.Errorf("decode strike list contents: %w", err)
+	}
+	ids := make([]string, 0, len(strikes))
+	for _, strike := range strikes {
+		if id := strikeID(strike); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// strikeID returns the identifier strike list operations expect, which is
+// the strike path when known.
+func strikeID(strike models.StrikeInfo) string {
+	if strike.Path != "" {
+		return strike.Path
+	}
+	return strike.ID
+}
+
+// strikeRefs turns strike ids into the references Add and Remove expect.
+func strikeRefs(ids []string) []map[string]interface{} {
+	refs := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, map[string]interface{}{"id": id})
+	}
+	return refs
+}
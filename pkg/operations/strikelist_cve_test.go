@@ -0,0 +1,117 @@
+package operations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func cveCatalog() []map[string]interface{} {
+	return []map[string]interface{}{
+		strike("log4j_1", "http", "2021", "cve:2021-44228"),
+		strike("log4j_2", "ldap", "2021", "CVE:CVE-2021-44228", "bid:44228"),
+		strike("shellshock", "http", "2014", "cve:cve-2014-6271"),
+		strike("bid_only", "http", "2021", "bid:2021-45046"),
+	}
+}
+
+func TestResolveCVEs(t *testing.T) {
+	c := strikeCatalog(cveCatalog(), false)
+	cov, err := (&StrikesOps{Client: c}).ResolveCVEs([]string{"2021-44228", "cve-2014-6271", " ", "CVE-2021-44228", "CVE-2021-45046"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"CVE-2021-44228", "CVE-2014-6271", "CVE-2021-45046"}; !reflect.DeepEqual(cov.CVEs, want) {
+		t.Errorf("CVEs = %v, want %v", cov.CVEs, want)
+	}
+	ids := func(cve string) []string {
+		var out []string
+		for _, s := range cov.Strikes[cve] {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+	if got := ids("CVE-2021-44228"); !reflect.DeepEqual(got, []string{"log4j_1", "log4j_2"}) {
+		t.Errorf("CVE-2021-44228 strikes = %v", got)
+	}
+	if got := ids("CVE-2014-6271"); !reflect.DeepEqual(got, []string{"shellshock"}) {
+		t.Errorf("CVE-2014-6271 strikes = %v", got)
+	}
+	if !reflect.DeepEqual(cov.Uncovered, []string{"CVE-2021-45046"}) || !reflect.DeepEqual(cov.Multiple, []string{"CVE-2021-44228"}) {
+		t.Errorf("Uncovered = %v, Multiple = %v", cov.Uncovered, cov.Multiple)
+	}
+	if got, want := cov.Summary(), "2/3 CVEs covered; no coverage: CVE-2021-45046"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	// One search per distinct CVE
+	if len(c.calls) != 3 {
+		t.Errorf("calls = %v", c.calls)
+	}
+}
+
+func TestBuildFromCVEs(t *testing.T) {
+	tests := []struct {
+		name       string
+		update     bool
+		existing   []interface{}
+		wantAdd    []interface{}
+		wantWrites []string
+	}{
+		{
+			name:    "new list",
+			wantAdd: []interface{}{"/strikes/log4j_1.xml", "/strikes/log4j_2.xml", "/strikes/shellshock.xml"},
+			wantWrites: []string{
+				"POST /strikeList/operations/new",
+				"POST /strikeList/operations/add",
+				"PATCH /strikeList",
+				"POST /strikeList/operations/saveAs",
+			},
+		},
+		{
+			name:     "update",
+			update:   true,
+			existing: []interface{}{map[string]interface{}{"path": "/strikes/log4j_2.xml"}},
+			wantAdd:  []interface{}{"/strikes/log4j_1.xml", "/strikes/shellshock.xml"},
+			wantWrites: []string{
+				"POST /strikeList/operations/load",
+				"POST /strikeList/operations/add",
+				"PATCH /strikeList",
+				"POST /strikeList/operations/save",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := strikeCatalog(cveCatalog(), false)
+			var added []interface{}
+			var description interface{}
+			c := &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+				switch path {
+				case "/strikes/operations/search":
+					return catalog.handle(method, path, body)
+				case "/strikeList/strikes":
+					return map[string]interface{}{"strikes": tt.existing}, nil
+				case "/strikeList/operations/add":
+					for _, ref := range body.(map[string]interface{})["strike"].([]map[string]interface{}) {
+						added = append(added, ref["id"])
+					}
+				case "/strikeList":
+					description = body.(map[string]interface{})["description"]
+				}
+				return nil, nil
+			}}
+			cov, err := (&StrikeListOps{Client: c}).BuildFromCVEs("log4j", []string{"CVE-2021-44228", "CVE-2014-6271"}, tt.update, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(added, tt.wantAdd) {
+				t.Errorf("added = %v, want %v", added, tt.wantAdd)
+			}
+			if description != cov.Summary() || cov.Name != "log4j" {
+				t.Errorf("description = %v, name = %s", description, cov.Name)
+			}
+			if got := c.writes(); !reflect.DeepEqual(got, tt.wantWrites) {
+				t.Errorf("writes = %v, want %v", got, tt.wantWrites)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package operations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"bps-client-go/pkg/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StrikeListManifest is the on-disk description of a strike list. It is
+// either YAML with name/description/strikes keys or plain text with one
+// strike path per line and # comments.
+type StrikeListManifest struct {
+	Name        string   `yaml:"name,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Strikes     []string `yaml:"strikes"`
+}
+
+// LoadStrikeListManifest reads a manifest from path.
+func LoadStrikeListManifest(path string) (*StrikeListManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := ParseStrikeListManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// ParseStrikeListManifest parses a YAML or plain text manifest.
+func ParseStrikeListManifest(data []byte) (*StrikeListManifest, error) {
+	m := &StrikeListManifest{}
+	if isYAMLManifest(data) {
+		if err := yaml.Unmarshal(data, m); err != nil {
+			return nil, fmt.Errorf("parse strike list manifest: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if i := strings.Index(line, "#"); i >= 0 {
+				line = line[:i]
+			}
+			if line = strings.TrimSpace(line); line != "" {
+				m.Strikes = append(m.Strikes, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	m.Strikes = uniqueStrings(m.Strikes)
+	return m, nil
+}
+
+func isYAMLManifest(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		return strings.HasPrefix(line, "name:") ||
+			strings.HasPrefix(line, "description:") ||
+			strings.HasPrefix(line, "strikes:")
+	}
+	return false
+}
+
+// StrikeListPlan is the set of changes needed to bring a strike list in
+// line with its manifest.
+type StrikeListPlan struct {
+	Name   string
+	Create bool
+	Add    []string
+	Remove []string
+}
+
+// Empty reports whether the list already matches the manifest.
+func (p *StrikeListPlan) Empty() bool {
+	return !p.Create && len(p.Add) == 0 && len(p.Remove) == 0
+}
+
+func (p *StrikeListPlan) String() string {
+	var b strings.Builder
+	switch {
+	case p.Create:
+		fmt.Fprintf(&b, "strike list %s: create with %d strikes\n", p.Name, len(p.Add))
+	case p.Empty():
+		fmt.Fprintf(&b, "strike list %s: up to date\n", p.Name)
+		return b.String()
+	default:
+		fmt.Fprintf(&b, "strike list %s: %d to add, %d to remove\n", p.Name, len(p.Add), len(p.Remove))
+	}
+	for _, id := range p.Add {
+		fmt.Fprintf(&b, "  + %s\n", id)
+	}
+	for _, id := range p.Remove {
+		fmt.Fprintf(&b, "  - %s\n", id)
+	}
+	return b.String()
+}
+
+// Plan computes the changes needed to make the strike list name match
+// manifest without changing the chassis. A saved list can only be read by
+// loading it, so the contents of name are taken from the working strike
+// list when that is name, unsaved edits included. Otherwise name is only
+// loaded when the working list is unnamed and empty, which is recreated
+// afterwards; any other working list might hold unsaved edits and Plan
+// refuses rather than replace it.
+func (s *StrikeListOps) Plan(name string, manifest *StrikeListManifest) (*StrikeListPlan, error) {
+	exists, err := s.exists(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return createPlan(name, manifest), nil
+	}
+	working, err := s.working()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case working.Name == name:
+		current := make([]string, 0, len(working.Strikes))
+		for _, strike := range working.Strikes {
+			if id := strikeID(strike); id != "" {
+				current = append(current, id)
+			}
+		}
+		return diffPlan(name, manifest, current), nil
+	case working.Name != "" || len(working.Strikes) > 0:
+		return nil, fmt.Errorf("reading strike list %s means loading it over the working strike list %q, which may have unsaved changes; save it or start a new one first", name, working.Name)
+	}
+	if _, err := s.Load(name); err != nil {
+		return nil, fmt.Errorf("load strike list %s: %w", name, err)
+	}
+	current, err := s.CurrentStrikes()
+	if _, newErr := s.New(nil); newErr != nil && err == nil {
+		err = fmt.Errorf("recreate working strike list: %w", newErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return diffPlan(name, manifest, current), nil
+}
+
+// createPlan is the plan for a list that does not exist yet.
+func createPlan(name string, manifest *StrikeListManifest) *StrikeListPlan {
+	return &StrikeListPlan{Name: name, Create: true, Add: append([]string(nil), manifest.Strikes...)}
+}
+
+// diffPlan is the plan turning the strikes current of an existing list
+// into those of manifest.
+func diffPlan(name string, manifest *StrikeListManifest, current []string) *StrikeListPlan {
+	plan := &StrikeListPlan{Name: name}
+	have := make(map[string]bool, len(current))
+	for _, id := range current {
+		have[id] = true
+	}
+	want := make(map[string]bool, len(manifest.Strikes))
+	for _, id := range manifest.Strikes {
+		want[id] = true
+		if !have[id] {
+			plan.Add = append(plan.Add, id)
+		}
+	}
+	for _, id := range current {
+		if !want[id] {
+			plan.Remove = append(plan.Remove, id)
+		}
+	}
+	sort.Strings(plan.Remove)
+	return plan
+}
+
+// Sync brings the strike list name in line with manifest and returns the
+// plan it carried out, leaving name loaded as the working strike list.
+// With dryRun set only the plan is computed, by Plan, and nothing is
+// changed on the chassis; plan.String() describes it.
+func (s *StrikeListOps) Sync(ctx context.Context, name string, manifest *StrikeListManifest, dryRun bool) (*StrikeListPlan, error) {
+	if name == "" {
+		name = manifest.Name
+	}
+	if name == "" {
+		return nil, fmt.Errorf("strike list name is required")
+	}
+	if dryRun {
+		return s.Plan(name, manifest)
+	}
+	exists, err := s.exists(name)
+	if err != nil {
+		return nil, err
+	}
+	plan := createPlan(name, manifest)
+	if exists {
+		if _, err := s.Load(name); err != nil {
+			return nil, fmt.Errorf("load strike list %s: %w", name, err)
+		}
+		current, err := s.CurrentStrikes()
+		if err != nil {
+			return nil, err
+		}
+		plan = diffPlan(name, manifest, current)
+	} else if _, err := s.New(nil); err != nil {
+		return plan, fmt.Errorf("create strike list: %w", err)
+	}
+	if plan.Empty() {
+		return plan, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return plan, err
+	}
+	if len(plan.Remove) > 0 {
+		if _, err := s.Remove(strikeRefs(plan.Remove)); err != nil {
+			return plan, fmt.Errorf("remove strikes: %w", err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return plan, err
+	}
+	if len(plan.Add) > 0 {
+		if _, err := s.Add(strikeRefs(plan.Add), true, nil); err != nil {
+			return plan, fmt.Errorf("add strikes: %w", err)
+		}
+	}
+	if manifest.Description != "" {
+		if err := s.Client.Patch("/strikeList", map[string]interface{}{"description": manifest.Description}); err != nil {
+			return plan, fmt.Errorf("set strike list description: %w", err)
+		}
+	}
+	if plan.Create {
+		_, err = s.SaveAs(name, false)
+	} else {
+		_, err = s.Save(&name, true)
+	}
+	if err != nil {
+		return plan, fmt.Errorf("save strike list %s: %w", name, err)
+	}
+	return plan, nil
+}
+
+func (s *StrikeListOps) exists(name string) (bool, error) {
+	found, err := findByName(s.Search, name, "strikeListInfo", "result")
+	if err != nil {
+		return false, fmt.Errorf("strike list %s: %w", name, err)
+	}
+	return found, nil
+}
+
+func (s *StrikeListOps) working() (*models.StrikeList, error) {
+	depth := models.FullDepth
+	resp, err := s.Client.Get("/strikeList", &depth, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read working strike list: %w", err)
+	}
+	list := &models.StrikeList{}
+	if err := decodeInto(resp, list); err != nil {
+		return nil, fmt.Errorf("decode working strike list: %w", err)
+	}
+	return list, nil
+}
+
+func uniqueStrings(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	out := in[:0]
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package operations
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func strikeListStub(saved map[string][]string, working string, workingStrikes []string) *stubClient {
+	loaded := working
+	strikes := func(ids []string) []interface{} {
+		out := make([]interface{}, len(ids))
+		for i, id := range ids {
+			out[i] = map[string]interface{}{"path": id}
+		}
+		return out
+	}
+	return &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+		switch path {
+		case "/strikeList/operations/search":
+			var items []interface{}
+			for name := range saved {
+				if strings.Contains(name, body.(map[string]interface{})["searchString"].(string)) {
+					items = append(items, map[string]interface{}{"name": name})
+				}
+			}
+			return map[string]interface{}{"strikeListInfo": items}, nil
+		case "/strikeList":
+			return map[string]interface{}{"name": loaded, "strikes": strikes(workingStrikes)}, nil
+		case "/strikeList/strikes":
+			return map[string]interface{}{"strikes": strikes(workingStrikes)}, nil
+		case "/strikeList/operations/load":
+			loaded = body.(map[string]interface{})["template"].(string)
+			workingStrikes = saved[loaded]
+		case "/strikeList/operations/new":
+			loaded, workingStrikes = "", nil
+		}
+		return nil, nil
+	}}
+}
+
+func TestStrikeListPlan(t *testing.T) {
+	saved := map[string][]string{"web": {"/a", "/b"}}
+	manifest := &StrikeListManifest{Strikes: []string{"/b", "/c"}}
+	tests := []struct {
+		name           string
+		list           string
+		working        string
+		workingStrikes []string
+		want           *StrikeListPlan
+		wantWrites     []string
+		wantErr        string
+	}{
+		{
+			name:    "new list",
+			list:    "mail",
+			working: "web", workingStrikes: []string{"/x"},
+			want: &StrikeListPlan{Name: "mail", Create: true, Add: []string{"/b", "/c"}},
+		},
+		{
+			name:    "working list is the target",
+			list:    "web",
+			working: "web", workingStrikes: []string{"/a", "/b"},
+			want: &StrikeListPlan{Name: "web", Add: []string{"/c"}, Remove: []string{"/a"}},
+		},
+		{
+			name: "empty working list",
+			list: "web",
+			want: &StrikeListPlan{Name: "web", Add: []string{"/c"}, Remove: []string{"/a"}},
+			wantWrites: []string{
+				"POST /strikeList/operations/load",
+				"POST /strikeList/operations/new",
+			},
+		},
+		{
+			name:    "other working list",
+			list:    "web",
+			working: "dns", workingStrikes: []string{"/x"},
+			wantErr: `over the working strike list "dns"`,
+		},
+		{
+			name:           "unnamed working list with strikes",
+			list:           "web",
+			workingStrikes: []string{"/x"},
+			wantErr:        "may have unsaved changes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := strikeListStub(saved, tt.working, tt.workingStrikes)
+			s := &StrikeListOps{Client: c}
+			plan, err := s.Sync(context.Background(), tt.list, manifest, true)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Sync(dry run) error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(plan, tt.want) {
+				t.Errorf("Sync(dry run) = %+v, want %+v", plan, tt.want)
+			}
+			if got := c.writes(); !reflect.DeepEqual(got, tt.wantWrites) {
+				t.Errorf("dry run sent %v, want %v", got, tt.wantWrites)
+			}
+		})
+	}
+}
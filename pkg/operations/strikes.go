@@ -1,16 +1,255 @@
 package operations
 
-type StrikesOps struct {
-	Client ClientWrapper
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"bps-client-go/pkg/models"
+)
+
+const defaultStrikePageSize = 100
+
+// maxStrikePages bounds Query against servers that ignore the offset and
+// keep returning full pages.
+const maxStrikePages = 1000
+
+// StrikeQuery describes a strike catalog search with facet filters. Facets
+// are sent to the server as field:value terms and then re-checked on the
+// client, so results only contain exact matches.
+type StrikeQuery struct {
+	protocol   string
+	category   string
+	direction  string
+	severity   string
+	yearFrom   int
+	yearTo     int
+	refType    string
+	refValue   string
+	keyword    string
+	pathPrefix string
+	text       string
+
+	PageSize  int
+	MaxResult int
+	Sort      string
+	SortOrder string
 }
 
-func (s *StrikesOps) Search(searchString string, limit int, sort string, sortorder string, offset int) (interface{}, error) {
-	params := map[string]interface{}{
-		"searchString": searchString,
-		"limit":        limit,
-		"sort":         sort,
-		"sortorder":    sortorder,
-		"offset":       offset,
+// NewStrikeQuery returns an empty query sorted by name.
+func NewStrikeQuery() *StrikeQuery {
+	return &StrikeQuery{
+		PageSize:  defaultStrikePageSize,
+		Sort:      "name",
+		SortOrder: "ascending",
 	}
-	return s.Client.Post("/strikes/operations/search", params)
-}
\ No newline at end of file
+}
+
+func (q *StrikeQuery) Text(text string) *StrikeQuery {
+	q.text = text
+	return q
+}
+
+func (q *StrikeQuery) Protocol(protocol string) *StrikeQuery {
+	q.protocol = protocol
+	return q
+}
+
+func (q *StrikeQuery) Category(category string) *StrikeQuery {
+	q.category = category
+	return q
+}
+
+func (q *StrikeQuery) Direction(direction string) *StrikeQuery {
+	q.direction = direction
+	return q
+}
+
+func (q *StrikeQuery) Severity(severity string) *StrikeQuery {
+	q.severity = severity
+	return q
+}
+
+// Years restricts the query to strikes published between from and to
+// inclusive. A zero bound is left open.
+func (q *StrikeQuery) Years(from, to int) *StrikeQuery {
+	q.yearFrom = from
+	q.yearTo = to
+	return q
+}
+
+// Reference restricts the query to strikes carrying a reference of the
+// given type (cve, bid, ...) and value.
+func (q *StrikeQuery) Reference(refType, value string) *StrikeQuery {
+	q.refType = strings.ToLower(refType)
+	q.refValue = value
+	return q
+}
+
+func (q *StrikeQuery) CVE(id string) *StrikeQuery {
+	return q.Reference("cve", normalizeCVE(id))
+}
+
+func (q *StrikeQuery) BID(id string) *StrikeQuery {
+	return q.Reference("bid", id)
+}
+
+func (q *StrikeQuery) Keyword(keyword string) *StrikeQuery {
+	q.keyword = keyword
+	return q
+}
+
+func (q *StrikeQuery) PathPrefix(prefix string) *StrikeQuery {
+	q.pathPrefix = prefix
+	return q
+}
+
+// String renders the query as a strike search string.
+func (q *StrikeQuery) String() string {
+	var terms []string
+	add := func(field, value string) {
+		if value == "" {
+			return
+		}
+		if strings.ContainsAny(value, " \t\"") {
+			value = strconv.Quote(value)
+		}
+		terms = append(terms, field+":"+value)
+	}
+	add("protocol", q.protocol)
+	add("category", q.category)
+	add("direction", q.direction)
+	add("severity", q.severity)
+	if q.yearFrom != 0 && q.yearFrom == q.yearTo {
+		add("year", strconv.Itoa(q.yearFrom))
+	}
+	if q.refType != "" {
+		add(q.refType, strings.TrimPrefix(q.refValue, "CVE-"))
+	}
+	add("keyword", q.keyword)
+	add("path", q.pathPrefix)
+	if q.text != "" {
+		terms = append(terms, q.text)
+	}
+	return strings.Join(terms, " ")
+}
+
+// Match reports whether strike satisfies every facet of the query.
+func (q *StrikeQuery) Match(strike models.StrikeInfo) bool {
+	if !equalFold(q.protocol, strike.Protocol) ||
+		!equalFold(q.category, strike.Category) ||
+		!equalFold(q.direction, strike.Direction) ||
+		!equalFold(q.severity, strike.Severity) {
+		return false
+	}
+	if q.yearFrom != 0 || q.yearTo != 0 {
+		year, err := strconv.Atoi(strings.TrimSpace(strike.Year))
+		if err != nil {
+			return false
+		}
+		if (q.yearFrom != 0 && year < q.yearFrom) || (q.yearTo != 0 && year > q.yearTo) {
+			return false
+		}
+	}
+	if q.refType != "" && !hasReference(strike, q.refType, q.refValue) {
+		return false
+	}
+	if q.keyword != "" && !hasKeyword(strike, q.keyword) {
+		return false
+	}
+	if q.pathPrefix != "" && !strings.HasPrefix(strike.Path, q.pathPrefix) {
+		return false
+	}
+	return true
+}
+
+// Query pages through the strike catalog and returns every strike matching q.
+func (s *StrikesOps) Query(q *StrikeQuery) ([]models.StrikeInfo, error) {
+	pageSize := q.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultStrikePageSize
+	}
+	searchString := q.String()
+
+	var out []models.StrikeInfo
+	seen := make(map[string]bool)
+	for pages, offset := 0, 0; ; pages, offset = pages+1, offset+pageSize {
+		if pages == maxStrikePages {
+			return out, fmt.Errorf("strike search %q: more than %d pages", searchString, maxStrikePages)
+		}
+		resp, err := s.Search(searchString, pageSize, q.Sort, q.SortOrder, offset)
+		if err != nil {
+			return nil, fmt.Errorf("strike search %q: %w", searchString, err)
+		}
+		items, err := listFrom(resp, "strikes", "strike", "result")
+		if err != nil {
+			return nil, err
+		}
+		var page []models.StrikeInfo
+		if err := decodeInto(items, &page); err != nil {
+			return nil, fmt.Errorf("decode strike search result: %w", err)
+		}
+		fresh := 0
+		for _, strike := range page {
+			key := strike.Path + "\x00" + strike.ID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			fresh++
+			if q.Match(strike) {
+				out = append(out, strike)
+				if q.MaxResult > 0 && len(out) >= q.MaxResult {
+					return out, nil
+				}
+			}
+		}
+		// A short page ends the catalog; a page of strikes already seen
+		// means the server ignores the offset.
+		if len(items) < pageSize || fresh == 0 {
+			return out, nil
+		}
+	}
+}
+
+func equalFold(want, got string) bool {
+	return want == "" || strings.EqualFold(want, got)
+}
+
+func hasReference(strike models.StrikeInfo, refType, value string) bool {
+	for _, ref := range strike.References {
+		if !strings.EqualFold(ref.Type, refType) {
+			continue
+		}
+		if value == "" {
+			return true
+		}
+		got := ref.Value
+		if strings.EqualFold(refType, "cve") {
+			got = normalizeCVE(got)
+		}
+		if strings.EqualFold(got, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasKeyword(strike models.StrikeInfo, keyword string) bool {
+	for _, kw := range strike.Keywords {
+		if strings.EqualFold(kw.Name, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeCVE turns "2021-44228", "cve-2021-44228" and "CVE-2021-44228"
+// into the canonical "CVE-2021-44228".
+func normalizeCVE(id string) string {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	if !strings.HasPrefix(id, "CVE-") {
+		id = "CVE-" + id
+	}
+	return id
+}
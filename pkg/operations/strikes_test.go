@@ -0,0 +1,149 @@
+package operations
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func strike(id, protocol, year string, refs ...string) map[string]interface{} {
+	var references []interface{}
+	for _, ref := range refs {
+		refType, value, _ := strings.Cut(ref, ":")
+		references = append(references, map[string]interface{}{"type": refType, "value": value})
+	}
+	return map[string]interface{}{
+		"id": id, "path": "/strikes/" + id + ".xml", "protocol": protocol, "year": year,
+		"reference": references,
+	}
+}
+
+// strikeCatalog serves catalog as the strike search result, page by page,
+// or from the start on every request when ignoreOffset is set
+func strikeCatalog(catalog []map[string]interface{}, ignoreOffset bool) *stubClient {
+	return &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+		if path != "/strikes/operations/search" {
+			return nil, fmt.Errorf("unexpected %s %s", method, path)
+		}
+		params := body.(map[string]interface{})
+		offset, limit := params["offset"].(int), params["limit"].(int)
+		if ignoreOffset {
+			offset = 0
+		}
+		var page []interface{}
+		for i := offset; i < offset+limit && i < len(catalog); i++ {
+			page = append(page, catalog[i])
+		}
+		return map[string]interface{}{"strikes": page}, nil
+	}}
+}
+
+func TestStrikeQueryString(t *testing.T) {
+	tests := []struct {
+		query *StrikeQuery
+		want  string
+	}{
+		{NewStrikeQuery(), ""},
+		{NewStrikeQuery().Protocol("http").Severity("high").Text("apache"), "protocol:http severity:high apache"},
+		{NewStrikeQuery().Years(2021, 2021), "year:2021"},
+		{NewStrikeQuery().Years(2019, 2021), ""},
+		{NewStrikeQuery().CVE("cve-2021-44228"), "cve:2021-44228"},
+		{NewStrikeQuery().Keyword("remote code"), `keyword:"remote code"`},
+	}
+	for _, tt := range tests {
+		if got := tt.query.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestStrikeQuery(t *testing.T) {
+	catalog := []map[string]interface{}{
+		strike("a", "http", "2019"),
+		strike("b", "HTTP", "2021", "cve:2021-44228"),
+		strike("c", "dns", "2021"),
+		strike("d", "http", "2022", "bid:44228"),
+		strike("e", "http", "n/a"),
+	}
+	tests := []struct {
+		name         string
+		query        *StrikeQuery
+		ignoreOffset bool
+		want         []string
+		wantOffsets  []int
+	}{
+		{
+			name:        "pages until a short page",
+			query:       NewStrikeQuery().Protocol("http"),
+			want:        []string{"a", "b", "d", "e"},
+			wantOffsets: []int{0, 2, 4},
+		},
+		{
+			name:        "year range",
+			query:       NewStrikeQuery().Years(2020, 2022),
+			want:        []string{"b", "c", "d"},
+			wantOffsets: []int{0, 2, 4},
+		},
+		{
+			name:        "max result stops paging",
+			query:       func() *StrikeQuery { q := NewStrikeQuery().Protocol("http"); q.MaxResult = 2; return q }(),
+			want:        []string{"a", "b"},
+			wantOffsets: []int{0},
+		},
+		{
+			name:        "reference type and value",
+			query:       NewStrikeQuery().CVE("2021-44228"),
+			want:        []string{"b"},
+			wantOffsets: []int{0, 2, 4},
+		},
+		{
+			name:         "offset ignored",
+			query:        NewStrikeQuery().Protocol("http"),
+			ignoreOffset: true,
+			want:         []string{"a", "b"},
+			wantOffsets:  []int{0, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var offsets []int
+			c := strikeCatalog(catalog, tt.ignoreOffset)
+			handle := c.handle
+			c.handle = func(method, path string, body interface{}) (interface{}, error) {
+				offsets = append(offsets, body.(map[string]interface{})["offset"].(int))
+				return handle(method, path, body)
+			}
+			tt.query.PageSize = 2
+			got, err := (&StrikesOps{Client: c}).Query(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, s := range got {
+				ids = append(ids, s.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("Query() = %v, want %v", ids, tt.want)
+			}
+			if !reflect.DeepEqual(offsets, tt.wantOffsets) {
+				t.Errorf("offsets = %v, want %v", offsets, tt.wantOffsets)
+			}
+		})
+	}
+}
+
+func TestStrikeQueryPageLimit(t *testing.T) {
+	// A server that hands out new strikes forever is cut off
+	n := 0
+	c := &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+		n++
+		return map[string]interface{}{"strikes": []interface{}{strike(fmt.Sprint(n), "http", "2020")}}, nil
+	}}
+	q := NewStrikeQuery()
+	q.PageSize = 1
+	_, err := (&StrikesOps{Client: c}).Query(q)
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("more than %d pages", maxStrikePages)) {
+		t.Errorf("Query() error = %v", err)
+	}
+}
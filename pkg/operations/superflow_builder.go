@@ -0,0 +1,408 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SuperflowSpec describes a superflow: its hosts and its flows, each with
+// an ordered list of actions. It is what SuperflowBuilder accumulates and
+// what a YAML description decodes into.
+type SuperflowSpec struct {
+	Name     string     `yaml:"name"`
+	Template string     `yaml:"template,omitempty"`
+	Hosts    []HostSpec `yaml:"hosts"`
+	Flows    []FlowSpec `yaml:"flows"`
+}
+
+// HostSpec is a superflow host. Iface is "origin" or "target".
+type HostSpec struct {
+	Name     string                 `yaml:"name"`
+	Iface    string                 `yaml:"iface"`
+	Hostname string                 `yaml:"hostname,omitempty"`
+	Params   map[string]interface{} `yaml:"params,omitempty"`
+}
+
+// FlowSpec is a flow between two hosts using a protocol such as "httpadv".
+type FlowSpec struct {
+	Protocol string                 `yaml:"protocol"`
+	From     string                 `yaml:"from"`
+	To       string                 `yaml:"to"`
+	Params   map[string]interface{} `yaml:"params,omitempty"`
+	Actions  []ActionSpec           `yaml:"actions,omitempty"`
+}
+
+// ActionSpec is an action of a flow. Source is the host sending it.
+type ActionSpec struct {
+	Source string                 `yaml:"source"`
+	Type   string                 `yaml:"type"`
+	Params map[string]interface{} `yaml:"params,omitempty"`
+}
+
+// LoadSuperflowSpec reads a YAML superflow description from path.
+func LoadSuperflowSpec(path string) (*SuperflowSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &SuperflowSpec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// BuiltFlow records the IDs the chassis assigned to a flow and its actions.
+type BuiltFlow struct {
+	ID        int
+	Protocol  string
+	ActionIDs []int
+}
+
+// BuiltSuperflow is the result of building a superflow.
+type BuiltSuperflow struct {
+	Name  string
+	Flows []BuiltFlow
+}
+
+// SuperflowBuilder assembles a SuperflowSpec fluently:
+//
+//	sf, err := ops.Builder("My HTTP").
+//		Host("Client", "origin", "client%n").
+//		Host("Server", "target", "server%n").
+//		Flow("httpadv", "Client", "Server").
+//		Action("Client", "get_uri", map[string]interface{}{"uri": "/"}).
+//		Action("Server", "response_ok", nil).
+//		Save(true)
+type SuperflowBuilder struct {
+	ops  *SuperflowOps
+	spec SuperflowSpec
+}
+
+// Builder starts a superflow called name.
+func (s *SuperflowOps) Builder(name string) *SuperflowBuilder {
+	return &SuperflowBuilder{ops: s, spec: SuperflowSpec{Name: name}}
+}
+
+// Template bases the superflow on an existing one.
+func (b *SuperflowBuilder) Template(name string) *SuperflowBuilder {
+	b.spec.Template = name
+	return b
+}
+
+// Host adds a host on the "origin" or "target" interface.
+func (b *SuperflowBuilder) Host(name, iface, hostname string) *SuperflowBuilder {
+	b.spec.Hosts = append(b.spec.Hosts, HostSpec{Name: name, Iface: iface, Hostname: hostname})
+	return b
+}
+
+// Flow adds a flow and returns a builder for its actions.
+func (b *SuperflowBuilder) Flow(protocol, from, to string) *FlowBuilder {
+	b.spec.Flows = append(b.spec.Flows, FlowSpec{Protocol: protocol, From: from, To: to})
+	return &FlowBuilder{b: b, index: len(b.spec.Flows) - 1}
+}
+
+// Spec returns the description built so far.
+func (b *SuperflowBuilder) Spec() *SuperflowSpec {
+	return &b.spec
+}
+
+// Save creates the superflow on the chassis.
+func (b *SuperflowBuilder) Save(force bool) (*BuiltSuperflow, error) {
+	return b.ops.Build(&b.spec, force)
+}
+
+// FlowBuilder adds parameters and actions to one flow of a
+// SuperflowBuilder. It keeps the index of the flow rather than a pointer,
+// which adding later flows would leave pointing at a stale copy.
+type FlowBuilder struct {
+	b     *SuperflowBuilder
+	index int
+}
+
+// flow returns the flow being built
+func (f *FlowBuilder) flow() *FlowSpec {
+	return &f.b.spec.Flows[f.index]
+}
+
+// Param sets a flow parameter.
+func (f *FlowBuilder) Param(name string, value interface{}) *FlowBuilder {
+	flow := f.flow()
+	if flow.Params == nil {
+		flow.Params = make(map[string]interface{})
+	}
+	flow.Params[name] = value
+	return f
+}
+
+// Action appends an action sent by source.
+func (f *FlowBuilder) Action(source, typ string, params map[string]interface{}) *FlowBuilder {
+	flow := f.flow()
+	flow.Actions = append(flow.Actions, ActionSpec{Source: source, Type: typ, Params: params})
+	return f
+}
+
+// Flow ends this flow and starts the next one.
+func (f *FlowBuilder) Flow(protocol, from, to string) *FlowBuilder {
+	return f.b.Flow(protocol, from, to)
+}
+
+// Spec returns the description built so far.
+func (f *FlowBuilder) Spec() *SuperflowSpec {
+	return f.b.Spec()
+}
+
+// Save creates the superflow on the chassis.
+func (f *FlowBuilder) Save(force bool) (*BuiltSuperflow, error) {
+	return f.b.Save(force)
+}
+
+// Build creates the superflow described by spec in the working superflow
+// and saves it under spec.Name. Flow parameters are checked against
+// GetFlowChoices, action types against GetActionChoices and action
+// parameters against GetActionInfo before they are written; the IDs
+// assigned by the chassis are returned. If any step fails the working
+// superflow is reset with New, discarding the partial build.
+func (s *SuperflowOps) Build(spec *SuperflowSpec, force bool) (*BuiltSuperflow, error) {
+	if err := spec.check(); err != nil {
+		return nil, err
+	}
+	var template *string
+	if spec.Template != "" {
+		template = &spec.Template
+	}
+	if _, err := s.New(template); err != nil {
+		return nil, fmt.Errorf("new superflow: %w", err)
+	}
+	for _, h := range spec.Hosts {
+		params := map[string]interface{}{"name": h.Name, "iface": h.Iface}
+		if h.Hostname != "" {
+			params["hostname"] = h.Hostname
+		}
+		for k, v := range h.Params {
+			params[k] = v
+		}
+		if _, err := s.AddHost(params, true); err != nil {
+			return nil, s.rollback(fmt.Errorf("add host %s: %w", h.Name, err))
+		}
+	}
+	built := &BuiltSuperflow{Name: spec.Name}
+	for i, f := range spec.Flows {
+		flow, err := s.buildFlow(f)
+		if err != nil {
+			return nil, s.rollback(fmt.Errorf("flow %d (%s): %w", i+1, f.Protocol, err))
+		}
+		built.Flows = append(built.Flows, *flow)
+	}
+	if _, err := s.SaveAs(spec.Name, force); err != nil {
+		return nil, s.rollback(fmt.Errorf("save superflow %s: %w", spec.Name, err))
+	}
+	return built, nil
+}
+
+// rollback resets the working superflow after a failed build so a later
+// save does not pick up the partial one.
+func (s *SuperflowOps) rollback(cause error) error {
+	if _, err := s.New(nil); err != nil {
+		return fmt.Errorf("%w (rollback failed: %v)", cause, err)
+	}
+	return cause
+}
+
+// buildFlow adds the flow with its endpoints only, since the choices of
+// its parameters can only be read once it exists, and writes the
+// parameters after checking them.
+func (s *SuperflowOps) buildFlow(f FlowSpec) (*BuiltFlow, error) {
+	params := map[string]interface{}{"name": f.Protocol, "from": f.From, "to": f.To}
+	flowID, err := s.added("flows", func() error {
+		_, err := s.AddFlow(params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(f.Params) {
+		if err := s.checkFlowChoice(flowID, name, f.Params[name]); err != nil {
+			return nil, err
+		}
+	}
+	if len(f.Params) > 0 {
+		if err := s.Client.Patch("/superflow/flows/"+strconv.Itoa(flowID)+"/params", f.Params); err != nil {
+			return nil, fmt.Errorf("flow parameters: %w", err)
+		}
+	}
+	built := &BuiltFlow{ID: flowID, Protocol: f.Protocol}
+	types, err := s.actionChoices(flowID)
+	if err != nil {
+		return nil, err
+	}
+	for i, a := range f.Actions {
+		if len(types) > 0 && !containsFold(types, a.Type) {
+			return nil, fmt.Errorf("action %d: %s has no action %q (choices: %s)", i+1, f.Protocol, a.Type, strings.Join(types, ", "))
+		}
+		actionID, err := s.added("actions", func() error {
+			_, err := s.AddAction(flowID, a.Type, len(built.ActionIDs)+1, a.Source)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("action %d (%s): %w", i+1, a.Type, err)
+		}
+		if err := s.setActionParams(actionID, a.Params); err != nil {
+			return nil, fmt.Errorf("action %d (%s): %w", i+1, a.Type, err)
+		}
+		built.ActionIDs = append(built.ActionIDs, actionID)
+	}
+	return built, nil
+}
+
+// added runs add and returns the id of the element it appended to the
+// flows or actions list of the working superflow.
+func (s *SuperflowOps) added(list string, add func() error) (int, error) {
+	before, err := s.ids(list)
+	if err != nil {
+		return 0, err
+	}
+	if err := add(); err != nil {
+		return 0, err
+	}
+	after, err := s.ids(list)
+	if err != nil {
+		return 0, err
+	}
+	for id := range after {
+		if !before[id] {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("no new entry in /superflow/%s", list)
+}
+
+func (s *SuperflowOps) ids(list string) (map[int]bool, error) {
+	resp, err := s.Client.Get("/superflow/"+list, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read superflow %s: %w", list, err)
+	}
+	items, _ := resp.([]interface{})
+	ids := make(map[int]bool, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			ids[toInt(m["id"])] = true
+		}
+	}
+	return ids, nil
+}
+
+func (s *SuperflowOps) checkFlowChoice(flowID int, name string, value interface{}) error {
+	resp, err := s.GetFlowChoices(flowID, name)
+	if err != nil {
+		return fmt.Errorf("choices of %s: %w", name, err)
+	}
+	choices := choiceNames(resp, "value", "name")
+	if len(choices) > 0 && !containsFold(choices, toString(value)) {
+		return fmt.Errorf("parameter %s: %q is not one of %s", name, toString(value), strings.Join(choices, ", "))
+	}
+	return nil
+}
+
+func (s *SuperflowOps) actionChoices(flowID int) ([]string, error) {
+	resp, err := s.GetActionChoices(flowID)
+	if err != nil {
+		return nil, fmt.Errorf("action choices: %w", err)
+	}
+	return choiceNames(resp, "type", "name", "id"), nil
+}
+
+// setActionParams checks params against the definition returned by
+// GetActionInfo and writes them to the action.
+func (s *SuperflowOps) setActionParams(actionID int, params map[string]interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	resp, err := s.GetActionInfo(actionID)
+	if err != nil {
+		return fmt.Errorf("action info: %w", err)
+	}
+	info := &ComponentDefinition{Type: "action " + strconv.Itoa(actionID), Params: make(map[string]*ParamDef)}
+	parseParams(info.Params, "", resp)
+	values := make(map[string]interface{}, len(params))
+	for _, name := range sortedKeys(params) {
+		def, err := info.Param(name)
+		if err != nil {
+			return err
+		}
+		v, err := def.Resolve(params[name])
+		if err != nil {
+			return err
+		}
+		values[name] = v
+	}
+	return s.Client.Patch("/superflow/actions/"+strconv.Itoa(actionID)+"/params", values)
+}
+
+func (spec *SuperflowSpec) check() error {
+	if spec.Name == "" {
+		return fmt.Errorf("superflow has no name")
+	}
+	hosts := make(map[string]bool)
+	for _, h := range spec.Hosts {
+		if h.Iface != "origin" && h.Iface != "target" {
+			return fmt.Errorf("host %s: iface must be origin or target, got %q", h.Name, h.Iface)
+		}
+		hosts[h.Name] = true
+	}
+	for i, f := range spec.Flows {
+		if f.Protocol == "" {
+			return fmt.Errorf("flow %d has no protocol", i+1)
+		}
+		for _, h := range []string{f.From, f.To} {
+			if len(spec.Hosts) > 0 && !hosts[h] {
+				return fmt.Errorf("flow %d (%s): unknown host %q", i+1, f.Protocol, h)
+			}
+		}
+		for j, a := range f.Actions {
+			if a.Source != f.From && a.Source != f.To {
+				return fmt.Errorf("flow %d action %d: source %q is not an endpoint of the flow", i+1, j+1, a.Source)
+			}
+		}
+	}
+	return nil
+}
+
+// choiceNames extracts the names of a list of choices given as strings or
+// as objects carrying one of keys.
+func choiceNames(resp interface{}, keys ...string) []string {
+	items, ok := resp.([]interface{})
+	if !ok {
+		if m, isMap := resp.(map[string]interface{}); isMap {
+			items, _ = m["choices"].([]interface{})
+		}
+	}
+	var names []string
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			names = append(names, toString(item))
+			continue
+		}
+		for _, k := range keys {
+			if v := toString(m[k]); v != "" {
+				names = append(names, v)
+				break
+			}
+		}
+	}
+	return names
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package operations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlowBuilderAfterLaterFlows(t *testing.T) {
+	b := (&SuperflowOps{}).Builder("web")
+	first := b.Flow("http", "client", "server")
+	for i := 0; i < 8; i++ {
+		b.Flow("dns", "client", "server")
+	}
+	first.Param("server-hostname", "example.com").Action("client", "get_uri", map[string]interface{}{"uri": "/"})
+	got := b.Spec().Flows[0]
+	want := FlowSpec{
+		Protocol: "http", From: "client", To: "server",
+		Params:  map[string]interface{}{"server-hostname": "example.com"},
+		Actions: []ActionSpec{{Source: "client", Type: "get_uri", Params: map[string]interface{}{"uri": "/"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("first flow = %+v, want %+v", got, want)
+	}
+	if len(b.Spec().Flows[1].Actions) != 0 {
+		t.Errorf("second flow got actions: %+v", b.Spec().Flows[1])
+	}
+}
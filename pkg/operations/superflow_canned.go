@@ -0,0 +1,17 @@
+package operations
+
+import "fmt"
+
+// CannedFlows lists the names of the canned flows shipped with the
+// chassis.
+func (s *SuperflowOps) CannedFlows() ([]string, error) {
+	resp, err := s.GetCannedFlows()
+	if err != nil {
+		return nil, fmt.Errorf("canned flows: %w", err)
+	}
+	items, err := listFrom(resp, "result", "flows", "items")
+	if err != nil {
+		return nil, fmt.Errorf("canned flows: %w", err)
+	}
+	return choiceNames(items, "name", "label"), nil
+}
@@ -0,0 +1,104 @@
+package operations
+
+import (
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"bps-client-go/pkg/pcap"
+)
+
+// CaptureConversion controls how captured conversations become a
+// superflow.
+type CaptureConversion struct {
+	// TCPProtocol and UDPProtocol are the flow protocols used for the
+	// conversations, "tcp" and "udp" by default.
+	TCPProtocol string
+	UDPProtocol string
+	// Action maps a captured message to a superflow action. The default
+	// is DefaultCaptureAction.
+	Action func(c *pcap.Conversation, m pcap.Message, source string) ActionSpec
+	Force  bool
+}
+
+// DefaultCaptureAction replays a message as a raw_message action. Text
+// payloads are sent as is, binary ones hex encoded with a 0x prefix.
+func DefaultCaptureAction(c *pcap.Conversation, m pcap.Message, source string) ActionSpec {
+	data := string(m.Payload)
+	if !utf8.Valid(m.Payload) {
+		data = "0x" + hex.EncodeToString(m.Payload)
+	}
+	return ActionSpec{Source: source, Type: "raw_message", Params: map[string]interface{}{"data": data}}
+}
+
+// CaptureSpec describes a superflow mirroring convs: one host per client
+// and server address, one flow per conversation and one action per
+// message, in capture order. It does not contact the chassis, so the
+// result can be reviewed or edited before Build.
+func CaptureSpec(name string, convs []*pcap.Conversation, opts CaptureConversion) (*SuperflowSpec, error) {
+	if len(convs) == 0 {
+		return nil, fmt.Errorf("no conversations selected")
+	}
+	opts.defaults()
+	spec := &SuperflowSpec{Name: name}
+	hosts := make(map[string]string)
+	host := func(ip, iface, label, prefix string) string {
+		key := iface + "/" + ip
+		if h, ok := hosts[key]; ok {
+			return h
+		}
+		n := 1
+		for _, h := range spec.Hosts {
+			if h.Iface == iface {
+				n++
+			}
+		}
+		h := fmt.Sprintf("%s%d", label, n)
+		hosts[key] = h
+		spec.Hosts = append(spec.Hosts, HostSpec{Name: h, Iface: iface, Hostname: fmt.Sprintf("%s%d", prefix, n)})
+		return h
+	}
+	for _, c := range convs {
+		from := host(c.Client.IP.String(), "origin", "Client", "client")
+		to := host(c.Server.IP.String(), "target", "Server", "server")
+		flow := FlowSpec{Protocol: opts.UDPProtocol, From: from, To: to}
+		if c.Proto == pcap.ProtoTCP {
+			flow.Protocol = opts.TCPProtocol
+		}
+		for _, m := range c.Messages {
+			source := to
+			if m.FromClient {
+				source = from
+			}
+			flow.Actions = append(flow.Actions, opts.Action(c, m, source))
+		}
+		spec.Flows = append(spec.Flows, flow)
+	}
+	return spec, nil
+}
+
+// FromCapture builds a superflow named name from conversations selected
+// from a capture, as returned by pcap.Conversations and pcap.Select. The
+// flows are rebuilt from the addresses, protocols and payloads read
+// locally, see CaptureSpec; the capture file itself is not uploaded, so
+// import it with CaptureOps.ImportCapture if it should be kept on the
+// chassis as well.
+func (s *SuperflowOps) FromCapture(name string, convs []*pcap.Conversation, opts CaptureConversion) (*BuiltSuperflow, error) {
+	spec, err := CaptureSpec(name, convs, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.Build(spec, opts.Force)
+}
+
+func (o *CaptureConversion) defaults() {
+	if o.TCPProtocol == "" {
+		o.TCPProtocol = "tcp"
+	}
+	if o.UDPProtocol == "" {
+		o.UDPProtocol = "udp"
+	}
+	if o.Action == nil {
+		o.Action = DefaultCaptureAction
+	}
+}
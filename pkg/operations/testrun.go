@@ -0,0 +1,98 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPollInterval is how often RunAndWait checks a running test.
+const DefaultPollInterval = 5 * time.Second
+
+// RunAndWait starts the test model and blocks until the run completes,
+// returning an error if it failed or was aborted, see WaitForRun. If ctx is
+// cancelled first the run is stopped and ctx.Err() returned along with the
+// run id.
+func (t *TestModelOps) RunAndWait(ctx context.Context, modelname string, group int, allowMalware bool, interval time.Duration) (int, error) {
+	resp, err := t.Run(modelname, group, allowMalware)
+	if err != nil {
+		return 0, fmt.Errorf("run %s: %w", modelname, err)
+	}
+	m, _ := resp.(map[string]interface{})
+	runID := toInt(m["runid"])
+	if runID == 0 {
+		return 0, fmt.Errorf("run %s: no runid in %#v", modelname, resp)
+	}
+	_, err = t.WaitForRun(ctx, runID, interval)
+	return runID, err
+}
+
+// WaitForRun polls a running test until it completes or is no longer
+// listed, and returns its last reported state. A run whose last state is
+// an error, a failure or an abort returns an error. A run that is no longer
+// listed has finished; its state is then the one reported by the last poll.
+func (t *TestModelOps) WaitForRun(ctx context.Context, runID int, interval time.Duration) (map[string]interface{}, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	topology := &TopologyOps{Client: t.Client}
+	var last map[string]interface{}
+	for {
+		resp, err := topology.GetRunningTest(strconv.Itoa(runID))
+		if err != nil && !isNotFound(err) {
+			return last, fmt.Errorf("poll run %d: %w", runID, err)
+		}
+		if resp == nil {
+			return last, runError(runID, last)
+		}
+		data, ok := resp.(map[string]interface{})
+		if !ok {
+			return last, fmt.Errorf("poll run %d: unexpected response %#v", runID, resp)
+		}
+		last = data
+		if err := runError(runID, data); err != nil {
+			return last, err
+		}
+		if completed, _ := data["completed"].(bool); completed || toInt(data["progress"]) >= 100 {
+			return last, nil
+		}
+		select {
+		case <-ctx.Done():
+			if _, err := t.Stop(runID); err != nil {
+				return last, fmt.Errorf("%w (stop run %d: %v)", ctx.Err(), runID, err)
+			}
+			return last, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// failedStates are the prefixes of the run states that end a run without
+// completing it
+var failedStates = []string{"error", "fail", "abort", "cancel"}
+
+// runError returns an error when state reports a run that ended without
+// completing, with the message the chassis gave if any.
+func runError(runID int, state map[string]interface{}) error {
+	value := strings.ToLower(toString(state["state"]))
+	for _, prefix := range failedStates {
+		if !strings.HasPrefix(value, prefix) {
+			continue
+		}
+		for _, key := range []string{"message", "error", "reason"} {
+			if msg := toString(state[key]); msg != "" {
+				return fmt.Errorf("run %d %s: %s", runID, value, msg)
+			}
+		}
+		return fmt.Errorf("run %d %s", runID, value)
+	}
+	return nil
+}
+
+// isNotFound reports whether err is the client's error for a 404 response,
+// which the chassis returns for a run that is no longer running.
+func isNotFound(err error) bool {
+	return strings.Contains(err.Error(), "failed: 404,")
+}
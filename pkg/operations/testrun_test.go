@@ -0,0 +1,80 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForRun(t *testing.T) {
+	notFound := errors.New("GET failed: 404, no such test")
+	tests := []struct {
+		name    string
+		polls   []interface{}
+		wantErr string
+		want    map[string]interface{}
+	}{
+		{
+			name:  "completed",
+			polls: []interface{}{map[string]interface{}{"progress": 40}, map[string]interface{}{"progress": 100, "state": "completed"}},
+			want:  map[string]interface{}{"progress": 100, "state": "completed"},
+		},
+		{
+			name:  "gone after polling",
+			polls: []interface{}{map[string]interface{}{"progress": 90}, notFound},
+			want:  map[string]interface{}{"progress": 90},
+		},
+		{
+			name:  "gone before polling",
+			polls: []interface{}{nil},
+		},
+		{
+			name:    "aborted",
+			polls:   []interface{}{map[string]interface{}{"progress": 20}, map[string]interface{}{"progress": 20, "state": "ABORTED", "message": "port down"}},
+			wantErr: "run 7 aborted: port down",
+		},
+		{
+			name:    "error before gone",
+			polls:   []interface{}{map[string]interface{}{"state": "error"}, notFound},
+			wantErr: "run 7 error",
+		},
+		{
+			name:    "other failure",
+			polls:   []interface{}{errors.New("GET failed: 500, oops")},
+			wantErr: "poll run 7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := tt.polls
+			c := &stubClient{handle: func(method, path string, body interface{}) (interface{}, error) {
+				if path != "/topology/runningTest/TEST-7" {
+					t.Fatalf("unexpected %s %s", method, path)
+				}
+				next := polls[0]
+				polls = polls[1:]
+				if err, ok := next.(error); ok {
+					return nil, err
+				}
+				return next, nil
+			}}
+			tm := &TestModelOps{Client: c}
+			state, err := tm.WaitForRun(context.Background(), 7, time.Millisecond)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("WaitForRun() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(state, tt.want) {
+				t.Errorf("WaitForRun() = %v, want %v", state, tt.want)
+			}
+		})
+	}
+}
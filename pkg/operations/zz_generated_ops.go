@@ -0,0 +1,1000 @@
+// Code generated by bpsgen from api/bps-api.json. DO NOT EDIT.
+
+package operations
+
+type AdministrationOps struct {
+	Client ClientWrapper
+}
+
+// ImportAtiLicense calls POST /administration/atiLicensing/operations/importAtiLicense.
+func (a *AdministrationOps) ImportAtiLicense(filename string, name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"filename": filename,
+		"name":     name,
+	}
+	return a.Client.Import("/administration/atiLicensing/operations/importAtiLicense", filename, params)
+}
+
+// ConfigPurge calls POST /administration/operations/configPurge.
+func (a *AdministrationOps) ConfigPurge(configPurge interface{}) (interface{}, error) {
+	params := map[string]interface{}{
+		"configPurge": configPurge,
+	}
+	return a.Client.Post("/administration/operations/configPurge", params)
+}
+
+// ExportAllTests calls POST /administration/operations/exportAllTests.
+func (a *AdministrationOps) ExportAllTests(filepath string) error {
+	params := map[string]interface{}{
+		"filepath": filepath,
+	}
+	return a.Client.Export("/administration/operations/exportAllTests", filepath, params)
+}
+
+// ImportAllTests calls POST /administration/operations/importAllTests.
+func (a *AdministrationOps) ImportAllTests(name string, filename string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":     name,
+		"filename": filename,
+		"force":    force,
+	}
+	return a.Client.Import("/administration/operations/importAllTests", filename, params)
+}
+
+type AppProfileOps struct {
+	Client ClientWrapper
+}
+
+// Add calls POST /appProfile/operations/add.
+func (a *AppProfileOps) Add(add []map[string]interface{}) (interface{}, error) {
+	params := map[string]interface{}{
+		"add": add,
+	}
+	return a.Client.Post("/appProfile/operations/add", params)
+}
+
+// Delete calls POST /appProfile/operations/delete.
+func (a *AppProfileOps) Delete(name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"name": name,
+	}
+	return a.Client.Post("/appProfile/operations/delete", params)
+}
+
+// ExportAppProfile calls POST /appProfile/operations/exportAppProfile.
+func (a *AppProfileOps) ExportAppProfile(name string, attachments bool, filepath string) error {
+	params := map[string]interface{}{
+		"name":        name,
+		"attachments": attachments,
+		"filepath":    filepath,
+	}
+	return a.Client.Export("/appProfile/operations/exportAppProfile", filepath, params)
+}
+
+// ImportAppProfile calls POST /appProfile/operations/importAppProfile.
+func (a *AppProfileOps) ImportAppProfile(name string, filename string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":     name,
+		"filename": filename,
+		"force":    force,
+	}
+	return a.Client.Import("/appProfile/operations/importAppProfile", filename, params)
+}
+
+// Load calls POST /appProfile/operations/load.
+func (a *AppProfileOps) Load(template string) (interface{}, error) {
+	params := map[string]interface{}{
+		"template": template,
+	}
+	return a.Client.Post("/appProfile/operations/load", params)
+}
+
+// New calls POST /appProfile/operations/new.
+func (a *AppProfileOps) New(template *string) (interface{}, error) {
+	params := map[string]interface{}{}
+	if template != nil {
+		params["template"] = *template
+	}
+	return a.Client.Post("/appProfile/operations/new", params)
+}
+
+// Remove calls POST /appProfile/operations/remove.
+func (a *AppProfileOps) Remove(superflow []int) (interface{}, error) {
+	params := map[string]interface{}{
+		"superflow": superflow,
+	}
+	return a.Client.Post("/appProfile/operations/remove", params)
+}
+
+// Save calls POST /appProfile/operations/save.
+func (a *AppProfileOps) Save(name *string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"force": force,
+	}
+	if name != nil {
+		params["name"] = *name
+	}
+	return a.Client.Post("/appProfile/operations/save", params)
+}
+
+// SaveAs calls POST /appProfile/operations/saveAs.
+func (a *AppProfileOps) SaveAs(name string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":  name,
+		"force": force,
+	}
+	return a.Client.Post("/appProfile/operations/saveAs", params)
+}
+
+// Search calls POST /appProfile/operations/search.
+func (a *AppProfileOps) Search(searchString string, limit int, sort string, sortorder string) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+	}
+	return a.Client.Post("/appProfile/operations/search", params)
+}
+
+type CaptureOps struct {
+	Client ClientWrapper
+}
+
+// ImportCapture calls POST /capture/operations/importCapture.
+func (c *CaptureOps) ImportCapture(name string, filename string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":  name,
+		"force": force,
+	}
+	return c.Client.Import("/capture/operations/importCapture", filename, params)
+}
+
+// Search calls POST /capture/operations/search.
+func (c *CaptureOps) Search(searchString string, limit int, sort string, sortorder string) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+	}
+	return c.Client.Post("/capture/operations/search", params)
+}
+
+type EvasionProfileOps struct {
+	Client ClientWrapper
+}
+
+// GetStrikeOptions calls POST /evasionProfile/StrikeOptions/operations/getStrikeOptions.
+func (e *EvasionProfileOps) GetStrikeOptions() (interface{}, error) {
+	params := map[string]interface{}{}
+	return e.Client.Post("/evasionProfile/StrikeOptions/operations/getStrikeOptions", params)
+}
+
+// Delete calls POST /evasionProfile/operations/delete.
+func (e *EvasionProfileOps) Delete(name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"name": name,
+	}
+	return e.Client.Post("/evasionProfile/operations/delete", params)
+}
+
+// Load calls POST /evasionProfile/operations/load.
+func (e *EvasionProfileOps) Load(template string) (interface{}, error) {
+	params := map[string]interface{}{
+		"template": template,
+	}
+	return e.Client.Post("/evasionProfile/operations/load", params)
+}
+
+// New calls POST /evasionProfile/operations/new.
+func (e *EvasionProfileOps) New(template *string) (interface{}, error) {
+	params := map[string]interface{}{}
+	if template != nil {
+		params["template"] = *template
+	}
+	return e.Client.Post("/evasionProfile/operations/new", params)
+}
+
+// Save calls POST /evasionProfile/operations/save.
+func (e *EvasionProfileOps) Save(name *string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"force": force,
+	}
+	if name != nil {
+		params["name"] = *name
+	}
+	return e.Client.Post("/evasionProfile/operations/save", params)
+}
+
+// SaveAs calls POST /evasionProfile/operations/saveAs.
+func (e *EvasionProfileOps) SaveAs(name string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":  name,
+		"force": force,
+	}
+	return e.Client.Post("/evasionProfile/operations/saveAs", params)
+}
+
+// Search calls POST /evasionProfile/operations/search.
+func (e *EvasionProfileOps) Search(searchString string, limit int, sort string, sortorder string) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+	}
+	return e.Client.Post("/evasionProfile/operations/search", params)
+}
+
+type LoadProfileOps struct {
+	Client ClientWrapper
+}
+
+// CreateNew calls POST /loadprofile/operations/createNew.
+func (l *LoadProfileOps) CreateNew(loadProfile string) (interface{}, error) {
+	params := map[string]interface{}{
+		"loadProfile": loadProfile,
+	}
+	return l.Client.Post("/loadprofile/operations/createNew", params)
+}
+
+// Delete calls POST /loadprofile/operations/delete.
+func (l *LoadProfileOps) Delete(name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"name": name,
+	}
+	return l.Client.Post("/loadprofile/operations/delete", params)
+}
+
+// Load calls POST /loadprofile/operations/load.
+func (l *LoadProfileOps) Load(template string) (interface{}, error) {
+	params := map[string]interface{}{
+		"template": template,
+	}
+	return l.Client.Post("/loadprofile/operations/load", params)
+}
+
+// Save calls POST /loadprofile/operations/save.
+func (l *LoadProfileOps) Save() (interface{}, error) {
+	params := map[string]interface{}{}
+	return l.Client.Post("/loadprofile/operations/save", params)
+}
+
+// SaveAs calls POST /loadprofile/operations/saveAs.
+func (l *LoadProfileOps) SaveAs(name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"name": name,
+	}
+	return l.Client.Post("/loadprofile/operations/saveAs", params)
+}
+
+// Search calls POST /loadprofile/operations/search.
+func (l *LoadProfileOps) Search(searchString string, limit int, sort string, sortorder string) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+	}
+	return l.Client.Post("/loadprofile/operations/search", params)
+}
+
+// SearchDynamic calls POST /loadprofile/operations/searchDynamic.
+func (l *LoadProfileOps) SearchDynamic(searchString string, limit int, sort string, sortorder string, offset int) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+		"offset":       offset,
+	}
+	return l.Client.Post("/loadprofile/operations/searchDynamic", params)
+}
+
+type NetworkOps struct {
+	Client ClientWrapper
+}
+
+// New calls POST /network/operations/new.
+func (n *NetworkOps) New(template *string) (interface{}, error) {
+	params := map[string]interface{}{}
+	if template != nil {
+		params["template"] = *template
+	}
+	return n.Client.Post("/network/operations/new", params)
+}
+
+// Delete calls POST /network/operations/delete.
+func (n *NetworkOps) Delete(name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"name": name,
+	}
+	return n.Client.Post("/network/operations/delete", params)
+}
+
+// ExportNetwork calls POST /network/operations/exportNetwork.
+func (n *NetworkOps) ExportNetwork(name string, attachments bool, filepath string) error {
+	params := map[string]interface{}{
+		"name":        name,
+		"attachments": attachments,
+		"filepath":    filepath,
+	}
+	return n.Client.Export("/network/operations/exportNetwork", filepath, params)
+}
+
+// ImportNetwork calls POST /network/operations/importNetwork.
+func (n *NetworkOps) ImportNetwork(name string, filename string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":     name,
+		"filename": filename,
+		"force":    force,
+	}
+	return n.Client.Import("/network/operations/importNetwork", filename, params)
+}
+
+// Search calls POST /network/operations/search.
+func (n *NetworkOps) Search(searchString string, userid string, clazz string, sortorder string, sort string, limit int, offset int) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"userid":       userid,
+		"class":        clazz,
+		"sortorder":    sortorder,
+		"sort":         sort,
+		"limit":        limit,
+		"offset":       offset,
+	}
+	return n.Client.Post("/network/operations/search", params)
+}
+
+// Load calls POST /network/operations/load.
+func (n *NetworkOps) Load(template string) (interface{}, error) {
+	params := map[string]interface{}{
+		"template": template,
+	}
+	return n.Client.Post("/network/operations/load", params)
+}
+
+// Save calls POST /network/operations/save.
+func (n *NetworkOps) Save(name *string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"force": force,
+	}
+	if name != nil {
+		params["name"] = *name
+	}
+	return n.Client.Post("/network/operations/save", params)
+}
+
+// SaveAs calls POST /network/operations/saveAs.
+func (n *NetworkOps) SaveAs(name string, regenerateOldStyle bool, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":               name,
+		"regenerateOldStyle": regenerateOldStyle,
+		"force":              force,
+	}
+	return n.Client.Post("/network/operations/saveAs", params)
+}
+
+type RemoteOps struct {
+	Client ClientWrapper
+}
+
+// ConnectChassis calls POST /remote/operations/connectChassis.
+func (r *RemoteOps) ConnectChassis(address string, remote string) (interface{}, error) {
+	params := map[string]interface{}{
+		"address": address,
+		"remote":  remote,
+	}
+	return r.Client.Post("/remote/operations/connectChassis", params)
+}
+
+// DisconnectChassis calls POST /remote/operations/disconnectChassis.
+func (r *RemoteOps) DisconnectChassis(address string, port *int) (interface{}, error) {
+	params := map[string]interface{}{
+		"address": address,
+	}
+	if port != nil {
+		params["port"] = *port
+	}
+	return r.Client.Post("/remote/operations/disconnectChassis", params)
+}
+
+type ReportsOps struct {
+	Client ClientWrapper
+}
+
+// Delete calls POST /reports/operations/delete.
+func (r *ReportsOps) Delete(runid int) (interface{}, error) {
+	params := map[string]interface{}{
+		"runid": runid,
+	}
+	return r.Client.Post("/reports/operations/delete", params)
+}
+
+// ExportReport calls POST /reports/operations/exportReport.
+func (r *ReportsOps) ExportReport(filepath string, runid int, reportType string, sectionIds string, dataType string) error {
+	params := map[string]interface{}{
+		"filepath":   filepath,
+		"runid":      runid,
+		"reportType": reportType,
+		"sectionIds": sectionIds,
+		"dataType":   dataType,
+	}
+	return r.Client.Export("/reports/operations/exportReport", filepath, params)
+}
+
+// GetReportContents calls POST /reports/operations/getReportContents.
+func (r *ReportsOps) GetReportContents(runid int, getTableOfContents bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"runid":              runid,
+		"getTableOfContents": getTableOfContents,
+	}
+	return r.Client.Post("/reports/operations/getReportContents", params)
+}
+
+// GetReportTable calls POST /reports/operations/getReportTable.
+func (r *ReportsOps) GetReportTable(runid int, sectionId string) (interface{}, error) {
+	params := map[string]interface{}{
+		"runid":     runid,
+		"sectionId": sectionId,
+	}
+	return r.Client.Post("/reports/operations/getReportTable", params)
+}
+
+// Search calls POST /reports/operations/search.
+func (r *ReportsOps) Search(searchString string, limit int, sort string, sortorder string) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+	}
+	return r.Client.Post("/reports/operations/search", params)
+}
+
+type ResultsOps struct {
+	Client ClientWrapper
+}
+
+// GetGroups calls POST /results/operations/getGroups.
+func (r *ResultsOps) GetGroups(name string, dynamicEnums bool, includeOutputs bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":           name,
+		"dynamicEnums":   dynamicEnums,
+		"includeOutputs": includeOutputs,
+	}
+	return r.Client.Post("/results/operations/getGroups", params)
+}
+
+// GetHistoricalResultSize calls POST /results/operations/getHistoricalResultSize.
+func (r *ResultsOps) GetHistoricalResultSize(runid int, componentid string, group string) (interface{}, error) {
+	params := map[string]interface{}{
+		"runid":       runid,
+		"componentid": componentid,
+		"group":       group,
+	}
+	return r.Client.Post("/results/operations/getHistoricalResultSize", params)
+}
+
+// GetHistoricalSeries calls POST /results/operations/getHistoricalSeries.
+func (r *ResultsOps) GetHistoricalSeries(runid int, componentid string, dataindex int, group string) (interface{}, error) {
+	params := map[string]interface{}{
+		"runid":       runid,
+		"componentid": componentid,
+		"dataindex":   dataindex,
+		"group":       group,
+	}
+	return r.Client.Post("/results/operations/getHistoricalSeries", params)
+}
+
+type StatisticsOps struct {
+	Client ClientWrapper
+}
+
+// GetStatsDefinitions calls POST /statistics/operations/getStatsDefinitions.
+func (s *StatisticsOps) GetStatsDefinitions() (interface{}, error) {
+	params := map[string]interface{}{}
+	return s.Client.Post("/statistics/operations/getStatsDefinitions", params)
+}
+
+// GetStatisticsByType calls POST /statistics/operations/getStatisticsByType.
+func (s *StatisticsOps) GetStatisticsByType(statType string) (interface{}, error) {
+	params := map[string]interface{}{
+		"type": statType,
+	}
+	return s.Client.Post("/statistics/operations/getStatisticsByType", params)
+}
+
+// GetStatisticValues calls POST /statistics/operations/getStatisticValues.
+func (s *StatisticsOps) GetStatisticValues(componentID string, statisticName string, runID int) (interface{}, error) {
+	params := map[string]interface{}{
+		"componentId":   componentID,
+		"statisticName": statisticName,
+		"runId":         runID,
+	}
+	return s.Client.Post("/statistics/operations/getStatisticValues", params)
+}
+
+// Search calls POST /statistics/operations/search.
+func (s *StatisticsOps) Search(searchString string, limit int, sort string, sortorder string) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+	}
+	return s.Client.Post("/statistics/operations/search", params)
+}
+
+type StrikeListOps struct {
+	Client ClientWrapper
+}
+
+// Add calls POST /strikeList/operations/add.
+func (s *StrikeListOps) Add(strikes []map[string]interface{}, validate bool, toList *string) (interface{}, error) {
+	params := map[string]interface{}{
+		"strike":   strikes,
+		"validate": validate,
+	}
+	if toList != nil {
+		params["toList"] = *toList
+	}
+	return s.Client.Post("/strikeList/operations/add", params)
+}
+
+// Delete calls POST /strikeList/operations/delete.
+func (s *StrikeListOps) Delete(name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"name": name,
+	}
+	return s.Client.Post("/strikeList/operations/delete", params)
+}
+
+// ExportStrikeList calls POST /strikeList/operations/exportStrikeList.
+func (s *StrikeListOps) ExportStrikeList(name string, filepath string) error {
+	params := map[string]interface{}{
+		"name":     name,
+		"filepath": filepath,
+	}
+	return s.Client.Export("/strikeList/operations/exportStrikeList", filepath, params)
+}
+
+// ImportStrikeList calls POST /strikeList/operations/importStrikeList.
+func (s *StrikeListOps) ImportStrikeList(name string, filename string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":     name,
+		"filename": filename,
+		"force":    force,
+	}
+	return s.Client.Import("/strikeList/operations/importStrikeList", filename, params)
+}
+
+// Load calls POST /strikeList/operations/load.
+func (s *StrikeListOps) Load(template string) (interface{}, error) {
+	params := map[string]interface{}{
+		"template": template,
+	}
+	return s.Client.Post("/strikeList/operations/load", params)
+}
+
+// New calls POST /strikeList/operations/new.
+func (s *StrikeListOps) New(template *string) (interface{}, error) {
+	params := map[string]interface{}{}
+	if template != nil {
+		params["template"] = *template
+	}
+	return s.Client.Post("/strikeList/operations/new", params)
+}
+
+// Remove calls POST /strikeList/operations/remove.
+func (s *StrikeListOps) Remove(strikes []map[string]interface{}) (interface{}, error) {
+	params := map[string]interface{}{
+		"strike": strikes,
+	}
+	return s.Client.Post("/strikeList/operations/remove", params)
+}
+
+// Save calls POST /strikeList/operations/save.
+func (s *StrikeListOps) Save(name *string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"force": force,
+	}
+	if name != nil {
+		params["name"] = *name
+	}
+	return s.Client.Post("/strikeList/operations/save", params)
+}
+
+// SaveAs calls POST /strikeList/operations/saveAs.
+func (s *StrikeListOps) SaveAs(name string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":  name,
+		"force": force,
+	}
+	return s.Client.Post("/strikeList/operations/saveAs", params)
+}
+
+// Search calls POST /strikeList/operations/search.
+func (s *StrikeListOps) Search(searchString string, limit int, sort string, sortorder string) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+	}
+	return s.Client.Post("/strikeList/operations/search", params)
+}
+
+type StrikesOps struct {
+	Client ClientWrapper
+}
+
+// Search calls POST /strikes/operations/search.
+func (s *StrikesOps) Search(searchString string, limit int, sort string, sortorder string, offset int) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+		"offset":       offset,
+	}
+	return s.Client.Post("/strikes/operations/search", params)
+}
+
+type SuperflowOps struct {
+	Client ClientWrapper
+}
+
+// AddAction calls POST /superflow/operations/addAction.
+func (s *SuperflowOps) AddAction(flowid int, typ string, actionid int, source string) (interface{}, error) {
+	params := map[string]interface{}{
+		"flowid":   flowid,
+		"type":     typ,
+		"actionid": actionid,
+		"source":   source,
+	}
+	return s.Client.Post("/superflow/operations/addAction", params)
+}
+
+// AddFlow calls POST /superflow/operations/addFlow.
+func (s *SuperflowOps) AddFlow(flowParams map[string]interface{}) (interface{}, error) {
+	params := map[string]interface{}{
+		"flowParams": flowParams,
+	}
+	return s.Client.Post("/superflow/operations/addFlow", params)
+}
+
+// AddHost calls POST /superflow/operations/addHost.
+func (s *SuperflowOps) AddHost(hostParams map[string]interface{}, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"hostParams": hostParams,
+		"force":      force,
+	}
+	return s.Client.Post("/superflow/operations/addHost", params)
+}
+
+// Delete calls POST /superflow/operations/delete.
+func (s *SuperflowOps) Delete(name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"name": name,
+	}
+	return s.Client.Post("/superflow/operations/delete", params)
+}
+
+// ImportResource calls POST /superflow/operations/importResource.
+func (s *SuperflowOps) ImportResource(name string, filename string, force bool, typ string) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":     name,
+		"filename": filename,
+		"force":    force,
+		"type":     typ,
+	}
+	return s.Client.Import("/superflow/operations/importResource", filename, params)
+}
+
+// Load calls POST /superflow/operations/load.
+func (s *SuperflowOps) Load(template string) (interface{}, error) {
+	params := map[string]interface{}{
+		"template": template,
+	}
+	return s.Client.Post("/superflow/operations/load", params)
+}
+
+// New calls POST /superflow/operations/new.
+func (s *SuperflowOps) New(template *string) (interface{}, error) {
+	params := map[string]interface{}{}
+	if template != nil {
+		params["template"] = *template
+	}
+	return s.Client.Post("/superflow/operations/new", params)
+}
+
+// RemoveAction calls POST /superflow/operations/removeAction.
+func (s *SuperflowOps) RemoveAction(id int) (interface{}, error) {
+	params := map[string]interface{}{
+		"id": id,
+	}
+	return s.Client.Post("/superflow/operations/removeAction", params)
+}
+
+// RemoveFlow calls POST /superflow/operations/removeFlow.
+func (s *SuperflowOps) RemoveFlow(id int) (interface{}, error) {
+	params := map[string]interface{}{
+		"id": id,
+	}
+	return s.Client.Post("/superflow/operations/removeFlow", params)
+}
+
+// Save calls POST /superflow/operations/save.
+func (s *SuperflowOps) Save(name *string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"force": force,
+	}
+	if name != nil {
+		params["name"] = *name
+	}
+	return s.Client.Post("/superflow/operations/save", params)
+}
+
+// SaveAs calls POST /superflow/operations/saveAs.
+func (s *SuperflowOps) SaveAs(name string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":  name,
+		"force": force,
+	}
+	return s.Client.Post("/superflow/operations/saveAs", params)
+}
+
+// Search calls POST /superflow/operations/search.
+func (s *SuperflowOps) Search(searchString string, limit int, sort string, sortorder string) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+	}
+	return s.Client.Post("/superflow/operations/search", params)
+}
+
+// GetActionChoices calls POST /superflow/actions/operations/getActionChoices.
+func (s *SuperflowOps) GetActionChoices(id int) (interface{}, error) {
+	params := map[string]interface{}{
+		"id": id,
+	}
+	return s.Client.Post("/superflow/actions/operations/getActionChoices", params)
+}
+
+// GetActionInfo calls POST /superflow/actions/operations/getActionInfo.
+func (s *SuperflowOps) GetActionInfo(id int) (interface{}, error) {
+	params := map[string]interface{}{
+		"id": id,
+	}
+	return s.Client.Post("/superflow/actions/operations/getActionInfo", params)
+}
+
+// GetCannedFlows calls POST /superflow/flows/operations/getCannedFlows.
+func (s *SuperflowOps) GetCannedFlows() (interface{}, error) {
+	params := map[string]interface{}{}
+	return s.Client.Post("/superflow/flows/operations/getCannedFlows", params)
+}
+
+// GetFlowChoices calls POST /superflow/flows/operations/getFlowChoices.
+func (s *SuperflowOps) GetFlowChoices(id int, name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"id":   id,
+		"name": name,
+	}
+	return s.Client.Post("/superflow/flows/operations/getFlowChoices", params)
+}
+
+type TestModelOps struct {
+	Client ClientWrapper
+}
+
+// Load calls POST /testmodel/operations/load.
+func (t *TestModelOps) Load(template string, validate bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"template": template,
+		"validate": validate,
+	}
+	return t.Client.Post("/testmodel/operations/load", params)
+}
+
+// Run calls POST /testmodel/operations/run.
+func (t *TestModelOps) Run(modelname string, group int, allowMalware bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"modelname":    modelname,
+		"group":        group,
+		"allowMalware": allowMalware,
+	}
+	return t.Client.Post("/testmodel/operations/run", params)
+}
+
+// ExportModel calls POST /testmodel/operations/exportModel.
+func (t *TestModelOps) ExportModel(name string, attachments bool, filepath string) error {
+	params := map[string]interface{}{
+		"name":        name,
+		"attachments": attachments,
+		"filepath":    filepath,
+	}
+	return t.Client.Export("/testmodel/operations/exportModel", filepath, params)
+}
+
+// ImportModel calls POST /testmodel/operations/importModel.
+func (t *TestModelOps) ImportModel(name string, filename string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":     name,
+		"filename": filename,
+		"force":    force,
+	}
+	return t.Client.Import("/testmodel/operations/importModel", filename, params)
+}
+
+// Search calls POST /testmodel/operations/search.
+func (t *TestModelOps) Search(searchString string, limit int, sort string, sortorder string) (interface{}, error) {
+	params := map[string]interface{}{
+		"searchString": searchString,
+		"limit":        limit,
+		"sort":         sort,
+		"sortorder":    sortorder,
+	}
+	return t.Client.Post("/testmodel/operations/search", params)
+}
+
+// Add calls POST /testmodel/operations/add.
+func (t *TestModelOps) Add(name string, component string, compType string, active bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":      name,
+		"component": component,
+		"type":      compType,
+		"active":    active,
+	}
+	return t.Client.Post("/testmodel/operations/add", params)
+}
+
+// Save calls POST /testmodel/operations/save.
+func (t *TestModelOps) Save(name string, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":  name,
+		"force": force,
+	}
+	return t.Client.Post("/testmodel/operations/save", params)
+}
+
+// Clone calls POST /testmodel/operations/clone.
+func (t *TestModelOps) Clone(template string, compType string, active bool, label string) (interface{}, error) {
+	params := map[string]interface{}{
+		"template": template,
+		"type":     compType,
+		"active":   active,
+		"label":    label,
+	}
+	return t.Client.Post("/testmodel/operations/clone", params)
+}
+
+// Delete calls POST /testmodel/operations/delete.
+func (t *TestModelOps) Delete(name string) (interface{}, error) {
+	params := map[string]interface{}{
+		"name": name,
+	}
+	return t.Client.Post("/testmodel/operations/delete", params)
+}
+
+// RealTimeStats calls POST /testmodel/operations/realTimeStats.
+func (t *TestModelOps) RealTimeStats(runid int, rtsgroup string, numSeconds int, numDataPoints int, aggregate string, protocol []string) (interface{}, error) {
+	params := map[string]interface{}{
+		"runid":         runid,
+		"rtsgroup":      rtsgroup,
+		"numSeconds":    numSeconds,
+		"numDataPoints": numDataPoints,
+		"aggregate":     aggregate,
+		"protocol":      protocol,
+	}
+	return t.Client.Post("/testmodel/operations/realTimeStats", params)
+}
+
+// Remove calls POST /testmodel/operations/remove.
+func (t *TestModelOps) Remove(id string) (interface{}, error) {
+	params := map[string]interface{}{
+		"id": id,
+	}
+	return t.Client.Post("/testmodel/operations/remove", params)
+}
+
+// Stop calls POST /testmodel/operations/stop.
+func (t *TestModelOps) Stop(runid int) (interface{}, error) {
+	params := map[string]interface{}{
+		"runid": runid,
+	}
+	return t.Client.Post("/testmodel/operations/stop", params)
+}
+
+// TestComponentDefinition calls POST /testmodel/operations/testComponentDefinition.
+func (t *TestModelOps) TestComponentDefinition(name string, dynamicEnums bool, includeOutputs bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"name":           name,
+		"dynamicEnums":   dynamicEnums,
+		"includeOutputs": includeOutputs,
+	}
+	return t.Client.Post("/testmodel/operations/testComponentDefinition", params)
+}
+
+// Validate calls POST /testmodel/operations/validate.
+func (t *TestModelOps) Validate(group string) (interface{}, error) {
+	params := map[string]interface{}{
+		"group": group,
+	}
+	return t.Client.Post("/testmodel/operations/validate", params)
+}
+
+// ListComponents calls GET /testmodel/component.
+func (t *TestModelOps) ListComponents() (interface{}, error) {
+	return t.Client.Get("/testmodel/component", nil, nil)
+}
+
+// GetComponent calls GET /testmodel/component/{componentID}.
+func (t *TestModelOps) GetComponent(componentID string) (interface{}, error) {
+	return t.Client.Get("/testmodel/component/"+componentID, nil, nil)
+}
+
+// SetComponentLabel calls PATCH /testmodel/component/{componentID}.
+func (t *TestModelOps) SetComponentLabel(componentID string, newLabel string) (interface{}, error) {
+	params := map[string]interface{}{
+		"label": newLabel,
+	}
+	err := t.Client.Patch("/testmodel/component/"+componentID, params)
+	return nil, err
+}
+
+// SetComponentActive calls PATCH /testmodel/component/{componentID}.
+func (t *TestModelOps) SetComponentActive(componentID string, active bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"active": active,
+	}
+	err := t.Client.Patch("/testmodel/component/"+componentID, params)
+	return nil, err
+}
+
+type TopologyOps struct {
+	Client ClientWrapper
+}
+
+// GetFanoutModes calls POST /topology/operations/getFanoutModes.
+func (t *TopologyOps) GetFanoutModes(cardId int) (interface{}, error) {
+	params := map[string]interface{}{
+		"cardId": cardId,
+	}
+	return t.Client.Post("/topology/operations/getFanoutModes", params)
+}
+
+// Reserve calls POST /topology/operations/reserve.
+func (t *TopologyOps) Reserve(reservation []map[string]interface{}, force bool) (interface{}, error) {
+	params := map[string]interface{}{
+		"reservation": reservation,
+		"force":       force,
+	}
+	return t.Client.Post("/topology/operations/reserve", params)
+}
+
+// ExportCapture calls POST /topology/operations/exportCapture.
+func (t *TopologyOps) ExportCapture(filepath string, args map[string]interface{}) error {
+	params := map[string]interface{}{
+		"filepath": filepath,
+		"args":     args,
+	}
+	return t.Client.Export("/topology/operations/exportCapture", filepath, params)
+}
+
+// Unreserve calls POST /topology/operations/unreserve.
+func (t *TopologyOps) Unreserve(ports []map[string]interface{}) (interface{}, error) {
+	params := map[string]interface{}{
+		"unreservation": ports,
+	}
+	return t.Client.Post("/topology/operations/unreserve", params)
+}
+
+// GetRunningTest calls GET /topology/runningTest/TEST-{runID}.
+func (t *TopologyOps) GetRunningTest(runID string) (interface{}, error) {
+	return t.Client.Get("/topology/runningTest/TEST-"+runID, nil, nil)
+}
@@ -0,0 +1,333 @@
+package pcap
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Endpoint is an address and port.
+type Endpoint struct {
+	IP   net.IP
+	Port uint16
+}
+
+func (e Endpoint) String() string {
+	return net.JoinHostPort(e.IP.String(), strconv.Itoa(int(e.Port)))
+}
+
+// Message is a run of payload sent in one direction without the other
+// side sending data in between. For UDP every datagram is a message.
+type Message struct {
+	FromClient bool
+	Timestamp  time.Time
+	Payload    []byte
+}
+
+// Conversation is a TCP connection or a UDP exchange between two
+// endpoints. The client is the side that sent the first SYN, or the first
+// packet when the start was not captured.
+type Conversation struct {
+	ID       int
+	Proto    int
+	Client   Endpoint
+	Server   Endpoint
+	VLAN     int
+	Start    time.Time
+	End      time.Time
+	Packets  int
+	Messages []Message
+	// ClientBytes and ServerBytes count IP bytes sent by each side.
+	ClientBytes int
+	ServerBytes int
+	// Retransmissions counts TCP segments repeating already seen data;
+	// they are left out of Messages. Segments arriving ahead of a missing
+	// one are held back and added in sequence order once the gap is
+	// filled.
+	Retransmissions int
+	Resets          int
+	Complete        bool
+	// SYN, SYNACK and Established follow the TCP handshake.
+	SYN         bool
+	SYNACK      bool
+	Established bool
+
+	nextSeq map[bool]uint32
+	seqSet  map[bool]bool
+	held    map[bool][]heldSegment
+}
+
+// heldSegment is TCP data received ahead of the next expected sequence
+// number.
+type heldSegment struct {
+	seq     uint32
+	ts      time.Time
+	payload []byte
+	fin     bool
+}
+
+// Protocol returns "tcp" or "udp".
+func (c *Conversation) Protocol() string {
+	if c.Proto == ProtoTCP {
+		return "tcp"
+	}
+	return "udp"
+}
+
+// PayloadBytes returns the payload sent by the client and by the server.
+func (c *Conversation) PayloadBytes() (client, server int) {
+	for _, m := range c.Messages {
+		if m.FromClient {
+			client += len(m.Payload)
+		} else {
+			server += len(m.Payload)
+		}
+	}
+	return client, server
+}
+
+func (c *Conversation) String() string {
+	client, server := c.PayloadBytes()
+	return fmt.Sprintf("#%d %s %s -> %s, %d packets, %d messages, %d/%d payload bytes, %v",
+		c.ID, c.Protocol(), c.Client, c.Server, c.Packets, len(c.Messages), client, server, c.End.Sub(c.Start))
+}
+
+type flowKey struct {
+	proto int
+	a, b  string
+}
+
+func keyOf(s *Segment) flowKey {
+	src := Endpoint{s.Src, s.SrcPort}.String()
+	dst := Endpoint{s.Dst, s.DstPort}.String()
+	if src < dst {
+		return flowKey{s.Proto, src, dst}
+	}
+	return flowKey{s.Proto, dst, src}
+}
+
+// Tracker groups decoded segments into conversations.
+type Tracker struct {
+	// Timeout starts a new conversation between the same endpoints after
+	// that much silence; zero never splits.
+	Timeout time.Duration
+	open    map[flowKey]*Conversation
+	all     []*Conversation
+}
+
+// NewTracker creates an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{open: make(map[flowKey]*Conversation)}
+}
+
+// Add assigns a segment captured at ts to its conversation and returns
+// the conversation.
+func (t *Tracker) Add(ts time.Time, s *Segment) *Conversation {
+	key := keyOf(s)
+	c, ok := t.open[key]
+	newTCP := s.Proto == ProtoTCP && s.Has(FlagSYN) && !s.Has(FlagACK)
+	if ok && (c.Complete && newTCP || t.Timeout > 0 && ts.Sub(c.End) > t.Timeout) {
+		ok = false
+	}
+	if !ok {
+		c = &Conversation{
+			ID:      len(t.all) + 1,
+			Proto:   s.Proto,
+			Client:  Endpoint{s.Src, s.SrcPort},
+			Server:  Endpoint{s.Dst, s.DstPort},
+			VLAN:    s.VLAN,
+			Start:   ts,
+			nextSeq: make(map[bool]uint32),
+			seqSet:  make(map[bool]bool),
+			held:    make(map[bool][]heldSegment),
+		}
+		if s.Proto == ProtoTCP && s.Has(FlagSYN|FlagACK) {
+			// Capture started after the SYN; the SYN/ACK comes from the server
+			c.Client, c.Server = c.Server, c.Client
+		}
+		t.open[key] = c
+		t.all = append(t.all, c)
+	}
+	fromClient := s.Src.Equal(c.Client.IP) && s.SrcPort == c.Client.Port
+	c.Packets++
+	c.End = ts
+	if fromClient {
+		c.ClientBytes += s.Length
+	} else {
+		c.ServerBytes += s.Length
+	}
+	if s.Proto == ProtoTCP {
+		c.addTCP(ts, s, fromClient)
+	} else if len(s.Payload) > 0 {
+		c.Messages = append(c.Messages, Message{FromClient: fromClient, Timestamp: ts, Payload: s.Payload})
+	}
+	return c
+}
+
+func (c *Conversation) addTCP(ts time.Time, s *Segment, fromClient bool) {
+	if s.Has(FlagRST) {
+		c.Resets++
+		c.Complete = true
+	}
+	switch {
+	case s.Has(FlagSYN | FlagACK):
+		c.SYNACK = true
+	case s.Has(FlagSYN):
+		c.SYN = true
+	case fromClient && c.SYNACK && s.Has(FlagACK) && !s.Has(FlagRST):
+		c.Established = true
+	}
+	seq := s.Seq
+	if s.Has(FlagSYN) {
+		seq++
+		c.nextSeq[fromClient], c.seqSet[fromClient] = seq, true
+	}
+	if s.Has(FlagFIN) {
+		c.Complete = true
+	}
+	if len(s.Payload) > 0 {
+		if !c.seqSet[fromClient] {
+			c.nextSeq[fromClient], c.seqSet[fromClient] = seq, true
+		}
+		// Serial number arithmetic handles sequence wrap
+		d := int32(seq - c.nextSeq[fromClient])
+		switch {
+		case d > 0:
+			c.hold(fromClient, heldSegment{seq: seq, ts: ts, payload: append([]byte(nil), s.Payload...), fin: s.Has(FlagFIN)})
+			return
+		case int(-d) >= len(s.Payload):
+			c.Retransmissions++
+		default:
+			c.appendPayload(fromClient, ts, s.Payload[-d:])
+			c.nextSeq[fromClient] = seq + uint32(len(s.Payload))
+		}
+	}
+	if s.Has(FlagFIN) {
+		// Count the FIN once, a retransmitted FIN does not advance
+		if end := seq + uint32(len(s.Payload)); c.seqSet[fromClient] && end == c.nextSeq[fromClient] {
+			c.nextSeq[fromClient] = end + 1
+		}
+	}
+	c.drain(fromClient)
+}
+
+func (c *Conversation) appendPayload(fromClient bool, ts time.Time, payload []byte) {
+	n := len(c.Messages)
+	if n > 0 && c.Messages[n-1].FromClient == fromClient {
+		c.Messages[n-1].Payload = append(c.Messages[n-1].Payload, payload...)
+		return
+	}
+	c.Messages = append(c.Messages, Message{
+		FromClient: fromClient,
+		Timestamp:  ts,
+		Payload:    append([]byte(nil), payload...),
+	})
+}
+
+// hold keeps a segment received ahead of the expected data; a second copy
+// of a held segment counts as a retransmission.
+func (c *Conversation) hold(fromClient bool, h heldSegment) {
+	for _, other := range c.held[fromClient] {
+		if other.seq == h.seq && len(other.payload) >= len(h.payload) {
+			c.Retransmissions++
+			return
+		}
+	}
+	c.held[fromClient] = append(c.held[fromClient], h)
+}
+
+// drain adds the held segments the expected data has caught up with.
+func (c *Conversation) drain(fromClient bool) {
+	for {
+		next := c.nextSeq[fromClient]
+		held := c.held[fromClient]
+		i := -1
+		for j, h := range held {
+			if int32(h.seq-next) <= 0 {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			return
+		}
+		h := held[i]
+		c.held[fromClient] = append(held[:i], held[i+1:]...)
+		end := h.seq + uint32(len(h.payload))
+		if d := int(int32(next - h.seq)); d < len(h.payload) {
+			c.appendPayload(fromClient, h.ts, h.payload[d:])
+			c.nextSeq[fromClient] = end
+		}
+		if h.fin && end == c.nextSeq[fromClient] {
+			c.nextSeq[fromClient] = end + 1
+		}
+	}
+}
+
+// flush adds the data still held behind segments that were never
+// captured, skipping each gap in sequence order.
+func (c *Conversation) flush() {
+	for _, fromClient := range []bool{true, false} {
+		for len(c.held[fromClient]) > 0 {
+			next := c.nextSeq[fromClient]
+			first := c.held[fromClient][0]
+			for _, h := range c.held[fromClient][1:] {
+				if int32(h.seq-next) < int32(first.seq-next) {
+					first = h
+				}
+			}
+			c.nextSeq[fromClient] = first.seq
+			c.drain(fromClient)
+		}
+	}
+}
+
+// Conversations returns the conversations seen so far in order of their
+// first packet. TCP data still held back behind a segment that was never
+// captured is added to Messages across the gap.
+func (t *Tracker) Conversations() []*Conversation {
+	for _, c := range t.all {
+		c.flush()
+	}
+	out := append([]*Conversation(nil), t.all...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
+
+// Conversations reads the capture at path and returns its TCP and UDP
+// conversations. Packets that are not TCP or UDP are skipped.
+func Conversations(path string) ([]*Conversation, error) {
+	packets, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t := NewTracker()
+	for _, p := range packets {
+		s, err := Decode(p)
+		if err != nil {
+			continue
+		}
+		t.Add(p.Timestamp, s)
+	}
+	return t.Conversations(), nil
+}
+
+// Select returns the conversations with the given IDs, in the order of
+// ids.
+func Select(convs []*Conversation, ids ...int) ([]*Conversation, error) {
+	byID := make(map[int]*Conversation, len(convs))
+	for _, c := range convs {
+		byID[c.ID] = c
+	}
+	out := make([]*Conversation, 0, len(ids))
+	for _, id := range ids {
+		c, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("no conversation #%d", id)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
@@ -0,0 +1,188 @@
+package pcap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// IP protocol numbers
+const (
+	ProtoTCP = 6
+	ProtoUDP = 17
+)
+
+// TCP flags
+const (
+	FlagFIN = 0x01
+	FlagSYN = 0x02
+	FlagRST = 0x04
+	FlagPSH = 0x08
+	FlagACK = 0x10
+)
+
+// Segment is the transport view of a decoded packet.
+type Segment struct {
+	Src, Dst         net.IP
+	SrcPort, DstPort uint16
+	Proto            int
+	VLAN             int
+	// TCP only
+	Seq, Ack uint32
+	Flags    uint8
+	Payload  []byte
+	// Length is the IP length of the packet, which counts the headers
+	// and, unlike len(Payload), survives a snap length.
+	Length int
+}
+
+// Has reports whether all of the TCP flags are set.
+func (s *Segment) Has(flags uint8) bool {
+	return s.Flags&flags == flags
+}
+
+// ErrNotTransport is returned by Decode for packets that are not TCP or
+// UDP over IP, such as ARP or IP fragments other than the first.
+var ErrNotTransport = fmt.Errorf("not a TCP or UDP packet")
+
+// Decode decodes the link, network and transport headers of p.
+func Decode(p *Packet) (*Segment, error) {
+	s := &Segment{}
+	data := p.Data
+	var etherType uint16
+	switch p.LinkType {
+	case LinkEthernet:
+		if len(data) < 14 {
+			return nil, fmt.Errorf("short ethernet header")
+		}
+		etherType, data = binary.BigEndian.Uint16(data[12:14]), data[14:]
+		for etherType == 0x8100 || etherType == 0x88a8 {
+			if len(data) < 4 {
+				return nil, fmt.Errorf("short VLAN tag")
+			}
+			if s.VLAN == 0 {
+				s.VLAN = int(binary.BigEndian.Uint16(data[0:2]) & 0x0fff)
+			}
+			etherType, data = binary.BigEndian.Uint16(data[2:4]), data[4:]
+		}
+	case LinkLinuxSLL:
+		if len(data) < 16 {
+			return nil, fmt.Errorf("short linux cooked header")
+		}
+		etherType, data = binary.BigEndian.Uint16(data[14:16]), data[16:]
+	case LinkNull:
+		if len(data) < 4 {
+			return nil, fmt.Errorf("short loopback header")
+		}
+		// The family is in host order of the capturing machine
+		family := binary.LittleEndian.Uint32(data[0:4])
+		if family > 0xffff {
+			family = binary.BigEndian.Uint32(data[0:4])
+		}
+		etherType, data = 0x0800, data[4:]
+		if family != 2 {
+			etherType = 0x86dd
+		}
+	case LinkRaw, LinkIPv4, LinkIPv6:
+		if len(data) == 0 {
+			return nil, fmt.Errorf("empty packet")
+		}
+		etherType = 0x0800
+		if data[0]>>4 == 6 {
+			etherType = 0x86dd
+		}
+	default:
+		return nil, fmt.Errorf("unsupported link type %d", p.LinkType)
+	}
+	var err error
+	switch etherType {
+	case 0x0800:
+		data, err = s.ipv4(data)
+	case 0x86dd:
+		data, err = s.ipv6(data)
+	default:
+		return nil, ErrNotTransport
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch s.Proto {
+	case ProtoTCP:
+		if len(data) < 20 {
+			return nil, fmt.Errorf("short TCP header")
+		}
+		off := int(data[12]>>4) * 4
+		if off < 20 || off > len(data) {
+			return nil, fmt.Errorf("bad TCP data offset %d", off)
+		}
+		s.SrcPort = binary.BigEndian.Uint16(data[0:2])
+		s.DstPort = binary.BigEndian.Uint16(data[2:4])
+		s.Seq = binary.BigEndian.Uint32(data[4:8])
+		s.Ack = binary.BigEndian.Uint32(data[8:12])
+		s.Flags = data[13]
+		s.Payload = data[off:]
+	case ProtoUDP:
+		if len(data) < 8 {
+			return nil, fmt.Errorf("short UDP header")
+		}
+		s.SrcPort = binary.BigEndian.Uint16(data[0:2])
+		s.DstPort = binary.BigEndian.Uint16(data[2:4])
+		s.Payload = data[8:]
+		if l := int(binary.BigEndian.Uint16(data[4:6])); l >= 8 && l-8 < len(s.Payload) {
+			s.Payload = s.Payload[:l-8]
+		}
+	default:
+		return nil, ErrNotTransport
+	}
+	return s, nil
+}
+
+func (s *Segment) ipv4(data []byte) ([]byte, error) {
+	if len(data) < 20 {
+		return nil, fmt.Errorf("short IPv4 header")
+	}
+	ihl := int(data[0]&0x0f) * 4
+	if ihl < 20 || ihl > len(data) {
+		return nil, fmt.Errorf("bad IPv4 header length %d", ihl)
+	}
+	if binary.BigEndian.Uint16(data[6:8])&0x1fff != 0 {
+		return nil, ErrNotTransport
+	}
+	s.Length = int(binary.BigEndian.Uint16(data[2:4]))
+	s.Proto = int(data[9])
+	s.Src = net.IP(append([]byte(nil), data[12:16]...))
+	s.Dst = net.IP(append([]byte(nil), data[16:20]...))
+	end := len(data)
+	if s.Length >= ihl && s.Length < end {
+		// Drop ethernet padding
+		end = s.Length
+	}
+	return data[ihl:end], nil
+}
+
+func (s *Segment) ipv6(data []byte) ([]byte, error) {
+	if len(data) < 40 {
+		return nil, fmt.Errorf("short IPv6 header")
+	}
+	s.Length = 40 + int(binary.BigEndian.Uint16(data[4:6]))
+	s.Src = net.IP(append([]byte(nil), data[8:24]...))
+	s.Dst = net.IP(append([]byte(nil), data[24:40]...))
+	next := int(data[6])
+	rest := data[40:]
+	if s.Length < len(data) {
+		rest = data[40:s.Length]
+	}
+	// Skip hop-by-hop, routing and destination options headers
+	for next == 0 || next == 43 || next == 60 {
+		if len(rest) < 8 {
+			return nil, fmt.Errorf("short IPv6 extension header")
+		}
+		l := (int(rest[1]) + 1) * 8
+		if l > len(rest) {
+			return nil, fmt.Errorf("bad IPv6 extension header length")
+		}
+		next, rest = int(rest[0]), rest[l:]
+	}
+	s.Proto = next
+	return rest, nil
+}
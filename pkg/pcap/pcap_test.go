@@ -0,0 +1,416 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+type frameSpec struct {
+	vlan       int
+	src, dst   string
+	sport      uint16
+	dport      uint16
+	proto      int
+	seq        uint32
+	flags      uint8
+	payload    string
+	fragmented bool
+}
+
+// ethernetFrame builds an Ethernet/IPv4 frame carrying a TCP or UDP
+// packet.
+func ethernetFrame(f frameSpec) []byte {
+	var b []byte
+	b = append(b, 0, 1, 2, 3, 4, 5, 0, 1, 2, 3, 4, 6)
+	if f.vlan > 0 {
+		b = binary.BigEndian.AppendUint16(b, 0x8100)
+		b = binary.BigEndian.AppendUint16(b, uint16(f.vlan))
+	}
+	b = binary.BigEndian.AppendUint16(b, 0x0800)
+	var l4 []byte
+	if f.proto == ProtoTCP {
+		l4 = binary.BigEndian.AppendUint16(l4, f.sport)
+		l4 = binary.BigEndian.AppendUint16(l4, f.dport)
+		l4 = binary.BigEndian.AppendUint32(l4, f.seq)
+		l4 = binary.BigEndian.AppendUint32(l4, 0)
+		l4 = append(l4, 5<<4, f.flags, 0xff, 0xff, 0, 0, 0, 0)
+	} else {
+		l4 = binary.BigEndian.AppendUint16(l4, f.sport)
+		l4 = binary.BigEndian.AppendUint16(l4, f.dport)
+		l4 = binary.BigEndian.AppendUint16(l4, uint16(8+len(f.payload)))
+		l4 = append(l4, 0, 0)
+	}
+	l4 = append(l4, f.payload...)
+	ip := []byte{0x45, 0}
+	ip = binary.BigEndian.AppendUint16(ip, uint16(20+len(l4)))
+	frag := uint16(0)
+	if f.fragmented {
+		frag = 100
+	}
+	ip = append(ip, 0, 0)
+	ip = binary.BigEndian.AppendUint16(ip, frag)
+	ip = append(ip, 64, byte(f.proto), 0, 0)
+	ip = append(ip, net.ParseIP(f.src).To4()...)
+	ip = append(ip, net.ParseIP(f.dst).To4()...)
+	return append(append(b, ip...), l4...)
+}
+
+func pcapFile(order binary.AppendByteOrder, nano bool, linkType int, ts []time.Time, frames [][]byte) []byte {
+	magic := uint32(magicMicro)
+	if nano {
+		magic = magicNano
+	}
+	b := order.AppendUint32(nil, magic)
+	b = order.AppendUint16(b, 2)
+	b = order.AppendUint16(b, 4)
+	b = order.AppendUint32(b, 0)
+	b = order.AppendUint32(b, 0)
+	b = order.AppendUint32(b, 65535)
+	b = order.AppendUint32(b, uint32(linkType))
+	for i, f := range frames {
+		frac := ts[i].Nanosecond() / 1000
+		if nano {
+			frac = ts[i].Nanosecond()
+		}
+		b = order.AppendUint32(b, uint32(ts[i].Unix()))
+		b = order.AppendUint32(b, uint32(frac))
+		b = order.AppendUint32(b, uint32(len(f)))
+		b = order.AppendUint32(b, uint32(len(f)))
+		b = append(b, f...)
+	}
+	return b
+}
+
+func pcapngBlock(order binary.AppendByteOrder, typ uint32, body []byte) []byte {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	total := uint32(12 + len(body))
+	b := order.AppendUint32(nil, typ)
+	b = order.AppendUint32(b, total)
+	b = append(b, body...)
+	return order.AppendUint32(b, total)
+}
+
+// pcapngFile writes one section with one interface whose timestamps are in
+// nanoseconds.
+func pcapngFile(order binary.AppendByteOrder, linkType int, ts []time.Time, frames [][]byte) []byte {
+	shb := order.AppendUint32(nil, byteOrderBOM)
+	shb = order.AppendUint16(shb, 1)
+	shb = order.AppendUint16(shb, 0)
+	shb = append(shb, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)
+	b := pcapngBlock(order, blockSHB, shb)
+	idb := order.AppendUint16(nil, uint16(linkType))
+	idb = order.AppendUint16(idb, 0)
+	idb = order.AppendUint32(idb, 65535)
+	idb = order.AppendUint16(idb, 9)
+	idb = order.AppendUint16(idb, 1)
+	idb = append(idb, 9, 0, 0, 0, 0, 0, 0, 0)
+	b = append(b, pcapngBlock(order, blockIDB, idb)...)
+	for i, f := range frames {
+		n := uint64(ts[i].UnixNano())
+		epb := order.AppendUint32(nil, 0)
+		epb = order.AppendUint32(epb, uint32(n>>32))
+		epb = order.AppendUint32(epb, uint32(n))
+		epb = order.AppendUint32(epb, uint32(len(f)))
+		epb = order.AppendUint32(epb, uint32(len(f)))
+		epb = append(epb, f...)
+		b = append(b, pcapngBlock(order, blockEPB, epb)...)
+	}
+	return b
+}
+
+func TestReader(t *testing.T) {
+	frames := [][]byte{
+		ethernetFrame(frameSpec{src: "10.0.0.1", dst: "10.0.0.2", sport: 1000, dport: 80, proto: ProtoTCP, flags: FlagSYN}),
+		ethernetFrame(frameSpec{src: "10.0.0.2", dst: "10.0.0.1", sport: 53, dport: 2000, proto: ProtoUDP, payload: "odd"}),
+	}
+	ts := []time.Time{
+		time.Unix(1700000000, 123456000).UTC(),
+		time.Unix(1700000001, 5000).UTC(),
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"pcap little endian", pcapFile(binary.LittleEndian, false, LinkEthernet, ts, frames)},
+		{"pcap big endian nanoseconds", pcapFile(binary.BigEndian, true, LinkEthernet, ts, frames)},
+		{"pcapng little endian", pcapngFile(binary.LittleEndian, LinkEthernet, ts, frames)},
+		{"pcapng big endian", pcapngFile(binary.BigEndian, LinkEthernet, ts, frames)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd, err := NewReader(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range frames {
+				p, err := rd.Next()
+				if err != nil {
+					t.Fatalf("packet %d: %v", i+1, err)
+				}
+				if p.LinkType != LinkEthernet || p.OrigLen != len(want) || !bytes.Equal(p.Data, want) {
+					t.Errorf("packet %d: link type %d, length %d, data %x", i+1, p.LinkType, p.OrigLen, p.Data)
+				}
+				if !p.Timestamp.Equal(ts[i]) {
+					t.Errorf("packet %d: timestamp %v, want %v", i+1, p.Timestamp, ts[i])
+				}
+			}
+			if _, err := rd.Next(); err != io.EOF {
+				t.Errorf("after the last packet got %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestReaderRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"unknown magic", bytes.Repeat([]byte{0x42}, 24)},
+		{"short pcap header", []byte{0xd4, 0xc3, 0xb2, 0xa1, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewReader(bytes.NewReader(tt.data)); err == nil {
+				t.Error("NewReader succeeded")
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		frame   frameSpec
+		wantErr error
+	}{
+		{name: "tcp", frame: frameSpec{src: "10.0.0.1", dst: "10.0.0.2", sport: 1000, dport: 80, proto: ProtoTCP, seq: 7, flags: FlagACK | FlagPSH, payload: "GET /"}},
+		{name: "udp in vlan", frame: frameSpec{vlan: 42, src: "10.0.0.2", dst: "10.0.0.1", sport: 53, dport: 2000, proto: ProtoUDP, payload: "answer"}},
+		{name: "later fragment", frame: frameSpec{src: "10.0.0.1", dst: "10.0.0.2", proto: ProtoUDP, fragmented: true}, wantErr: ErrNotTransport},
+		{name: "icmp", frame: frameSpec{src: "10.0.0.1", dst: "10.0.0.2", proto: 1}, wantErr: ErrNotTransport},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := ethernetFrame(tt.frame)
+			// Ethernet padding must not end up in the payload
+			data = append(data, 0, 0, 0, 0)
+			s, err := Decode(&Packet{LinkType: LinkEthernet, Data: data})
+			if tt.wantErr != nil {
+				if err != tt.wantErr {
+					t.Fatalf("Decode error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := tt.frame
+			if !s.Src.Equal(net.ParseIP(f.src)) || !s.Dst.Equal(net.ParseIP(f.dst)) || s.SrcPort != f.sport || s.DstPort != f.dport {
+				t.Errorf("endpoints %v:%d -> %v:%d", s.Src, s.SrcPort, s.Dst, s.DstPort)
+			}
+			if s.Proto != f.proto || s.VLAN != f.vlan || s.Seq != f.seq || s.Flags != f.flags {
+				t.Errorf("proto %d, vlan %d, seq %d, flags %x", s.Proto, s.VLAN, s.Seq, s.Flags)
+			}
+			if string(s.Payload) != f.payload {
+				t.Errorf("payload %q, want %q", s.Payload, f.payload)
+			}
+		})
+	}
+}
+
+func TestTrackerReassembly(t *testing.T) {
+	type seg struct {
+		fromClient bool
+		seq        uint32
+		flags      uint8
+		payload    string
+	}
+	handshake := []seg{
+		{true, 99, FlagSYN, ""},
+		{false, 499, FlagSYN | FlagACK, ""},
+		{true, 100, FlagACK, ""},
+	}
+	tests := []struct {
+		name            string
+		segs            []seg
+		wantMessages    []string
+		retransmissions int
+	}{
+		{
+			name:         "in order",
+			segs:         []seg{{true, 100, FlagACK, "GET "}, {true, 104, FlagACK, "/"}, {false, 500, FlagACK, "OK"}},
+			wantMessages: []string{"GET /", "OK"},
+		},
+		{
+			name:         "reordered",
+			segs:         []seg{{true, 104, FlagACK, "/"}, {true, 100, FlagACK, "GET "}, {false, 500, FlagACK, "OK"}},
+			wantMessages: []string{"GET /", "OK"},
+		},
+		{
+			name:         "reordered across three segments",
+			segs:         []seg{{true, 106, FlagACK, "c"}, {true, 105, FlagACK, "b"}, {true, 100, FlagACK, "GET a"}},
+			wantMessages: []string{"GET abc"},
+		},
+		{
+			name:            "retransmission",
+			segs:            []seg{{true, 100, FlagACK, "GET "}, {true, 100, FlagACK, "GET "}, {true, 104, FlagACK, "/"}},
+			wantMessages:    []string{"GET /"},
+			retransmissions: 1,
+		},
+		{
+			name:         "overlapping retransmission",
+			segs:         []seg{{true, 100, FlagACK, "GET "}, {true, 102, FlagACK, "T /"}},
+			wantMessages: []string{"GET /"},
+		},
+		{
+			name:            "held segment sent twice",
+			segs:            []seg{{true, 104, FlagACK, "/"}, {true, 104, FlagACK, "/"}, {true, 100, FlagACK, "GET "}},
+			wantMessages:    []string{"GET /"},
+			retransmissions: 1,
+		},
+		{
+			name:         "gap never filled",
+			segs:         []seg{{true, 100, FlagACK, "GET "}, {true, 110, FlagACK, "tail"}},
+			wantMessages: []string{"GET tail"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTracker()
+			start := time.Unix(1700000000, 0)
+			for i, s := range append(append([]seg{}, handshake...), tt.segs...) {
+				f := frameSpec{src: "10.0.0.1", dst: "10.0.0.2", sport: 1000, dport: 80}
+				if !s.fromClient {
+					f = frameSpec{src: "10.0.0.2", dst: "10.0.0.1", sport: 80, dport: 1000}
+				}
+				f.proto, f.seq, f.flags, f.payload = ProtoTCP, s.seq, s.flags, s.payload
+				seg, err := Decode(&Packet{LinkType: LinkEthernet, Data: ethernetFrame(f)})
+				if err != nil {
+					t.Fatal(err)
+				}
+				tr.Add(start.Add(time.Duration(i)*time.Millisecond), seg)
+			}
+			convs := tr.Conversations()
+			if len(convs) != 1 {
+				t.Fatalf("got %d conversations, want 1", len(convs))
+			}
+			c := convs[0]
+			var got []string
+			for _, m := range c.Messages {
+				got = append(got, string(m.Payload))
+			}
+			if len(got) != len(tt.wantMessages) {
+				t.Fatalf("messages %q, want %q", got, tt.wantMessages)
+			}
+			for i := range got {
+				if got[i] != tt.wantMessages[i] {
+					t.Errorf("messages %q, want %q", got, tt.wantMessages)
+					break
+				}
+			}
+			if c.Retransmissions != tt.retransmissions {
+				t.Errorf("retransmissions %d, want %d", c.Retransmissions, tt.retransmissions)
+			}
+			if !c.Established || c.Client.Port != 1000 {
+				t.Errorf("established %v, client %v", c.Established, c.Client)
+			}
+		})
+	}
+}
+
+func TestTrackerSequenceWrap(t *testing.T) {
+	tr := NewTracker()
+	start := time.Unix(1700000000, 0)
+	segs := []struct {
+		seq     uint32
+		flags   uint8
+		payload string
+	}{
+		{0xfffffffd, FlagSYN, ""},
+		{0x00000000, FlagACK, "cd"},
+		{0xfffffffe, FlagACK, "ab"},
+	}
+	for i, s := range segs {
+		f := frameSpec{src: "10.0.0.1", dst: "10.0.0.2", sport: 1000, dport: 80, proto: ProtoTCP, seq: s.seq, flags: s.flags, payload: s.payload}
+		seg, err := Decode(&Packet{LinkType: LinkEthernet, Data: ethernetFrame(f)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr.Add(start.Add(time.Duration(i)*time.Millisecond), seg)
+	}
+	c := tr.Conversations()[0]
+	if len(c.Messages) != 1 || string(c.Messages[0].Payload) != "abcd" {
+		t.Errorf("messages %v, want one message abcd", c.Messages)
+	}
+}
+
+func TestOriginFromFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want Origin
+		ok   bool
+	}{
+		{"/tmp/caps/run42_slot1_port4.pcap", Origin{RunID: 42, Slot: 1, Port: 4}, true},
+		{"run7_slot2_port0_rx.pcapng", Origin{RunID: 7, Slot: 2, Port: 0, Direction: "rx"}, true},
+		{"capture.pcap", Origin{}, false},
+		{"run7_slot2.pcap", Origin{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := OriginFromFile(tt.path)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("OriginFromFile(%s) = %+v, %v, want %+v, %v", tt.path, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestHandshakes(t *testing.T) {
+	type seg struct {
+		fromClient bool
+		flags      uint8
+	}
+	tests := []struct {
+		name string
+		segs []seg
+		want Handshakes
+	}{
+		{"completed", []seg{{true, FlagSYN}, {false, FlagSYN | FlagACK}, {true, FlagACK}}, Handshakes{Attempted: 1, Completed: 1}},
+		{"refused", []seg{{true, FlagSYN}, {false, FlagRST | FlagACK}}, Handshakes{Attempted: 1, Refused: 1}},
+		{"unanswered", []seg{{true, FlagSYN}, {true, FlagSYN}}, Handshakes{Attempted: 1, Unanswered: 1}},
+		{"client reset after SYN/ACK", []seg{{true, FlagSYN}, {false, FlagSYN | FlagACK}, {true, FlagRST | FlagACK}}, Handshakes{Attempted: 1, Aborted: 1}},
+		{"server reset after SYN/ACK", []seg{{true, FlagSYN}, {false, FlagSYN | FlagACK}, {false, FlagRST}}, Handshakes{Attempted: 1, Aborted: 1}},
+		{"no final ACK", []seg{{true, FlagSYN}, {false, FlagSYN | FlagACK}}, Handshakes{Attempted: 1, Incomplete: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var packets []*Packet
+			for i, s := range tt.segs {
+				f := frameSpec{src: "10.0.0.1", dst: "10.0.0.2", sport: 1000, dport: 80}
+				if !s.fromClient {
+					f = frameSpec{src: "10.0.0.2", dst: "10.0.0.1", sport: 80, dport: 1000}
+				}
+				f.proto, f.flags = ProtoTCP, s.flags
+				packets = append(packets, &Packet{
+					Timestamp: time.Unix(1700000000, int64(i)),
+					LinkType:  LinkEthernet,
+					Data:      ethernetFrame(f),
+				})
+			}
+			got := SummarizePackets(packets).Handshakes
+			if got != tt.want {
+				t.Errorf("handshakes %+v, want %+v", got, tt.want)
+			}
+			if got.Completed+got.Failed() != got.Attempted {
+				t.Errorf("outcomes of %+v do not add up", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+// Package pcap reads packet captures in the classic pcap and the pcapng
+// formats and decodes the Ethernet, IP, TCP and UDP headers needed to
+// follow conversations and summarize a capture. It has no dependencies
+// outside the standard library so captures can be examined offline.
+package pcap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Link types
+const (
+	LinkNull     = 0
+	LinkEthernet = 1
+	LinkRaw      = 101
+	LinkLinuxSLL = 113
+	LinkIPv4     = 228
+	LinkIPv6     = 229
+)
+
+const (
+	magicMicro   = 0xa1b2c3d4
+	magicNano    = 0xa1b23c4d
+	blockSHB     = 0x0a0d0d0a
+	blockIDB     = 0x00000001
+	blockSPB     = 0x00000003
+	blockEPB     = 0x00000006
+	byteOrderBOM = 0x1a2b3c4d
+	maxBlockSize = 64 << 20
+)
+
+// Packet is one captured frame.
+type Packet struct {
+	Timestamp time.Time
+	LinkType  int
+	// OrigLen is the length on the wire, Data may be shorter when the
+	// capture was truncated to a snap length.
+	OrigLen int
+	Data    []byte
+}
+
+// Reader reads packets from a pcap or pcapng stream.
+type Reader struct {
+	r     *bufio.Reader
+	next  func() (*Packet, error)
+	order binary.ByteOrder
+
+	// pcap
+	nano     bool
+	linkType int
+
+	// pcapng, per interface of the current section
+	ifaces []pcapngIface
+}
+
+type pcapngIface struct {
+	linkType int
+	// tsUnit is the duration of one timestamp tick
+	tsUnit time.Duration
+}
+
+// NewReader detects the format of r from its first bytes.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{r: bufio.NewReader(r)}
+	head, err := rd.r.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf("read capture header: %w", err)
+	}
+	if binary.LittleEndian.Uint32(head) == blockSHB {
+		rd.next = rd.nextPcapng
+		return rd, nil
+	}
+	if err := rd.readPcapHeader(); err != nil {
+		return nil, err
+	}
+	rd.next = rd.nextPcap
+	return rd, nil
+}
+
+// Next returns the next packet, or io.EOF at the end of the capture.
+func (rd *Reader) Next() (*Packet, error) {
+	return rd.next()
+}
+
+// ReadFile reads every packet of the capture at path.
+func ReadFile(path string) ([]*Packet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rd, err := NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var packets []*Packet
+	for {
+		p, err := rd.Next()
+		if err == io.EOF {
+			return packets, nil
+		}
+		if err != nil {
+			return packets, fmt.Errorf("%s: packet %d: %w", path, len(packets)+1, err)
+		}
+		packets = append(packets, p)
+	}
+}
+
+func (rd *Reader) readPcapHeader() error {
+	var hdr [24]byte
+	if _, err := io.ReadFull(rd.r, hdr[:]); err != nil {
+		return fmt.Errorf("read pcap header: %w", err)
+	}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		switch order.Uint32(hdr[0:4]) {
+		case magicMicro:
+			rd.order = order
+		case magicNano:
+			rd.order, rd.nano = order, true
+		default:
+			continue
+		}
+		rd.linkType = int(order.Uint32(hdr[20:24]) & 0x0fffffff)
+		return nil
+	}
+	return fmt.Errorf("not a pcap or pcapng capture (magic %x)", hdr[0:4])
+}
+
+func (rd *Reader) nextPcap() (*Packet, error) {
+	var hdr [16]byte
+	if _, err := io.ReadFull(rd.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated record header")
+		}
+		return nil, err
+	}
+	sec := int64(rd.order.Uint32(hdr[0:4]))
+	frac := int64(rd.order.Uint32(hdr[4:8]))
+	capLen := rd.order.Uint32(hdr[8:12])
+	if capLen > maxBlockSize {
+		return nil, fmt.Errorf("record length %d is too large", capLen)
+	}
+	data := make([]byte, capLen)
+	if _, err := io.ReadFull(rd.r, data); err != nil {
+		return nil, fmt.Errorf("truncated record: %w", err)
+	}
+	if !rd.nano {
+		frac *= 1000
+	}
+	return &Packet{
+		Timestamp: time.Unix(sec, frac).UTC(),
+		LinkType:  rd.linkType,
+		OrigLen:   int(rd.order.Uint32(hdr[12:16])),
+		Data:      data,
+	}, nil
+}
+
+func (rd *Reader) nextPcapng() (*Packet, error) {
+	for {
+		typ, body, err := rd.readBlock()
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case blockSHB:
+			rd.ifaces = nil
+		case blockIDB:
+			if len(body) < 8 {
+				return nil, fmt.Errorf("short interface description block")
+			}
+			rd.ifaces = append(rd.ifaces, pcapngIface{
+				linkType: int(rd.order.Uint16(body[0:2])),
+				tsUnit:   rd.tsResolution(body[8:]),
+			})
+		case blockEPB:
+			if len(body) < 20 {
+				return nil, fmt.Errorf("short enhanced packet block")
+			}
+			id := int(rd.order.Uint32(body[0:4]))
+			if id >= len(rd.ifaces) {
+				return nil, fmt.Errorf("packet on undeclared interface %d", id)
+			}
+			iface := rd.ifaces[id]
+			ts := uint64(rd.order.Uint32(body[4:8]))<<32 | uint64(rd.order.Uint32(body[8:12]))
+			capLen := int(rd.order.Uint32(body[12:16]))
+			if 20+capLen > len(body) {
+				return nil, fmt.Errorf("enhanced packet block overruns its length")
+			}
+			return &Packet{
+				Timestamp: time.Unix(0, 0).Add(time.Duration(ts) * iface.tsUnit).UTC(),
+				LinkType:  iface.linkType,
+				OrigLen:   int(rd.order.Uint32(body[16:20])),
+				Data:      body[20 : 20+capLen],
+			}, nil
+		case blockSPB:
+			if len(rd.ifaces) == 0 || len(body) < 4 {
+				return nil, fmt.Errorf("simple packet block without interface")
+			}
+			origLen := int(rd.order.Uint32(body[0:4]))
+			data := body[4:]
+			if origLen < len(data) {
+				data = data[:origLen]
+			}
+			return &Packet{LinkType: rd.ifaces[0].linkType, OrigLen: origLen, Data: data}, nil
+		}
+	}
+}
+
+// readBlock reads one pcapng block and returns its type and body. A
+// section header block also sets the byte order of the section.
+func (rd *Reader) readBlock() (uint32, []byte, error) {
+	var hdr [8]byte
+	if _, err := io.ReadFull(rd.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return 0, nil, fmt.Errorf("truncated block header")
+		}
+		return 0, nil, err
+	}
+	if binary.LittleEndian.Uint32(hdr[0:4]) == blockSHB {
+		bom, err := rd.r.Peek(4)
+		if err != nil {
+			return 0, nil, fmt.Errorf("truncated section header")
+		}
+		switch {
+		case binary.LittleEndian.Uint32(bom) == byteOrderBOM:
+			rd.order = binary.LittleEndian
+		case binary.BigEndian.Uint32(bom) == byteOrderBOM:
+			rd.order = binary.BigEndian
+		default:
+			return 0, nil, fmt.Errorf("bad section byte order mark %x", bom)
+		}
+	}
+	if rd.order == nil {
+		return 0, nil, errors.New("pcapng block before section header")
+	}
+	typ := rd.order.Uint32(hdr[0:4])
+	total := rd.order.Uint32(hdr[4:8])
+	if total < 12 || total%4 != 0 || total > maxBlockSize {
+		return 0, nil, fmt.Errorf("bad block length %d", total)
+	}
+	rest := make([]byte, total-8)
+	if _, err := io.ReadFull(rd.r, rest); err != nil {
+		return 0, nil, fmt.Errorf("truncated block: %w", err)
+	}
+	return typ, rest[:len(rest)-4], nil
+}
+
+// tsResolution reads the if_tsresol option of an interface description
+// block; the default is microseconds.
+func (rd *Reader) tsResolution(opts []byte) time.Duration {
+	for len(opts) >= 4 {
+		code := rd.order.Uint16(opts[0:2])
+		length := int(rd.order.Uint16(opts[2:4]))
+		if code == 0 || 4+length > len(opts) {
+			break
+		}
+		if code == 9 && length >= 1 {
+			v := opts[4]
+			if v&0x80 == 0 {
+				unit := time.Second
+				for i := byte(0); i < v && unit > 1; i++ {
+					unit /= 10
+				}
+				return unit
+			}
+			shift := v & 0x7f
+			if shift < 30 {
+				return time.Second >> shift
+			}
+			return 1
+		}
+		opts = opts[4+(length+3)&^3:]
+	}
+	return time.Microsecond
+}
@@ -0,0 +1,302 @@
+package pcap
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Origin identifies where an exported capture came from so a summary can
+// be matched to its test run and port reservation.
+type Origin struct {
+	RunID     int    `json:"runId,omitempty"`
+	Slot      int    `json:"slot,omitempty"`
+	Port      int    `json:"port,omitempty"`
+	Group     int    `json:"group,omitempty"`
+	Direction string `json:"direction,omitempty"`
+}
+
+// captureName matches the files exported by operations.CaptureSession,
+// optionally followed by the direction.
+var captureName = regexp.MustCompile(`^run(\d+)_slot(\d+)_port(\d+)(?:_(tx|rx|both))?\.pcap(?:ng)?$`)
+
+// OriginFromFile reads the origin from the name of a capture exported by
+// operations.CaptureSession, run<id>_slot<slot>_port<port>.pcap. ok is
+// false for other names.
+func OriginFromFile(path string) (o Origin, ok bool) {
+	m := captureName.FindStringSubmatch(filepath.Base(path))
+	if m == nil {
+		return Origin{}, false
+	}
+	o.RunID, _ = strconv.Atoi(m[1])
+	o.Slot, _ = strconv.Atoi(m[2])
+	o.Port, _ = strconv.Atoi(m[3])
+	o.Direction = m[4]
+	return o, true
+}
+
+func (o Origin) String() string {
+	s := fmt.Sprintf("slot %d port %d", o.Slot, o.Port)
+	if o.Direction != "" {
+		s += " " + o.Direction
+	}
+	if o.RunID != 0 {
+		s = fmt.Sprintf("run %d, %s", o.RunID, s)
+	}
+	return s
+}
+
+// Count is a packet and byte counter.
+type Count struct {
+	Name    string `json:"name"`
+	Packets int    `json:"packets"`
+	Bytes   int    `json:"bytes"`
+}
+
+// Handshakes counts TCP connection attempts by outcome; every attempt
+// has exactly one.
+type Handshakes struct {
+	Attempted int `json:"attempted"`
+	// Completed got the final ACK, Refused was answered by a reset and
+	// Unanswered never saw a SYN/ACK. Aborted saw a SYN/ACK and then a
+	// reset before the final ACK; Incomplete saw a SYN/ACK and neither.
+	Completed  int `json:"completed"`
+	Refused    int `json:"refused"`
+	Unanswered int `json:"unanswered"`
+	Aborted    int `json:"aborted"`
+	Incomplete int `json:"incomplete"`
+}
+
+// Failed returns the attempts that did not complete.
+func (h Handshakes) Failed() int {
+	return h.Refused + h.Unanswered + h.Aborted + h.Incomplete
+}
+
+// Summary is the offline analysis of a capture.
+type Summary struct {
+	File    string    `json:"file"`
+	Origin  Origin    `json:"origin"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Packets int       `json:"packets"`
+	Bytes   int       `json:"bytes"`
+	// Other counts packets that are not TCP or UDP, Errors packets that
+	// could not be decoded.
+	Other  int `json:"other"`
+	Errors int `json:"errors"`
+
+	Flows           []*Conversation `json:"-"`
+	FlowCount       int             `json:"flows"`
+	Protocols       []Count         `json:"protocols"`
+	Services        []Count         `json:"services"`
+	TopTalkers      []Count         `json:"topTalkers"`
+	ClientBytes     int             `json:"clientBytes"`
+	ServerBytes     int             `json:"serverBytes"`
+	Retransmissions int             `json:"retransmissions"`
+	Resets          int             `json:"resets"`
+	Handshakes      Handshakes      `json:"handshakes"`
+}
+
+// TopTalkerCount is the number of addresses kept in Summary.TopTalkers.
+const TopTalkerCount = 10
+
+// Summarize reads the capture at path and summarizes it. A zero origin is
+// taken from the file name when it follows the CaptureSession naming.
+func Summarize(path string, origin Origin) (*Summary, error) {
+	packets, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if origin == (Origin{}) {
+		origin, _ = OriginFromFile(path)
+	}
+	s := SummarizePackets(packets)
+	s.File, s.Origin = path, origin
+	return s, nil
+}
+
+// SummarizePackets summarizes already read packets.
+func SummarizePackets(packets []*Packet) *Summary {
+	s := &Summary{}
+	t := NewTracker()
+	protocols := make(map[string]*Count)
+	talkers := make(map[string]*Count)
+	for _, p := range packets {
+		if s.Packets == 0 || p.Timestamp.Before(s.Start) {
+			s.Start = p.Timestamp
+		}
+		if p.Timestamp.After(s.End) {
+			s.End = p.Timestamp
+		}
+		s.Packets++
+		s.Bytes += p.OrigLen
+		seg, err := Decode(p)
+		switch {
+		case err == ErrNotTransport:
+			s.Other++
+			add(protocols, "other", p.OrigLen)
+			continue
+		case err != nil:
+			s.Errors++
+			continue
+		}
+		t.Add(p.Timestamp, seg)
+		add(protocols, protoName(seg.Proto), p.OrigLen)
+		add(talkers, seg.Src.String(), p.OrigLen)
+		add(talkers, seg.Dst.String(), p.OrigLen)
+	}
+	services := make(map[string]*Count)
+	for _, c := range t.Conversations() {
+		s.ClientBytes += c.ClientBytes
+		s.ServerBytes += c.ServerBytes
+		s.Retransmissions += c.Retransmissions
+		s.Resets += c.Resets
+		name := fmt.Sprintf("%s/%d", c.Protocol(), c.Server.Port)
+		svc, ok := services[name]
+		if !ok {
+			svc = &Count{Name: name}
+			services[name] = svc
+		}
+		svc.Packets += c.Packets
+		svc.Bytes += c.ClientBytes + c.ServerBytes
+		if c.Proto == ProtoTCP && c.SYN {
+			s.Handshakes.Attempted++
+			switch {
+			case c.Established:
+				s.Handshakes.Completed++
+			case !c.SYNACK && c.Resets > 0:
+				s.Handshakes.Refused++
+			case !c.SYNACK:
+				s.Handshakes.Unanswered++
+			case c.Resets > 0:
+				s.Handshakes.Aborted++
+			default:
+				s.Handshakes.Incomplete++
+			}
+		}
+		s.Flows = append(s.Flows, c)
+	}
+	s.FlowCount = len(s.Flows)
+	s.Protocols = ranked(protocols, 0)
+	s.Services = ranked(services, 0)
+	s.TopTalkers = ranked(talkers, TopTalkerCount)
+	return s
+}
+
+func protoName(proto int) string {
+	if proto == ProtoTCP {
+		return "tcp"
+	}
+	return "udp"
+}
+
+func add(counts map[string]*Count, name string, bytes int) {
+	c, ok := counts[name]
+	if !ok {
+		c = &Count{Name: name}
+		counts[name] = c
+	}
+	c.Packets++
+	c.Bytes += bytes
+}
+
+// ranked sorts counts by bytes, largest first, keeping at most n (all when
+// n is zero)
+func ranked(counts map[string]*Count, n int) []Count {
+	out := make([]Count, 0, len(counts))
+	for _, c := range counts {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Bytes != out[j].Bytes {
+			return out[i].Bytes > out[j].Bytes
+		}
+		return out[i].Name < out[j].Name
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// Thresholds are the limits Findings checks a summary against. Ratios
+// are fractions between 0 and 1.
+type Thresholds struct {
+	MaxFailedHandshakes float64
+	MaxRetransmissions  float64
+	MaxResets           float64
+}
+
+// DefaultThresholds flag a capture where more than 1% of handshakes fail,
+// more than 5% of TCP flows carry retransmissions or more than 5% of TCP
+// flows are reset.
+var DefaultThresholds = Thresholds{
+	MaxFailedHandshakes: 0.01,
+	MaxRetransmissions:  0.05,
+	MaxResets:           0.05,
+}
+
+// Findings returns a message for every threshold the capture exceeds. An
+// empty result means nothing suspicious was seen.
+func (s *Summary) Findings(th Thresholds) []string {
+	var out []string
+	h := s.Handshakes
+	if h.Attempted > 0 {
+		failed := h.Failed()
+		if r := float64(failed) / float64(h.Attempted); r > th.MaxFailedHandshakes {
+			out = append(out, fmt.Sprintf("%s: %d of %d TCP handshakes failed (%d unanswered, %d refused, %d aborted, %d incomplete)",
+				s.Origin, failed, h.Attempted, h.Unanswered, h.Refused, h.Aborted, h.Incomplete))
+		}
+	}
+	tcp, retrans, reset := 0, 0, 0
+	for _, c := range s.Flows {
+		if c.Proto != ProtoTCP {
+			continue
+		}
+		tcp++
+		if c.Retransmissions > 0 {
+			retrans++
+		}
+		if c.Resets > 0 {
+			reset++
+		}
+	}
+	if tcp > 0 {
+		if r := float64(retrans) / float64(tcp); r > th.MaxRetransmissions {
+			out = append(out, fmt.Sprintf("%s: %d of %d TCP flows retransmitted data (%d segments)", s.Origin, retrans, tcp, s.Retransmissions))
+		}
+		if r := float64(reset) / float64(tcp); r > th.MaxResets {
+			out = append(out, fmt.Sprintf("%s: %d of %d TCP flows were reset", s.Origin, reset, tcp))
+		}
+	}
+	return out
+}
+
+// String renders the summary as a text report.
+func (s *Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", s.File, s.Origin)
+	fmt.Fprintf(&b, "%d packets, %d bytes, %v, %d flows, %d other, %d undecodable\n",
+		s.Packets, s.Bytes, s.End.Sub(s.Start), s.FlowCount, s.Other, s.Errors)
+	fmt.Fprintf(&b, "client->server %d bytes, server->client %d bytes\n", s.ClientBytes, s.ServerBytes)
+	h := s.Handshakes
+	fmt.Fprintf(&b, "handshakes %d attempted, %d completed, %d refused, %d unanswered, %d aborted, %d incomplete; %d retransmissions, %d resets\n",
+		h.Attempted, h.Completed, h.Refused, h.Unanswered, h.Aborted, h.Incomplete, s.Retransmissions, s.Resets)
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	for _, section := range []struct {
+		title  string
+		counts []Count
+	}{{"PROTOCOL", s.Protocols}, {"SERVICE", s.Services}, {"TALKER", s.TopTalkers}} {
+		fmt.Fprintf(w, "\n%s\tPACKETS\tBYTES\n", section.title)
+		for _, c := range section.counts {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", c.Name, c.Packets, c.Bytes)
+		}
+	}
+	w.Flush()
+	return b.String()
+}
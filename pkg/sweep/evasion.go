@@ -0,0 +1,382 @@
+package sweep
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"bps-client-go/pkg/operations"
+)
+
+// Strike outcomes. Other values reported by the chassis, such as errors,
+// are kept as reported in lower case.
+const (
+	StrikeBlocked = "blocked"
+	StrikeAllowed = "allowed"
+)
+
+// Defaults of the report table strike outcomes are read from.
+const (
+	DefaultStrikeSection = "Strike Results"
+	DefaultStrikeColumn  = "Strike Name"
+	DefaultResultColumn  = "Result"
+)
+
+// BaselineEvasion names the run without evasion options.
+const BaselineEvasion = "baseline"
+
+// EvasionAxis is an evasion option and the values it takes, the option
+// named "Group.Option" as in the strike options catalog.
+type EvasionAxis struct {
+	Option string        `json:"option"`
+	Values []interface{} `json:"values"`
+}
+
+// EvasionPermutation is one combination of evasion option values.
+type EvasionPermutation struct {
+	Name    string
+	Options map[string]interface{}
+}
+
+// EvasionPermutations returns every combination of the axis values, the
+// last axis varying fastest. Each is named after its values.
+func EvasionPermutations(axes []EvasionAxis) []EvasionPermutation {
+	if len(axes) == 0 {
+		return nil
+	}
+	total := 1
+	for _, a := range axes {
+		total *= len(a.Values)
+	}
+	perms := make([]EvasionPermutation, total)
+	for i := range perms {
+		options := make(map[string]interface{}, len(axes))
+		parts := make([]string, len(axes))
+		rest := i
+		for j := len(axes) - 1; j >= 0; j-- {
+			a := axes[j]
+			v := a.Values[rest%len(a.Values)]
+			options[a.Option] = v
+			parts[j] = fmt.Sprintf("%s=%v", a.Option, v)
+			rest /= len(a.Values)
+		}
+		perms[i] = EvasionPermutation{Name: strings.Join(parts, " "), Options: options}
+	}
+	return perms
+}
+
+// EvasionRun is the outcome of running the strike list with one evasion
+// profile.
+type EvasionRun struct {
+	Evasion  EvasionPermutation
+	Profile  string
+	RunID    int
+	Outcomes map[string]string
+	Error    string
+}
+
+// EvasionCampaign runs a strike list once per evasion permutation, each
+// with its own evasion profile attached to a Security component, and
+// collects which strikes the DUT blocked.
+type EvasionCampaign struct {
+	Client operations.ClientWrapper
+	// Model is the test model holding the Security component. It is left
+	// untouched; every run is saved as Model + "_evasion".
+	Model string
+	Group int
+	// Component is the id of the Security NP component.
+	Component string
+	// StrikeList replaces the strike list of the component when set.
+	StrikeList string
+	Axes       []EvasionAxis
+	// Baseline adds a run with the catalog defaults, against which
+	// bypasses are judged.
+	Baseline bool
+	// ProfilePrefix names the profiles, followed by the permutation
+	// index; it defaults to Model + "_evasion".
+	ProfilePrefix string
+	// Section and the columns select the report table with the per strike
+	// outcome; they default to DefaultStrikeSection, DefaultStrikeColumn
+	// and DefaultResultColumn.
+	Section      string
+	StrikeColumn string
+	ResultColumn string
+	PollInterval time.Duration
+	// Log receives one line per run when set.
+	Log io.Writer
+}
+
+// Run checks the values of every permutation against the catalog before
+// anything is run, then for each permutation in turn creates its evasion
+// profile and runs it. A failed run is recorded and the campaign moves
+// on; cancelling ctx stops the current run and returns the matrix so far
+// with ctx.Err().
+func (c *EvasionCampaign) Run(ctx context.Context) (*EvasionMatrix, error) {
+	if c.Component == "" {
+		return nil, fmt.Errorf("evasion campaign needs a Security component")
+	}
+	perms := EvasionPermutations(c.Axes)
+	if c.Baseline {
+		perms = append([]EvasionPermutation{{Name: BaselineEvasion}}, perms...)
+	}
+	if len(perms) == 0 {
+		return nil, fmt.Errorf("evasion campaign has no permutations")
+	}
+	evasion := &operations.EvasionProfileOps{Client: c.Client}
+	catalog, err := evasion.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	prefix := c.ProfilePrefix
+	if prefix == "" {
+		prefix = sanitize(c.Model) + "_evasion"
+	}
+	profiles := make([]*operations.EvasionSettings, len(perms))
+	for i, p := range perms {
+		s := catalog.NewSettings(fmt.Sprintf("%s_%03d", prefix, i+1))
+		s.Description = p.Name
+		for _, option := range sortedOptions(p.Options) {
+			if err := s.Set(option, p.Options[option]); err != nil {
+				return nil, fmt.Errorf("permutation %s: %w", p.Name, err)
+			}
+		}
+		profiles[i] = s
+	}
+	m := &EvasionMatrix{}
+	for i, p := range perms {
+		r := c.runPermutation(ctx, evasion, p, profiles[i])
+		if ctx.Err() != nil {
+			return m, ctx.Err()
+		}
+		m.Runs = append(m.Runs, r)
+		if r.Error != "" {
+			c.logf("evasion %d/%d %s: %s", i+1, len(perms), p.Name, r.Error)
+		} else {
+			c.logf("evasion %d/%d %s: run %d, %d of %d strikes blocked", i+1, len(perms), p.Name, r.RunID, count(r.Outcomes, StrikeBlocked), len(r.Outcomes))
+		}
+	}
+	return m, nil
+}
+
+func (c *EvasionCampaign) runPermutation(ctx context.Context, evasion *operations.EvasionProfileOps, p EvasionPermutation, profile *operations.EvasionSettings) EvasionRun {
+	r := EvasionRun{Evasion: p, Profile: profile.Name}
+	fail := func(err error) EvasionRun {
+		r.Error = err.Error()
+		return r
+	}
+	if err := evasion.Create(profile, true); err != nil {
+		return fail(err)
+	}
+	tm := &operations.TestModelOps{Client: c.Client}
+	if _, err := tm.Load(c.Model, false); err != nil {
+		return fail(fmt.Errorf("load %s: %w", c.Model, err))
+	}
+	model := sanitize(c.Model) + "_evasion"
+	edit := tm.Edit(c.Model).SaveAs(model)
+	security, err := edit.SecurityNP(c.Component)
+	if err != nil {
+		return fail(err)
+	}
+	if c.StrikeList != "" {
+		if err := security.SetStrikeList(c.StrikeList); err != nil {
+			return fail(err)
+		}
+	}
+	if err := security.SetEvasionProfile(profile.Name); err != nil {
+		return fail(err)
+	}
+	if err := edit.Commit(); err != nil {
+		return fail(err)
+	}
+	runID, err := tm.RunAndWait(ctx, model, c.Group, false, c.PollInterval)
+	r.RunID = runID
+	if err != nil {
+		return fail(err)
+	}
+	outcomes, err := c.outcomes(runID)
+	r.Outcomes = outcomes
+	if err != nil {
+		return fail(err)
+	}
+	return r
+}
+
+// outcomes reads the per strike outcome of a finished run.
+func (c *EvasionCampaign) outcomes(runID int) (map[string]string, error) {
+	section, strikeCol, resultCol := c.Section, c.StrikeColumn, c.ResultColumn
+	if section == "" {
+		section = DefaultStrikeSection
+	}
+	if strikeCol == "" {
+		strikeCol = DefaultStrikeColumn
+	}
+	if resultCol == "" {
+		resultCol = DefaultResultColumn
+	}
+	reports := &operations.ReportsOps{Client: c.Client}
+	t, err := reports.Table(runID, section)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(t.Rows))
+	for _, row := range t.Rows {
+		strike, ok := row[strikeCol]
+		if !ok {
+			return nil, fmt.Errorf("report %d section %s has no column %s", runID, section, strikeCol)
+		}
+		out[strike] = outcome(row[resultCol])
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("report %d section %s lists no strikes", runID, section)
+	}
+	return out, nil
+}
+
+func outcome(result string) string {
+	r := strings.ToLower(strings.TrimSpace(result))
+	switch {
+	case strings.Contains(r, "unblocked"), strings.Contains(r, "not blocked"):
+		return StrikeAllowed
+	case strings.Contains(r, "block"):
+		return StrikeBlocked
+	case strings.Contains(r, "allow"), strings.Contains(r, "pass"):
+		return StrikeAllowed
+	}
+	return r
+}
+
+func (c *EvasionCampaign) logf(format string, args ...interface{}) {
+	if c.Log != nil {
+		fmt.Fprintf(c.Log, format+"\n", args...)
+	}
+}
+
+// EvasionMatrix holds the strike outcomes of every evasion run.
+type EvasionMatrix struct {
+	Runs []EvasionRun
+}
+
+// Strikes returns the sorted names of every strike of any run.
+func (m *EvasionMatrix) Strikes() []string {
+	seen := make(map[string]bool)
+	var strikes []string
+	for _, r := range m.Runs {
+		for s := range r.Outcomes {
+			if !seen[s] {
+				seen[s] = true
+				strikes = append(strikes, s)
+			}
+		}
+	}
+	sort.Strings(strikes)
+	return strikes
+}
+
+// Outcome returns the outcome of strike under the evasion named evasion,
+// or "" if it was not run.
+func (m *EvasionMatrix) Outcome(strike, evasion string) string {
+	for _, r := range m.Runs {
+		if r.Evasion.Name == evasion {
+			return r.Outcomes[strike]
+		}
+	}
+	return ""
+}
+
+// Bypasses lists, per evasion, the strikes it got past the DUT: allowed
+// with the evasion but blocked in the baseline run, or simply allowed when
+// there is no baseline. It fails if the baseline run failed, since nothing
+// can be judged against it.
+func (m *EvasionMatrix) Bypasses() (map[string][]string, error) {
+	var baseline map[string]string
+	for _, r := range m.Runs {
+		if r.Evasion.Name != BaselineEvasion {
+			continue
+		}
+		if r.Error != "" {
+			return nil, fmt.Errorf("baseline run failed: %s", r.Error)
+		}
+		baseline = r.Outcomes
+	}
+	strikes := m.Strikes()
+	out := make(map[string][]string)
+	for _, r := range m.Runs {
+		if r.Evasion.Name == BaselineEvasion {
+			continue
+		}
+		for _, s := range strikes {
+			if r.Outcomes[s] != StrikeAllowed {
+				continue
+			}
+			if baseline != nil && baseline[s] != StrikeBlocked {
+				continue
+			}
+			out[r.Evasion.Name] = append(out[r.Evasion.Name], s)
+		}
+	}
+	return out, nil
+}
+
+// WriteCSV writes the strike × evasion matrix: one row per strike, one
+// column per evasion, followed by the profile and run id of each evasion
+// and the errors of failed runs.
+func (m *EvasionMatrix) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"strike"}
+	for _, r := range m.Runs {
+		header = append(header, r.Evasion.Name)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, s := range m.Strikes() {
+		row := []string{s}
+		for _, r := range m.Runs {
+			row = append(row, r.Outcomes[s])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	for _, line := range []struct {
+		label string
+		value func(EvasionRun) string
+	}{
+		{"# profile", func(r EvasionRun) string { return r.Profile }},
+		{"# runId", func(r EvasionRun) string { return fmt.Sprint(r.RunID) }},
+		{"# error", func(r EvasionRun) string { return r.Error }},
+	} {
+		row := []string{line.label}
+		for _, r := range m.Runs {
+			row = append(row, line.value(r))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func sortedOptions(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func count(m map[string]string, value string) int {
+	n := 0
+	for _, v := range m {
+		if v == value {
+			n++
+		}
+	}
+	return n
+}
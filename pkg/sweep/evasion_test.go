@@ -0,0 +1,80 @@
+package sweep
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		result string
+		want   string
+	}{
+		{"Blocked", StrikeBlocked},
+		{"blocked by DUT", StrikeBlocked},
+		{"Unblocked", StrikeAllowed},
+		{"Not Blocked", StrikeAllowed},
+		{"Allowed", StrikeAllowed},
+		{"passed", StrikeAllowed},
+		{" Error ", "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.result, func(t *testing.T) {
+			if got := outcome(tt.result); got != tt.want {
+				t.Errorf("outcome(%q) = %q, want %q", tt.result, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBypasses(t *testing.T) {
+	baseline := EvasionRun{
+		Evasion:  EvasionPermutation{Name: BaselineEvasion},
+		Outcomes: map[string]string{"a": StrikeBlocked, "b": StrikeAllowed, "c": StrikeBlocked},
+	}
+	frag := EvasionRun{
+		Evasion:  EvasionPermutation{Name: "IP.FragEvasion=true"},
+		Outcomes: map[string]string{"a": StrikeAllowed, "b": StrikeAllowed, "c": StrikeBlocked},
+	}
+	tests := []struct {
+		name    string
+		runs    []EvasionRun
+		want    map[string][]string
+		wantErr string
+	}{
+		{
+			name: "judged against the baseline",
+			runs: []EvasionRun{baseline, frag},
+			want: map[string][]string{"IP.FragEvasion=true": {"a"}},
+		},
+		{
+			name: "no baseline",
+			runs: []EvasionRun{frag},
+			want: map[string][]string{"IP.FragEvasion=true": {"a", "b"}},
+		},
+		{
+			name:    "failed baseline",
+			runs:    []EvasionRun{{Evasion: baseline.Evasion, Error: "run 7 failed"}, frag},
+			wantErr: "baseline run failed: run 7 failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &EvasionMatrix{Runs: tt.runs}
+			got, err := m.Bypasses()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Bypasses() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bypasses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
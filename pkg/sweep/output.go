@@ -0,0 +1,48 @@
+package sweep
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes one row per result with the axis values, the model and
+// run id, the metrics and the error, if any.
+func WriteCSV(w io.Writer, axes []Axis, metrics []Metric, results []Result) error {
+	cw := csv.NewWriter(w)
+	header := []string{"point", "model", "runId"}
+	for _, a := range axes {
+		header = append(header, a.Key())
+	}
+	for _, m := range metrics {
+		header = append(header, m.Name)
+	}
+	header = append(header, "error")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range results {
+		row := []string{strconv.Itoa(r.Index + 1), r.Model, strconv.Itoa(r.RunID)}
+		for _, a := range axes {
+			row = append(row, fmt.Sprintf("%v", r.Values[a.Key()]))
+		}
+		for _, m := range metrics {
+			row = append(row, r.Metrics[m.Name])
+		}
+		row = append(row, r.Error)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the results as an indented JSON array.
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
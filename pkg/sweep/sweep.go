@@ -0,0 +1,250 @@
+// Package sweep runs a test model once for every combination of a grid of
+// component parameter values and collects report metrics of each run into
+// a single matrix. It also searches for the highest rate a device under
+// test forwards without loss and runs a strike list under a series of
+// evasion profiles to find the evasions that get past it.
+package sweep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"bps-client-go/pkg/operations"
+)
+
+// Axis is one dimension of the grid: a component parameter and the values
+// it takes. Param uses the dotted form of ModelEdit.SetParameter.
+type Axis struct {
+	Component string        `json:"component"`
+	Param     string        `json:"param"`
+	Values    []interface{} `json:"values"`
+}
+
+// Key names the axis in results and output headers.
+func (a Axis) Key() string {
+	return a.Component + "." + a.Param
+}
+
+// Metric is a report value collected after every run.
+type Metric struct {
+	Name    string `json:"name"`
+	Section string `json:"section"`
+	Column  string `json:"column"`
+	// Row selects the row by the content of one of its cells; empty
+	// selects the last row.
+	Row string `json:"row,omitempty"`
+}
+
+// Point is one combination of the grid.
+type Point struct {
+	Index  int                    `json:"index"`
+	Model  string                 `json:"model"`
+	Values map[string]interface{} `json:"values"`
+}
+
+// Result is the outcome of running one point.
+type Result struct {
+	Point
+	RunID    int               `json:"runId,omitempty"`
+	Metrics  map[string]string `json:"metrics,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Finished time.Time         `json:"finished"`
+}
+
+// Sweep runs Model for every point of the grid spanned by Axes.
+type Sweep struct {
+	Client  operations.ClientWrapper
+	Model   string
+	Group   int
+	Axes    []Axis
+	Metrics []Metric
+	// InPlace edits and saves Model itself; otherwise every point is saved
+	// as a derived model named after the base and the point index.
+	InPlace bool
+	// StateFile records finished points so an interrupted sweep resumes
+	// where it stopped. Points that failed are retried.
+	StateFile    string
+	PollInterval time.Duration
+	// Log receives one line per point when set.
+	Log io.Writer
+}
+
+// Points returns every combination of the axis values, the last axis
+// varying fastest.
+func (s *Sweep) Points() []Point {
+	total := 1
+	for _, a := range s.Axes {
+		total *= len(a.Values)
+	}
+	if len(s.Axes) == 0 {
+		total = 0
+	}
+	points := make([]Point, total)
+	for i := range points {
+		values := make(map[string]interface{}, len(s.Axes))
+		rest := i
+		for j := len(s.Axes) - 1; j >= 0; j-- {
+			a := s.Axes[j]
+			values[a.Key()] = a.Values[rest%len(a.Values)]
+			rest /= len(a.Values)
+		}
+		model := s.Model
+		if !s.InPlace {
+			model = fmt.Sprintf("%s_sweep_%03d", sanitize(s.Model), i+1)
+		}
+		points[i] = Point{Index: i, Model: model, Values: values}
+	}
+	return points
+}
+
+// Run executes every point not already finished according to StateFile
+// and returns the results of all points in grid order. When ctx is
+// cancelled the current run is stopped and ctx.Err() returned with the
+// results so far; points finished before remain in StateFile. A recorded
+// point whose values no longer match the grid is run again.
+func (s *Sweep) Run(ctx context.Context) ([]Result, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	st, err := loadState(s.StateFile)
+	if err != nil {
+		return nil, err
+	}
+	points := s.Points()
+	for _, p := range points {
+		if r, ok := st.Results[p.Index]; ok && r.Error == "" && describe(r.Point) == describe(p) {
+			s.logf("point %d/%d already done (run %d)", p.Index+1, len(points), r.RunID)
+			continue
+		}
+		r := s.runPoint(ctx, p)
+		if ctx.Err() != nil {
+			return st.ordered(), ctx.Err()
+		}
+		st.Results[p.Index] = r
+		if err := st.save(s.StateFile); err != nil {
+			return st.ordered(), err
+		}
+		if r.Error != "" {
+			s.logf("point %d/%d %s: %s", p.Index+1, len(points), describe(p), r.Error)
+		} else {
+			s.logf("point %d/%d %s: run %d %v", p.Index+1, len(points), describe(p), r.RunID, r.Metrics)
+		}
+	}
+	return st.ordered(), nil
+}
+
+func (s *Sweep) check() error {
+	if len(s.Axes) == 0 {
+		return fmt.Errorf("sweep has no axes")
+	}
+	for _, a := range s.Axes {
+		if a.Component == "" || a.Param == "" || len(a.Values) == 0 {
+			return fmt.Errorf("axis %s needs a component, a parameter and values", a.Key())
+		}
+	}
+	return nil
+}
+
+func (s *Sweep) runPoint(ctx context.Context, p Point) Result {
+	r := Result{Point: p}
+	fail := func(err error) Result {
+		r.Error = err.Error()
+		r.Finished = time.Now()
+		return r
+	}
+	tm := &operations.TestModelOps{Client: s.Client}
+	if _, err := tm.Load(s.Model, false); err != nil {
+		return fail(fmt.Errorf("load %s: %w", s.Model, err))
+	}
+	edit := tm.Edit(s.Model).SaveAs(p.Model)
+	for _, a := range s.Axes {
+		c, err := edit.Component(a.Component)
+		if err != nil {
+			return fail(err)
+		}
+		if err := c.Set(a.Param, p.Values[a.Key()]); err != nil {
+			return fail(err)
+		}
+	}
+	if err := edit.Commit(); err != nil {
+		return fail(err)
+	}
+	runID, err := tm.RunAndWait(ctx, p.Model, s.Group, false, s.PollInterval)
+	r.RunID = runID
+	if err != nil {
+		return fail(err)
+	}
+	metrics, err := CollectMetrics(&operations.ReportsOps{Client: s.Client}, runID, s.Metrics)
+	r.Metrics = metrics
+	if err != nil {
+		return fail(err)
+	}
+	r.Finished = time.Now()
+	return r
+}
+
+// CollectMetrics reads metrics from the report of a finished run, reading
+// each section once.
+func CollectMetrics(reports *operations.ReportsOps, runID int, metrics []Metric) (map[string]string, error) {
+	out := make(map[string]string, len(metrics))
+	tables := make(map[string]*operations.ReportTable)
+	for _, m := range metrics {
+		t, ok := tables[m.Section]
+		if !ok {
+			var err error
+			if t, err = reports.Table(runID, m.Section); err != nil {
+				return out, err
+			}
+			tables[m.Section] = t
+		}
+		v, err := t.Value(m.Column, m.Row)
+		if err != nil {
+			return out, fmt.Errorf("metric %s: %w", m.Name, err)
+		}
+		out[m.Name] = v
+	}
+	return out, nil
+}
+
+func (s *Sweep) logf(format string, args ...interface{}) {
+	if s.Log != nil {
+		fmt.Fprintf(s.Log, format+"\n", args...)
+	}
+}
+
+// describe names the values of p in canonical JSON, so a point read back
+// from the state file, whose numbers have become float64, matches the grid
+// point it was recorded for.
+func describe(p Point) string {
+	var parts []string
+	for k, v := range p.Values {
+		parts = append(parts, k+"="+canonical(v))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+func canonical(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	var plain interface{}
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return string(data)
+	}
+	data, _ = json.Marshal(plain)
+	return string(data)
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+func sanitize(name string) string {
+	return unsafeName.ReplaceAllString(name, "_")
+}
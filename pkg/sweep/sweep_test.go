@@ -0,0 +1,109 @@
+package sweep
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDescribeSurvivesState(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"large int", 1000000},
+		{"int64", int64(40000000000)},
+		{"float", 2.5},
+		{"string", "10 Gbps"},
+		{"numeric string", "1000000"},
+		{"bool", true},
+		{"list", []interface{}{1, "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "state.json")
+			p := Point{Index: 0, Model: "m", Values: map[string]interface{}{"appsim.sessions.max": tt.value}}
+			st, err := loadState(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			st.Results[0] = Result{Point: p, RunID: 1}
+			if err := st.save(file); err != nil {
+				t.Fatal(err)
+			}
+			st, err = loadState(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := describe(st.Results[0].Point), describe(p); got != want {
+				t.Errorf("describe after state round trip = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDescribeDistinguishesTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b interface{}
+	}{
+		{"number and string", 1000000, "1000000"},
+		{"different numbers", 1000000, 1000001},
+		{"bool and string", true, "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Point{Values: map[string]interface{}{"c.p": tt.a}}
+			b := Point{Values: map[string]interface{}{"c.p": tt.b}}
+			if describe(a) == describe(b) {
+				t.Errorf("describe(%v) and describe(%v) are both %s", tt.a, tt.b, describe(a))
+			}
+		})
+	}
+}
+
+func TestPoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		inPlace bool
+		axes    []Axis
+		want    []string
+		models  []string
+	}{
+		{
+			name: "last axis varies fastest",
+			axes: []Axis{
+				{Component: "a", Param: "x", Values: []interface{}{1, 2}},
+				{Component: "b", Param: "y", Values: []interface{}{"u", "v"}},
+			},
+			want:   []string{`a.x=1 b.y="u"`, `a.x=1 b.y="v"`, `a.x=2 b.y="u"`, `a.x=2 b.y="v"`},
+			models: []string{"base_model_sweep_001", "base_model_sweep_002", "base_model_sweep_003", "base_model_sweep_004"},
+		},
+		{
+			name:    "in place",
+			inPlace: true,
+			axes:    []Axis{{Component: "a", Param: "x", Values: []interface{}{1}}},
+			want:    []string{"a.x=1"},
+			models:  []string{"base model"},
+		},
+		{
+			name: "no axes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Sweep{Model: "base model", Axes: tt.axes, InPlace: tt.inPlace}
+			points := s.Points()
+			if len(points) != len(tt.want) {
+				t.Fatalf("got %d points, want %d", len(points), len(tt.want))
+			}
+			for i, p := range points {
+				if got := describe(p); got != tt.want[i] {
+					t.Errorf("point %d = %s, want %s", i, got, tt.want[i])
+				}
+				if p.Model != tt.models[i] {
+					t.Errorf("point %d model = %s, want %s", i, p.Model, tt.models[i])
+				}
+			}
+		})
+	}
+}
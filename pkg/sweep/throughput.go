@@ -0,0 +1,300 @@
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"bps-client-go/pkg/operations"
+)
+
+// LossFunc returns the frame loss of a finished run in percent.
+type LossFunc func(runID int) (float64, error)
+
+// ReportLoss computes loss from transmitted and received frame counts read
+// from the report of the run.
+func ReportLoss(client operations.ClientWrapper, tx, rx Metric) LossFunc {
+	reports := &operations.ReportsOps{Client: client}
+	return func(runID int) (float64, error) {
+		values, err := CollectMetrics(reports, runID, []Metric{tx, rx})
+		if err != nil {
+			return 0, err
+		}
+		return lossPercent(values[tx.Name], values[rx.Name])
+	}
+}
+
+// StatsLoss computes loss from transmitted and received frame counts of
+// the real-time statistics group, e.g. "summary" with fields
+// "ethTxFrames" and "ethRxFrames".
+func StatsLoss(client operations.ClientWrapper, group, txField, rxField string) LossFunc {
+	tm := &operations.TestModelOps{Client: client}
+	return func(runID int) (float64, error) {
+		resp, err := tm.RealTimeStats(runID, group, -1, 1, "", []string{})
+		if err != nil {
+			return 0, fmt.Errorf("real-time stats of run %d: %w", runID, err)
+		}
+		tx, ok := findStat(resp, txField)
+		if !ok {
+			return 0, fmt.Errorf("real-time stats of run %d have no %s", runID, txField)
+		}
+		rx, ok := findStat(resp, rxField)
+		if !ok {
+			return 0, fmt.Errorf("real-time stats of run %d have no %s", runID, rxField)
+		}
+		return lossPercent(tx, rx)
+	}
+}
+
+func lossPercent(txValue, rxValue string) (float64, error) {
+	tx, err := parseNumber(txValue)
+	if err != nil {
+		return 0, fmt.Errorf("transmitted frames: %w", err)
+	}
+	rx, err := parseNumber(rxValue)
+	if err != nil {
+		return 0, fmt.Errorf("received frames: %w", err)
+	}
+	if tx <= 0 {
+		return 0, fmt.Errorf("no frames were transmitted")
+	}
+	if rx >= tx {
+		return 0, nil
+	}
+	return (tx - rx) / tx * 100, nil
+}
+
+// parseNumber accepts report formatting such as "1,234,567" or "12.5 %".
+func parseNumber(s string) (float64, error) {
+	clean := strings.TrimSpace(strings.TrimSuffix(strings.ReplaceAll(s, ",", ""), "%"))
+	f, err := strconv.ParseFloat(strings.TrimSpace(clean), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return f, nil
+}
+
+// findStat looks up field anywhere in a statistics response.
+func findStat(v interface{}, field string) (string, bool) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if s, ok := value[field]; ok && s != nil {
+			return fmt.Sprintf("%v", s), true
+		}
+		for _, child := range value {
+			if s, ok := findStat(child, field); ok {
+				return s, true
+			}
+		}
+	case []interface{}:
+		for _, child := range value {
+			if s, ok := findStat(child, field); ok {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Trial is one run of a throughput search.
+type Trial struct {
+	Rate  float64 `json:"rate"`
+	RunID int     `json:"runId"`
+	Loss  float64 `json:"loss"`
+	Pass  bool    `json:"pass"`
+}
+
+// ThroughputResult is the converged throughput for one frame size.
+// Converged is false, and Rate zero, when no trial passed.
+type ThroughputResult struct {
+	FrameSize int     `json:"frameSize"`
+	Rate      float64 `json:"rate"`
+	Converged bool    `json:"converged"`
+	Trials    []Trial `json:"trials"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Throughput searches, per frame size, for the highest rate at which the
+// loss of a run stays within MaxLoss, in the manner of RFC 2544 section
+// 26.1. The first trial of a frame size loads Model, edits the rate and
+// frame size of one component and saves the result as Model +
+// "_throughput", leaving Model untouched; later trials only change the
+// rate of that copy. Every trial runs it to completion and evaluates Loss.
+type Throughput struct {
+	Client    operations.ClientWrapper
+	Model     string
+	Group     int
+	Component string
+	// RateParam and FrameSizeParam name the component parameters, for a
+	// Routing Robot "rateDist.min" and "sizeDist.min".
+	RateParam      string
+	FrameSizeParam string
+	FrameSizes     []int
+	// Min and Max bound the search; it stops once the interval between
+	// the highest passing and lowest failing rate is at most Resolution.
+	// Rates of an integer parameter are rounded down to whole numbers.
+	Min, Max   float64
+	Resolution float64
+	// MaxLoss is the loss in percent still counted as a pass.
+	MaxLoss      float64
+	Loss         LossFunc
+	PollInterval time.Duration
+	Log          io.Writer
+}
+
+// Run searches every frame size in turn. A frame size whose trials fail
+// to run is reported with Error set and does not stop the others; ctx
+// cancellation does.
+func (t *Throughput) Run(ctx context.Context) ([]ThroughputResult, error) {
+	if t.Loss == nil {
+		return nil, fmt.Errorf("throughput search needs a Loss function")
+	}
+	if t.Resolution <= 0 || t.Min < 0 || t.Max <= t.Min {
+		return nil, fmt.Errorf("invalid search range %v..%v with resolution %v", t.Min, t.Max, t.Resolution)
+	}
+	integer, err := t.integerRate()
+	if err != nil {
+		return nil, err
+	}
+	sizes := t.FrameSizes
+	if len(sizes) == 0 {
+		sizes = []int{0}
+	}
+	var results []ThroughputResult
+	for _, size := range sizes {
+		r, err := t.search(ctx, size, integer)
+		if ctx.Err() != nil {
+			return append(results, r), ctx.Err()
+		}
+		if err != nil {
+			r.Error = err.Error()
+		}
+		if r.Converged {
+			t.logf("frame size %d: throughput %v after %d trials %s", size, r.Rate, len(r.Trials), r.Error)
+		} else {
+			t.logf("frame size %d: no rate passed after %d trials %s", size, len(r.Trials), r.Error)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// integerRate reports whether the rate parameter takes whole numbers.
+func (t *Throughput) integerRate() (bool, error) {
+	tm := &operations.TestModelOps{Client: t.Client}
+	if _, err := tm.Load(t.Model, false); err != nil {
+		return false, fmt.Errorf("load %s: %w", t.Model, err)
+	}
+	c, err := tm.Edit(t.Model).Component(t.Component)
+	if err != nil {
+		return false, err
+	}
+	def, err := c.Def.Param(t.RateParam)
+	if err != nil {
+		return false, err
+	}
+	return def.Integer(), nil
+}
+
+func (t *Throughput) search(ctx context.Context, size int, integer bool) (ThroughputResult, error) {
+	r := ThroughputResult{FrameSize: size}
+	lo, hi := t.Min, t.Max
+	if integer {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+		if hi < lo {
+			return r, fmt.Errorf("no whole rate between %v and %v", t.Min, t.Max)
+		}
+	}
+	run := func(rate float64) (bool, error) {
+		trial, err := t.trial(ctx, size, rate, len(r.Trials) == 0)
+		if err != nil {
+			return false, err
+		}
+		r.Trials = append(r.Trials, trial)
+		t.logf("frame size %d: rate %v loss %.4f%% pass=%v (run %d)", size, rate, trial.Loss, trial.Pass, trial.RunID)
+		if trial.Pass {
+			r.Rate = rate
+			r.Converged = true
+		}
+		return trial.Pass, nil
+	}
+	// Try the maximum first, a DUT running at line rate needs one trial
+	if pass, err := run(hi); err != nil || pass {
+		return r, err
+	}
+	// Then the minimum: when it fails too there is nothing to search. A
+	// zero minimum cannot be run and is taken as passing.
+	if lo > 0 && lo < hi {
+		if pass, err := run(lo); err != nil || !pass {
+			return r, err
+		}
+	}
+	for hi-lo > t.Resolution {
+		rate := lo + (hi-lo)/2
+		if integer {
+			rate = math.Floor(rate)
+		}
+		if rate <= lo || rate >= hi {
+			break
+		}
+		pass, err := run(rate)
+		if err != nil {
+			return r, err
+		}
+		if pass {
+			lo = rate
+		} else {
+			hi = rate
+		}
+	}
+	return r, nil
+}
+
+// trial runs one rate. The first trial of a frame size starts from Model,
+// later ones edit the copy the previous trial saved and left loaded.
+func (t *Throughput) trial(ctx context.Context, size int, rate float64, first bool) (Trial, error) {
+	trial := Trial{Rate: rate}
+	tm := &operations.TestModelOps{Client: t.Client}
+	model := sanitize(t.Model) + "_throughput"
+	edit := tm.Edit(model)
+	if first {
+		if _, err := tm.Load(t.Model, false); err != nil {
+			return trial, fmt.Errorf("load %s: %w", t.Model, err)
+		}
+		edit = tm.Edit(t.Model).SaveAs(model)
+	}
+	c, err := edit.Component(t.Component)
+	if err != nil {
+		return trial, err
+	}
+	if first && t.FrameSizeParam != "" && size > 0 {
+		if err := c.Set(t.FrameSizeParam, size); err != nil {
+			return trial, err
+		}
+	}
+	if err := c.Set(t.RateParam, rate); err != nil {
+		return trial, err
+	}
+	if err := edit.Commit(); err != nil {
+		return trial, err
+	}
+	trial.RunID, err = tm.RunAndWait(ctx, model, t.Group, false, t.PollInterval)
+	if err != nil {
+		return trial, err
+	}
+	if trial.Loss, err = t.Loss(trial.RunID); err != nil {
+		return trial, fmt.Errorf("run %d: %w", trial.RunID, err)
+	}
+	trial.Pass = trial.Loss <= t.MaxLoss
+	return trial, nil
+}
+
+func (t *Throughput) logf(format string, args ...interface{}) {
+	if t.Log != nil {
+		fmt.Fprintf(t.Log, format+"\n", args...)
+	}
+}
@@ -0,0 +1,160 @@
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeDUT serves a test model with one Routing Robot and runs it against a
+// device forwarding up to capacity without loss
+type fakeDUT struct {
+	rateType string
+	capacity float64
+	rate     float64
+	runs     map[int]float64
+	loads    int
+}
+
+func (d *fakeDUT) Get(path string, responseDepth *int, params map[string]string) (interface{}, error) {
+	switch {
+	case path == "/testmodel/component/rr_1":
+		return map[string]interface{}{"type": "routingrobot"}, nil
+	case strings.HasPrefix(path, "/topology/runningTest/"):
+		return map[string]interface{}{"completed": true, "progress": 100}, nil
+	}
+	return nil, fmt.Errorf("GET %s: not found", path)
+}
+
+func (d *fakeDUT) Post(path string, data interface{}) (interface{}, error) {
+	switch path {
+	case "/testmodel/operations/load":
+		d.loads++
+	case "/testmodel/operations/testComponentDefinition":
+		return map[string]interface{}{"parameters": []interface{}{
+			map[string]interface{}{"id": "rateDist", "parameters": []interface{}{
+				map[string]interface{}{"id": "min", "type": d.rateType},
+			}},
+			map[string]interface{}{"id": "sizeDist", "parameters": []interface{}{
+				map[string]interface{}{"id": "min", "type": "integer"},
+			}},
+		}}, nil
+	case "/testmodel/operations/run":
+		id := len(d.runs) + 1
+		d.runs[id] = d.rate
+		return map[string]interface{}{"runid": id}, nil
+	}
+	return nil, nil
+}
+
+func (d *fakeDUT) Put(path string, value interface{}) error { return nil }
+
+func (d *fakeDUT) Patch(path string, value interface{}) error {
+	patch := value.(map[string]interface{})
+	if rate, ok := patch["rateDist"].(map[string]interface{}); ok {
+		switch v := rate["min"].(type) {
+		case float64:
+			d.rate = v
+		case int64:
+			d.rate = float64(v)
+		}
+	}
+	return nil
+}
+
+func (d *fakeDUT) Delete(path string) (interface{}, error) { return nil, nil }
+func (d *fakeDUT) Export(path, filepath string, params map[string]interface{}) error {
+	return nil
+}
+func (d *fakeDUT) Import(path, filename string, params map[string]interface{}) (interface{}, error) {
+	return nil, nil
+}
+func (d *fakeDUT) EnableProfiling(enabled bool) {}
+func (d *fakeDUT) PrintVersions()               {}
+func (d *fakeDUT) PrintProfilingData()          {}
+
+func (d *fakeDUT) loss(runID int) (float64, error) {
+	if d.runs[runID] > d.capacity {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func TestThroughput(t *testing.T) {
+	tests := []struct {
+		name          string
+		rateType      string
+		capacity      float64
+		min, max, res float64
+		wantRate      float64
+		wantConverged bool
+		wantTrials    []float64
+	}{
+		{
+			name: "pass at max", rateType: "number", capacity: 100,
+			min: 10, max: 100, res: 1,
+			wantRate: 100, wantConverged: true, wantTrials: []float64{100},
+		},
+		{
+			name: "fail at min", rateType: "number", capacity: 5,
+			min: 10, max: 100, res: 1,
+			wantTrials: []float64{100, 10},
+		},
+		{
+			name: "nothing passes from zero", rateType: "number", capacity: -1,
+			min: 0, max: 8, res: 2,
+			wantTrials: []float64{8, 4, 2},
+		},
+		{
+			name: "integer rounding", rateType: "integer", capacity: 6,
+			min: 0.5, max: 10.5, res: 0.5,
+			wantRate: 6, wantConverged: true, wantTrials: []float64{10, 1, 5, 7, 6},
+		},
+		{
+			name: "resolution stop", rateType: "number", capacity: 70,
+			min: 0, max: 100, res: 20,
+			wantRate: 62.5, wantConverged: true, wantTrials: []float64{100, 50, 75, 62.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dut := &fakeDUT{rateType: tt.rateType, capacity: tt.capacity, runs: make(map[int]float64)}
+			search := &Throughput{
+				Client:         dut,
+				Model:          "rfc2544",
+				Component:      "rr_1",
+				RateParam:      "rateDist.min",
+				FrameSizeParam: "sizeDist.min",
+				FrameSizes:     []int{64},
+				Min:            tt.min,
+				Max:            tt.max,
+				Resolution:     tt.res,
+				Loss:           dut.loss,
+			}
+			results, err := search.Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := results[0]
+			if r.Error != "" {
+				t.Fatalf("Error = %s", r.Error)
+			}
+			var rates []float64
+			for _, trial := range r.Trials {
+				rates = append(rates, trial.Rate)
+			}
+			if !reflect.DeepEqual(rates, tt.wantTrials) {
+				t.Errorf("trials = %v, want %v", rates, tt.wantTrials)
+			}
+			if r.Rate != tt.wantRate || r.Converged != tt.wantConverged {
+				t.Errorf("Rate, Converged = %v, %v, want %v, %v", r.Rate, r.Converged, tt.wantRate, tt.wantConverged)
+			}
+			// one load to read the definition, one for the first trial
+			if dut.loads != 2 {
+				t.Errorf("model loaded %d times, want 2", dut.loads)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package trafficmix
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Application is the traffic one application accounted for in a
+// monitoring export.
+type Application struct {
+	Name  string
+	Bytes int64
+	Flows int64
+}
+
+// Column names accepted for each field, compared case-insensitively with
+// spaces, dashes and underscores removed. The short forms are those of
+// nfdump and IPFIX collector exports.
+var (
+	nameColumns  = []string{"application", "app", "appname", "applicationname", "appl", "name"}
+	bytesColumns = []string{"bytes", "octets", "ibyt", "inbytes", "totalbytes", "octetdeltacount", "volume"}
+	flowsColumns = []string{"flows", "fl", "sessions", "connections", "flowcount", "count"}
+)
+
+// ReadCSV reads application statistics from a CSV export with a header
+// row naming an application column and at least one of a bytes and a
+// flows column. Rows of the same application are added up, and the
+// applications are returned in the order they first appear.
+func ReadCSV(r io.Reader) ([]Application, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	name, bytes, flows := column(header, nameColumns), column(header, bytesColumns), column(header, flowsColumns)
+	if name < 0 {
+		return nil, fmt.Errorf("no application column in %q", header)
+	}
+	if bytes < 0 && flows < 0 {
+		return nil, fmt.Errorf("no bytes or flows column in %q", header)
+	}
+	var apps []Application
+	index := make(map[string]int)
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		app := strings.TrimSpace(cell(row, name))
+		if app == "" {
+			continue
+		}
+		line, _ := cr.FieldPos(name)
+		b, err := number(cell(row, bytes))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bytes: %w", line, err)
+		}
+		f, err := number(cell(row, flows))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: flows: %w", line, err)
+		}
+		i, ok := index[strings.ToLower(app)]
+		if !ok {
+			i = len(apps)
+			index[strings.ToLower(app)] = i
+			apps = append(apps, Application{Name: app})
+		}
+		apps[i].Bytes += b
+		apps[i].Flows += f
+	}
+	return apps, nil
+}
+
+// ReadCSVFile reads application statistics from the CSV file path.
+func ReadCSVFile(path string) ([]Application, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	apps, err := ReadCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return apps, nil
+}
+
+func column(header, names []string) int {
+	for _, want := range names {
+		for i, h := range header {
+			if normalizeColumn(h) == want {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func normalizeColumn(s string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+func cell(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+// number parses a count that may carry thousands separators or a K, M or
+// G suffix as written by some collectors.
+func number(s string) (int64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" {
+		return 0, nil
+	}
+	mult := 1.0
+	switch s[len(s)-1] {
+	case 'k', 'K':
+		mult = 1e3
+	case 'm', 'M':
+		mult = 1e6
+	case 'g', 'G':
+		mult = 1e9
+	}
+	if mult != 1 {
+		s = strings.TrimSpace(s[:len(s)-1])
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("negative count %s", s)
+	}
+	return int64(v * mult), nil
+}
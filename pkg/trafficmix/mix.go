@@ -0,0 +1,385 @@
+// Package trafficmix turns application statistics exported by monitoring
+// systems into app profiles: applications are mapped to superflows through
+// a mapping table or by fuzzy matching against the canned superflows, and
+// their bytes or flows become the profile weights.
+package trafficmix
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"bps-client-go/pkg/models"
+	"bps-client-go/pkg/operations"
+)
+
+// DefaultMinScore is the lowest fuzzy match score accepted.
+const DefaultMinScore = 0.6
+
+// ProfileTotal is the sum of the weights of a generated profile.
+const ProfileTotal = 100
+
+// Mapping maps application names to superflows. Keys are compared
+// case-insensitively and may contain shell wildcards, e.g. "office365*";
+// exact keys win over patterns, a key of the same case as the application
+// over other spellings of it and longer patterns over shorter ones.
+type Mapping map[string]string
+
+// Lookup returns the superflow app maps to. Keys that differ only in case,
+// and patterns of the same length, are tried in sorted order so the result
+// does not depend on map iteration.
+func (m Mapping) Lookup(app string) (string, bool) {
+	if v, ok := m[app]; ok {
+		return v, true
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lower := strings.ToLower(app)
+	for _, k := range keys {
+		if strings.ToLower(k) == lower {
+			return m[k], true
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	for _, k := range keys {
+		if ok, _ := path.Match(strings.ToLower(k), lower); ok {
+			return m[k], true
+		}
+	}
+	return "", false
+}
+
+// LoadMapping reads a mapping table from a CSV file with application and
+// superflow columns. A header row and # comments are allowed.
+func LoadMapping(file string) (Mapping, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := ReadMapping(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return m, nil
+}
+
+// ReadMapping reads a mapping table in the form LoadMapping accepts.
+func ReadMapping(r io.Reader) (Mapping, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	m := make(Mapping)
+	for i, row := range rows {
+		if i == 0 && normalizeColumn(row[0]) == "application" && normalizeColumn(row[1]) == "superflow" {
+			continue
+		}
+		m[strings.TrimSpace(row[0])] = strings.TrimSpace(row[1])
+	}
+	return m, nil
+}
+
+// Match is an application and the superflow chosen for it.
+type Match struct {
+	Application Application
+	Superflow   string
+	// Score is 1 for mapped applications and the similarity of the names
+	// for fuzzy matches.
+	Score  float64
+	Mapped bool
+}
+
+// Mix is the outcome of matching a set of applications.
+type Mix struct {
+	Matches  []Match
+	Unmapped []Application
+}
+
+// Coverage returns the share of the bytes, or the flows with
+// models.WeightFlows, carried by matched applications.
+func (m *Mix) Coverage(weightType string) float64 {
+	var matched, total int64
+	for _, x := range m.Matches {
+		matched += measure(x.Application, weightType)
+	}
+	total = matched
+	for _, a := range m.Unmapped {
+		total += measure(a, weightType)
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(matched) / float64(total)
+}
+
+// String lists the matches and the unmapped applications.
+func (m *Mix) String() string {
+	var b strings.Builder
+	for _, x := range m.Matches {
+		how := "mapped"
+		if !x.Mapped {
+			how = fmt.Sprintf("fuzzy %.2f", x.Score)
+		}
+		fmt.Fprintf(&b, "%s -> %s (%s)\n", x.Application.Name, x.Superflow, how)
+	}
+	for _, a := range m.Unmapped {
+		fmt.Fprintf(&b, "%s: UNMAPPED (%d bytes, %d flows)\n", a.Name, a.Bytes, a.Flows)
+	}
+	return b.String()
+}
+
+// Converter maps applications to superflows and builds app profiles.
+type Converter struct {
+	Superflows *operations.SuperflowOps
+	Mapping    Mapping
+	// MinScore defaults to DefaultMinScore.
+	MinScore float64
+	// WeightType selects whether bytes (models.WeightBandwidth, the
+	// default) or flows (models.WeightFlows) become the weights.
+	WeightType string
+
+	canned []string
+}
+
+// Match maps every application to a superflow, through the mapping table
+// first and by fuzzy matching otherwise. Applications without a match
+// good enough end up in Unmapped.
+func (c *Converter) Match(apps []Application) (*Mix, error) {
+	mix := &Mix{}
+	for _, a := range apps {
+		if sf, ok := c.Mapping.Lookup(a.Name); ok {
+			mix.Matches = append(mix.Matches, Match{Application: a, Superflow: sf, Score: 1, Mapped: true})
+			continue
+		}
+		sf, score, err := c.fuzzy(a.Name)
+		if err != nil {
+			return nil, err
+		}
+		if sf == "" {
+			mix.Unmapped = append(mix.Unmapped, a)
+			continue
+		}
+		mix.Matches = append(mix.Matches, Match{Application: a, Superflow: sf, Score: score})
+	}
+	return mix, nil
+}
+
+// Profile builds an app profile named name from mix. Applications mapped
+// to the same superflow add up; weights are shared out of ProfileTotal.
+func (c *Converter) Profile(name string, mix *Mix) (*models.AppProfile, error) {
+	weightType := c.weightType()
+	totals := make(map[string]int64)
+	var order []string
+	for _, x := range mix.Matches {
+		if _, ok := totals[x.Superflow]; !ok {
+			order = append(order, x.Superflow)
+		}
+		totals[x.Superflow] += measure(x.Application, weightType)
+	}
+	weights := make([]float64, 0, len(order))
+	kept := order[:0]
+	for _, sf := range order {
+		if totals[sf] > 0 {
+			kept = append(kept, sf)
+			weights = append(weights, float64(totals[sf]))
+		}
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("app profile %s: no matched traffic", name)
+	}
+	if len(kept) > ProfileTotal {
+		return nil, fmt.Errorf("app profile %s: %d superflows do not fit weights out of %d", name, len(kept), ProfileTotal)
+	}
+	p := models.NewAppProfile(name, weightType)
+	for i, w := range models.Apportion(weights, ProfileTotal) {
+		p.Set(kept[i], w)
+	}
+	return p, nil
+}
+
+// Import matches apps, builds the profile and creates it through
+// profiles. The mix is returned even when creating the profile fails so
+// the unmapped applications can be reported.
+func (c *Converter) Import(profiles *operations.AppProfileOps, name string, apps []Application, force bool) (*Mix, error) {
+	mix, err := c.Match(apps)
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.Profile(name, mix)
+	if err != nil {
+		return mix, err
+	}
+	return mix, profiles.Create(p, force)
+}
+
+func (c *Converter) weightType() string {
+	if c.WeightType == "" {
+		return models.WeightBandwidth
+	}
+	return c.WeightType
+}
+
+// fuzzy returns the candidate superflow closest to app, or "" when none
+// reaches MinScore. Candidates are the canned superflows and the results
+// of searching for the words of the name.
+func (c *Converter) fuzzy(app string) (string, float64, error) {
+	if c.canned == nil {
+		canned, err := c.Superflows.CannedFlows()
+		if err != nil {
+			return "", 0, err
+		}
+		c.canned = append([]string{}, canned...)
+	}
+	candidates := append([]string{}, c.canned...)
+	for _, word := range words(app) {
+		if len(word) < 3 {
+			continue
+		}
+		resp, err := c.Superflows.Search(word, 100, "name", "ascending")
+		if err != nil {
+			return "", 0, fmt.Errorf("search superflows for %s: %w", app, err)
+		}
+		items, err := operations.SearchItems(resp)
+		if err != nil {
+			return "", 0, fmt.Errorf("search superflows for %s: %w", app, err)
+		}
+		for _, item := range items {
+			candidates = append(candidates, item.Name)
+		}
+	}
+	min := c.MinScore
+	if min == 0 {
+		min = DefaultMinScore
+	}
+	best, bestScore := "", 0.0
+	for _, cand := range candidates {
+		if s := Similarity(app, cand); s > bestScore || (s == bestScore && len(cand) < len(best)) {
+			best, bestScore = cand, s
+		}
+	}
+	if bestScore < min {
+		return "", bestScore, nil
+	}
+	return best, bestScore, nil
+}
+
+// Similarity scores how alike an application name and a superflow name
+// are, from 0 to 1. Shared words count most; names without shared words
+// fall back to the edit distance of their letters.
+func Similarity(app, superflow string) float64 {
+	a, b := words(app), words(superflow)
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inB := make(map[string]bool, len(b))
+	for _, w := range b {
+		inB[w] = true
+	}
+	common := 0
+	for _, w := range a {
+		if inB[w] {
+			common++
+		}
+	}
+	if common > 0 {
+		// Dice coefficient, favouring superflows that contain every word
+		// of the application.
+		score := 2 * float64(common) / float64(len(a)+len(b))
+		if common == len(a) {
+			score = (score + 1) / 2
+		}
+		return score
+	}
+	// Misspelt names: compare the whole application name with the whole
+	// superflow name and with each of its words.
+	x := strings.Join(a, "")
+	best := editSimilarity(x, strings.Join(b, ""))
+	for _, w := range b {
+		if s := editSimilarity(x, w); s > best {
+			best = s
+		}
+	}
+	return 0.8 * best
+}
+
+func editSimilarity(x, y string) float64 {
+	longest := len([]rune(x))
+	if n := len([]rune(y)); n > longest {
+		longest = n
+	}
+	return 1 - float64(levenshtein(x, y))/float64(longest)
+}
+
+// words splits a name into lower case words at punctuation, spaces and
+// letter/digit boundaries.
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = cur[:0]
+		}
+	}
+	for i, r := range strings.ToLower(s) {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case i > 0 && len(cur) > 0 && unicode.IsDigit(r) != unicode.IsDigit(cur[len(cur)-1]):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return out
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func measure(a Application, weightType string) int64 {
+	if weightType == models.WeightFlows {
+		return a.Flows
+	}
+	return a.Bytes
+}
@@ -0,0 +1,114 @@
+package trafficmix
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMappingLookup(t *testing.T) {
+	m := Mapping{
+		"Netflix":    "Netflix Streaming",
+		"NETFLIX":    "Netflix Upper",
+		"netflix":    "Netflix Lower",
+		"office365*": "Office 365",
+		"office*":    "Office Generic",
+		"Zoom":       "Zoom Meeting",
+	}
+	tests := []struct {
+		app    string
+		want   string
+		wantOK bool
+	}{
+		{"Netflix", "Netflix Streaming", true},
+		{"NETFLIX", "Netflix Upper", true},
+		{"netflix", "Netflix Lower", true},
+		{"NetFlix", "Netflix Upper", true},
+		{"zoom", "Zoom Meeting", true},
+		{"Office365 Outlook", "Office 365", true},
+		{"Office Online", "Office Generic", true},
+		{"Teams", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.app, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got, ok := m.Lookup(tt.app)
+				if got != tt.want || ok != tt.wantOK {
+					t.Fatalf("Lookup(%s) = %s, %v, want %s, %v", tt.app, got, ok, tt.want, tt.wantOK)
+				}
+			}
+		})
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []Application
+		wantErr string
+	}{
+		{
+			name: "nfdump columns",
+			in:   "Appl,ibyt,fl\nHTTP,1000,10\nDNS,2K,5\n",
+			want: []Application{{"HTTP", 1000, 10}, {"DNS", 2000, 5}},
+		},
+		{
+			name: "rows of one application add up",
+			in:   "Application Name,Total Bytes\nYouTube,\"1,500\"\nyoutube,500\n",
+			want: []Application{{Name: "YouTube", Bytes: 2000}},
+		},
+		{
+			name:    "no application column",
+			in:      "host,bytes\na,1\n",
+			wantErr: "no application column",
+		},
+		{
+			name:    "bad count after a comment and a quoted line break",
+			in:      "app,bytes\n# exported\n\"multi\nline\",1\nDNS,lots\n",
+			wantErr: "line 5: bytes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadCSV(strings.NewReader(tt.in))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadCSV() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadCSV() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		app, better, worse string
+	}{
+		{"Netflix", "Netflix", "Netflix Video Streaming"},
+		{"Office 365", "Microsoft Office 365", "Office Depot"},
+		{"Youtub", "YouTube", "Yahoo Mail"},
+		{"Skype", "Skype 7 Call", "Facebook Messenger"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.app, func(t *testing.T) {
+			b, w := Similarity(tt.app, tt.better), Similarity(tt.app, tt.worse)
+			if b <= w {
+				t.Errorf("Similarity(%s) with %s = %.2f, not above %.2f with %s", tt.app, tt.better, b, w, tt.worse)
+			}
+			if b < 0 || b > 1 || w < 0 || w > 1 {
+				t.Errorf("Similarity(%s) out of range: %.2f, %.2f", tt.app, b, w)
+			}
+		})
+	}
+	if s := Similarity("Netflix", "netflix"); s != 1 {
+		t.Errorf("Similarity of equal names = %.2f, want 1", s)
+	}
+}